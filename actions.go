@@ -40,6 +40,34 @@ func (m Action) WasPlaced() bool {
 func (m Action) WasMoved() bool {
 	return m.Act() == Moved
 }
+func (m Action) WasThrown() bool {
+	return m.Act() == Thrown
+}
+func (m Action) WasPassed() bool {
+	return m.Act() == Passed
+}
+func (m Action) WasResigned() bool {
+	return m.Act() == Resigned
+}
+func (m Action) WasDrawn() bool {
+	return m.Act() == Drawn
+}
+func (m Action) WasAdjudicated() bool {
+	return m.Act() == Adjudicated
+}
+func (m Action) WasTimedOut() bool {
+	return m.Act() == TimedOut
+}
+
+// Concludes returns true for the actions that end a game without touching the board. The piece of these actions only
+// carries a color, the player that resigned or ran out of time, or the winner of an adjudication.
+func (m Action) Concludes() bool {
+	switch m.Act() {
+	case Resigned, Drawn, Adjudicated, TimedOut:
+		return true
+	}
+	return false
+}
 func (m Action) Act() uint8 {
 	return uint8(uint64(m.act) & ActMask >> 24)
 }
@@ -53,6 +81,12 @@ func (m Action) String() string {
 const (
 	Placed uint8 = iota
 	Moved
+	Passed
+	Thrown
+	Resigned
+	Drawn
+	Adjudicated
+	TimedOut
 
 	ActMask = 0b11111111000000000000000000000000
 	DstMask = 0b0000000000000000000000000000000011111111111111111111111111111111
@@ -61,4 +95,10 @@ const (
 var actLabels = []string{
 	"Placed",
 	"Moved",
+	"Passed",
+	"Thrown",
+	"Resigned",
+	"Drawn",
+	"Adjudicated",
+	"TimedOut",
 }
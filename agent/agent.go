@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/ai"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/uhp"
+)
+
+// Agent chooses the actions of a player.
+type Agent interface {
+	// Next returns the action to play for the player whose turn it is. The game is a copy the agent may keep or act
+	// on. In a timed game the context is done when the player runs out of time.
+	Next(ctx context.Context, g *game.Game) (hive.Action, error)
+}
+
+// Func adapts a function to an Agent.
+type Func func(ctx context.Context, g *game.Game) (hive.Action, error)
+
+func (f Func) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	return f(ctx, g)
+}
+
+// Random plays a legal action picked at random. It's not safe for concurrent use, give each game its own.
+type Random struct {
+	rnd *rand.Rand
+}
+
+// NewRandom returns a random agent, the same seed always picks the same actions.
+func NewRandom(seed int64) *Random {
+	return &Random{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (r *Random) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	legal := g.LegalActions()
+	if len(legal) == 0 {
+		return hive.Action{}, game.ErrGameOver
+	}
+	return legal[r.rnd.Intn(len(legal))], nil
+}
+
+// Greedy plays the action that leaves the best position according to ai.Evaluate without looking any further ahead.
+// A winning action is always taken and a losing one avoided when there's another choice. Ties go to the first of the
+// legal actions.
+type Greedy struct{}
+
+func (Greedy) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	legal := g.LegalActions()
+	if len(legal) == 0 {
+		return hive.Action{}, game.ErrGameOver
+	}
+
+	us := g.Turn()
+	best, bestScore := legal[0], 0
+	for i, a := range legal {
+		next := g.Clone()
+		if err := next.Play(a); err != nil {
+			return hive.Action{}, fmt.Errorf("legal action %s was rejected: %w", a, err)
+		}
+
+		// the evaluation is from the perspective of the opponent who is to move next
+		score := -ai.Evaluate(next)
+		if w, err := next.Winner(); err == nil {
+			switch {
+			case w == game.Tie:
+				score = 0
+			case uint8(w) == us:
+				return a, nil
+			default:
+				score = -winScore
+			}
+		}
+		if i == 0 || score > bestScore {
+			best, bestScore = a, score
+		}
+	}
+	return best, nil
+}
+
+// Search plays the best action ai.Search finds.
+type Search struct {
+	// Options configures the search. When neither a depth nor a move time is set the search is limited to
+	// DefaultDepth, unless the game is timed.
+	Options ai.Options
+
+	// MoveTime limits the time spent on each action.
+	MoveTime time.Duration
+}
+
+func (s *Search) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	opts := s.Options
+	budget := s.MoveTime
+	if t := ThinkTime(g); t > 0 && (budget == 0 || t < budget) {
+		budget = t
+	}
+	if budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	} else if opts.Depth == 0 {
+		opts.Depth = DefaultDepth
+	}
+
+	r, err := ai.Search(ctx, g, opts)
+	return r.Move, err
+}
+
+// Engine plays the actions of an external engine that speaks the Universal Hive Protocol.
+type Engine struct {
+	Engine *uhp.Engine
+
+	// Limit is passed to the engine for every action. In a timed game with no depth set the engine is given the
+	// ThinkTime instead, when that's shorter.
+	Limit uhp.Limit
+}
+
+func (e *Engine) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	limit := e.Limit
+	if t := ThinkTime(g); limit.Depth <= 0 && t > 0 && (limit.Time == 0 || t < limit.Time) {
+		limit.Time = t
+	}
+	return e.Engine.BestMove(ctx, g, limit)
+}
+
+// Remote plays the actions it's handed by Submit, from a person at a terminal or a client over the network for
+// example.
+type Remote struct {
+	actions chan hive.Action
+}
+
+// NewRemote returns a remote agent that waits for its actions to be submitted.
+func NewRemote() *Remote {
+	return &Remote{actions: make(chan hive.Action)}
+}
+
+// Submit hands the action to the agent. It waits until the agent is asked for its next action or the context is
+// done.
+func (r *Remote) Submit(ctx context.Context, a hive.Action) error {
+	select {
+	case r.actions <- a:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Remote) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	select {
+	case a := <-r.actions:
+		return a, nil
+	case <-ctx.Done():
+		return hive.Action{}, ctx.Err()
+	}
+}
+
+// ThinkTime returns how long the player whose turn it is should think about their action in a timed game, a small
+// share of the time they have left plus what the time control gives back for the action. It returns zero when the
+// game isn't timed.
+func ThinkTime(g *game.Game) time.Duration {
+	cs, ok := g.Clock()
+	if !ok {
+		return 0
+	}
+	left := cs.Remaining(g.Turn())
+	t := left/movesToGo + cs.Control.Increment + cs.Control.Delay
+	if t > left/2 {
+		t = left / 2
+	}
+	return t
+}
+
+const (
+	// DefaultDepth limits a Search that was given no other limit.
+	DefaultDepth = 3
+
+	// movesToGo is the number of actions ThinkTime expects a player still has to make.
+	movesToGo = 30
+
+	// winScore is how Greedy scores a position that's lost, it's beyond any evaluation.
+	winScore = 1 << 20
+)
@@ -0,0 +1,140 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/ai"
+	"github.com/theshadow/hive/game"
+)
+
+func TestRandom(t *testing.T) {
+	t.Run("When two agents share a seed they pick the same actions", func(t *testing.T) {
+		a, b := NewRandom(7), NewRandom(7)
+		g := game.New(nil)
+		for i := 0; i < 10; i++ {
+			x, err := a.Next(context.Background(), g)
+			if err != nil {
+				t.Fatalf("Unexpected error %#v while picking an action", err)
+			}
+			if y, _ := b.Next(context.Background(), g); x != y {
+				t.Fatalf("Expected both agents to pick %s instead received %s", x, y)
+			}
+			if err := g.Play(x); err != nil {
+				t.Fatalf("Unexpected error %#v while playing %s", err, x)
+			}
+		}
+	})
+}
+
+func TestGreedy(t *testing.T) {
+	t.Run("When an action wins the game it is taken", func(t *testing.T) {
+		found := 0
+		for seed := int64(0); seed < 20 && found < 2; seed++ {
+			r, g := NewRandom(seed), game.New(nil)
+			for turn := 0; turn < 200 && !g.Over(); turn++ {
+				if wins(g) {
+					found++
+					a, err := Greedy{}.Next(context.Background(), g)
+					if err != nil {
+						t.Fatalf("Unexpected error %#v while picking an action", err)
+					}
+					if !winsWith(g, a) {
+						t.Errorf("Expected a winning action instead received %s", a)
+					}
+					break
+				}
+				a, _ := r.Next(context.Background(), g)
+				if err := g.Play(a); err != nil {
+					t.Fatalf("Unexpected error %#v while playing %s", err, a)
+				}
+			}
+		}
+		if found == 0 {
+			t.Fatalf("Expected the random games to reach a winning position")
+		}
+	})
+
+	t.Run("When the game is over there is no action", func(t *testing.T) {
+		g := game.New(nil)
+		if err := g.Resign(hive.WhiteColor); err != nil {
+			t.Fatalf("Unexpected error %#v while resigning", err)
+		}
+		if _, err := (Greedy{}).Next(context.Background(), g); err != game.ErrGameOver {
+			t.Errorf("Expected ErrGameOver instead received %#v", err)
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("When the search has a move time it answers in time", func(t *testing.T) {
+		s := &Search{Options: ai.Options{Workers: 1}, MoveTime: 20 * time.Millisecond}
+		start := time.Now()
+		a, err := s.Next(context.Background(), game.New(nil))
+		if err != nil || !a.WasPlaced() {
+			t.Fatalf("Expected a placement instead received %s and %#v", a, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected an answer within the move time instead it took %s", elapsed)
+		}
+	})
+}
+
+func TestRemote(t *testing.T) {
+	t.Run("When an action is submitted it is returned", func(t *testing.T) {
+		r := NewRemote()
+		g := game.New(nil)
+		want := g.LegalActions()[0]
+		go r.Submit(context.Background(), want)
+		if a, err := r.Next(context.Background(), g); err != nil || a != want {
+			t.Errorf("Expected %s instead received %s and %#v", want, a, err)
+		}
+	})
+
+	t.Run("When nothing is submitted the context ends the wait", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := NewRemote().Next(ctx, game.New(nil)); err != context.DeadlineExceeded {
+			t.Errorf("Expected context.DeadlineExceeded instead received %#v", err)
+		}
+	})
+}
+
+func TestThinkTime(t *testing.T) {
+	t.Run("When the game is untimed there is no think time", func(t *testing.T) {
+		if d := ThinkTime(game.New(nil)); d != 0 {
+			t.Errorf("Expected no think time instead received %s", d)
+		}
+	})
+
+	t.Run("When the game is timed a share of the clock and the increment is used", func(t *testing.T) {
+		g := game.New(nil)
+		if err := g.StartClock(game.Fischer(30*time.Second, 2*time.Second), game.NewManualClock(time.Now())); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		if d := ThinkTime(g); d != 3*time.Second {
+			t.Errorf("Expected 3s instead received %s", d)
+		}
+	})
+}
+
+// wins returns true when the player whose turn it is has a winning action.
+func wins(g *game.Game) bool {
+	for _, a := range g.LegalActions() {
+		if winsWith(g, a) {
+			return true
+		}
+	}
+	return false
+}
+
+func winsWith(g *game.Game, a hive.Action) bool {
+	next := g.Clone()
+	if err := next.Play(a); err != nil {
+		return false
+	}
+	w, err := next.Winner()
+	return err == nil && uint8(w) == g.Turn()
+}
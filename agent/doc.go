@@ -0,0 +1,30 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package agent plugs players into games, so that an application doesn't have to write its own turn loop.
+
+Agents
+
+An Agent returns the action to play for the player whose turn it is, it may be a person, a computer player, or a
+player on the other side of a network connection. The package comes with a few:
+
+	Random   plays a random legal action, the weakest possible opponent
+	Greedy   plays the action that leaves the best position, without looking ahead
+	Search   plays the best action ai.Search finds
+	Engine   plays the actions of an external engine, see the uhp package
+	Remote   plays the actions handed to it, by a person or a network client
+
+Func turns a plain function into an agent.
+
+Referee
+
+A Referee drives a game between two agents until the game is over and returns the Verdict. It's the referee that
+enforces the rules, an illegal action is never played. Instead the agent forfeits the game, unless it has strikes to
+spare, and the game is adjudicated to its opponent. In a timed game an agent is given until its clock runs out to
+answer and loses on time when it doesn't.
+
+	r := &agent.Referee{White: agent.Greedy{}, Black: &agent.Search{MoveTime: time.Second}, MaxTurns: 200}
+	v, err := r.Play(ctx, game.New(nil))
+*/
+package agent
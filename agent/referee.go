@@ -0,0 +1,141 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// Referee drives a game between two agents. It asks the agent whose turn it is for an action and plays it, until the
+// game is over.
+//
+// An agent that returns an error, or more illegal actions than it's allowed, forfeits the game which is adjudicated
+// to its opponent. In a timed game an agent has until its clock runs out to answer, once it does the game is lost on
+// time.
+type Referee struct {
+	White, Black Agent
+
+	// MaxTurns ends a game that reaches the turn in an adjudicated draw, zero doesn't limit the game.
+	MaxTurns int
+
+	// Strikes is the number of illegal actions an agent may return in a game, it forfeits on the next one. A person
+	// who mistypes a move may deserve a few, an engine none.
+	Strikes int
+
+	// OnAction is called after each action that's played, and with the error for each illegal action.
+	OnAction func(g *game.Game, a hive.Action, err error)
+}
+
+// Verdict is how a game the referee played ended.
+type Verdict struct {
+	Result game.Result
+
+	// Forfeited is the color of the agent that forfeited the game, NoColor when neither did.
+	Forfeited uint8
+
+	// Err is the error or the last illegal action that cost the agent the game.
+	Err error
+
+	// TurnLimit is true when the game was drawn at the turn limit.
+	TurnLimit bool
+}
+
+// Termination explains a result the referee adjudicated, it's empty when the game ended on its own.
+func (v Verdict) Termination() string {
+	switch {
+	case v.Forfeited == hive.WhiteColor:
+		return fmt.Sprintf("White forfeited: %s", v.Err)
+	case v.Forfeited == hive.BlackColor:
+		return fmt.Sprintf("Black forfeited: %s", v.Err)
+	case v.TurnLimit:
+		return "the game reached the turn limit"
+	}
+	return ""
+}
+
+func (v Verdict) String() string {
+	if t := v.Termination(); t != "" {
+		return v.Result.String() + ", " + t
+	}
+	return v.Result.String()
+}
+
+// Play drives the game until it's over and returns the verdict. The game may already be under way, a clock included.
+// The only error returned is that of the context, when it's done before the game is over.
+func (r *Referee) Play(ctx context.Context, g *game.Game) (Verdict, error) {
+	var strikes [2]int
+	v := Verdict{Forfeited: hive.NoColor}
+	for !g.Over() {
+		if r.MaxTurns > 0 && g.Turns() > uint(r.MaxTurns) {
+			v.TurnLimit = true
+			if err := g.Adjudicate(game.Tie); err != nil {
+				return v, err
+			}
+			break
+		}
+
+		color := g.Turn()
+		a, err := next(ctx, r.agent(color), g)
+		if ctx.Err() != nil {
+			return v, ctx.Err()
+		}
+		if err == nil {
+			if err = g.Play(a); err == nil {
+				r.observe(g, a, nil)
+				continue
+			}
+			r.observe(g, a, err)
+			if g.Over() {
+				// the clock ran out before the action, the game has recorded the timeout
+				continue
+			}
+			if strikes[color-1] < r.Strikes {
+				strikes[color-1]++
+				continue
+			}
+			err = fmt.Errorf("%w %s: %s", ErrIllegalAction, a, err)
+		} else if g.ClaimTimeout() == nil {
+			continue
+		}
+
+		v.Forfeited, v.Err = color, err
+		winner := game.WhitePlayer
+		if color == hive.WhiteColor {
+			winner = game.BlackPlayer
+		}
+		if err := g.Adjudicate(winner); err != nil {
+			return v, err
+		}
+	}
+
+	res, err := g.Result()
+	v.Result = res
+	return v, err
+}
+
+func (r *Referee) agent(color uint8) Agent {
+	if color == hive.WhiteColor {
+		return r.White
+	}
+	return r.Black
+}
+
+func (r *Referee) observe(g *game.Game, a hive.Action, err error) {
+	if r.OnAction != nil {
+		r.OnAction(g, a, err)
+	}
+}
+
+// next asks the agent for its action, in a timed game the agent is given until its time runs out.
+func next(ctx context.Context, a Agent, g *game.Game) (hive.Action, error) {
+	if cs, ok := g.Clock(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cs.Remaining(g.Turn()))
+		defer cancel()
+	}
+	return a.Next(ctx, g.Clone())
+}
+
+var ErrIllegalAction = fmt.Errorf("the agent returned an illegal action")
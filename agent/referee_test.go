@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+func TestReferee(t *testing.T) {
+	t.Run("When the agents play legal actions the game is played to the end", func(t *testing.T) {
+		var played int
+		r := &Referee{
+			White:    NewRandom(1),
+			Black:    Greedy{},
+			MaxTurns: 100,
+			OnAction: func(g *game.Game, a hive.Action, err error) {
+				if err != nil {
+					t.Errorf("Unexpected illegal action %s: %s", a, err)
+				}
+				played++
+			},
+		}
+		g := game.New(nil)
+		v, err := r.Play(context.Background(), g)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while playing", err)
+		}
+		if !g.Over() || v.Forfeited != hive.NoColor || played != len(g.History())-concluding(g) {
+			t.Errorf("Expected the game to be played out instead received %s after %d actions", v, played)
+		}
+	})
+
+	t.Run("When the game reaches the turn limit it is drawn", func(t *testing.T) {
+		r := &Referee{White: NewRandom(1), Black: NewRandom(2), MaxTurns: 3}
+		v, err := r.Play(context.Background(), game.New(nil))
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while playing", err)
+		}
+		if !v.TurnLimit || v.Result.Winner != game.Tie || v.Result.Reason != game.Adjudication {
+			t.Errorf("Expected a draw at the turn limit instead received %s", v)
+		}
+	})
+
+	t.Run("When an agent returns an error it forfeits", func(t *testing.T) {
+		broken := Func(func(ctx context.Context, g *game.Game) (hive.Action, error) {
+			return hive.Action{}, ErrForTest
+		})
+		v, err := (&Referee{White: Greedy{}, Black: broken}).Play(context.Background(), game.New(nil))
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while playing", err)
+		}
+		if v.Forfeited != hive.BlackColor || !errors.Is(v.Err, ErrForTest) || v.Result.Winner != game.WhitePlayer {
+			t.Errorf("Expected black to forfeit instead received %s", v)
+		}
+		if !strings.HasPrefix(v.Termination(), "Black forfeited") {
+			t.Errorf("Expected the termination to name black instead received %q", v.Termination())
+		}
+	})
+
+	t.Run("When an agent returns illegal actions it forfeits once out of strikes", func(t *testing.T) {
+		var asked, illegal int
+		cheat := Func(func(ctx context.Context, g *game.Game) (hive.Action, error) {
+			asked++
+			return hive.NewAction(hive.Moved, hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin, hive.Origin), nil
+		})
+		r := &Referee{
+			White:    cheat,
+			Black:    Greedy{},
+			Strikes:  2,
+			OnAction: func(g *game.Game, a hive.Action, err error) { illegal++ },
+		}
+		v, err := r.Play(context.Background(), game.New(nil))
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while playing", err)
+		}
+		if asked != 3 || illegal != 3 || v.Forfeited != hive.WhiteColor || !errors.Is(v.Err, ErrIllegalAction) {
+			t.Errorf("Expected white to forfeit on the third illegal action instead received %s after %d", v, asked)
+		}
+	})
+
+	t.Run("When an agent runs out of time it loses on time", func(t *testing.T) {
+		g := game.New(nil)
+		if err := g.StartClock(game.SuddenDeath(20*time.Millisecond), game.SystemClock{}); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		v, err := (&Referee{White: NewRemote(), Black: Greedy{}}).Play(context.Background(), g)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while playing", err)
+		}
+		if v.Result.Winner != game.BlackPlayer || v.Result.Reason != game.Timeout || v.Termination() != "" {
+			t.Errorf("Expected white to lose on time instead received %s", v)
+		}
+	})
+
+	t.Run("When the context is done the game is left unfinished", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		g := game.New(nil)
+		if _, err := (&Referee{White: NewRemote(), Black: NewRemote()}).Play(ctx, g); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled instead received %#v", err)
+		}
+		if g.Over() {
+			t.Errorf("Expected the game to be left unfinished")
+		}
+	})
+}
+
+// concluding counts the actions of the history that ended the game without touching the board.
+func concluding(g *game.Game) int {
+	var n int
+	for _, a := range g.History() {
+		if a.Concludes() {
+			n++
+		}
+	}
+	return n
+}
+
+var ErrForTest = fmt.Errorf("the agent failed")
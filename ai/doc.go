@@ -0,0 +1,20 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package ai contains the building blocks for computer players that sit on top of the game engine.
+
+Transposition Table
+
+In Hive the same position is reached over and over again through different move orders. The
+TranspositionTable remembers the result of searching a position, keyed by Game.Hash, so that a
+search can skip work it has already done. The table is sized in megabytes and is safe to share
+between several search goroutines.
+
+Search
+
+Search is a Lazy SMP alpha-beta search. It runs several workers over their own copies of the
+game that share a single TranspositionTable. The search stops cooperatively when its context
+is done and the results of the workers are merged deterministically.
+*/
+package ai
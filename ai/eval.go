@@ -0,0 +1,46 @@
+package ai
+
+import (
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// Evaluate returns a static score of the position from the perspective of the player whose turn it is. Positive
+// scores favor that player.
+//
+// Hive is won by surrounding the opponents queen, so the evaluation is mostly a count of how many pieces surround each
+// queen. Having placed your queen is worth a little on its own as it's what lets your pieces move.
+func Evaluate(g *game.Game) int {
+	us := g.Turn()
+	var them uint8 = hive.BlackColor
+	if us == hive.BlackColor {
+		them = hive.WhiteColor
+	}
+	return queenScore(g, them) - queenScore(g, us)
+}
+
+// queenScore returns how much trouble the queen of the specified color is in.
+func queenScore(g *game.Game, color uint8) int {
+	c, placed := g.Queen(color)
+	if !placed {
+		return unplacedQueenPenalty
+	}
+
+	var score int
+	neighbors := g.Neighbors(c)
+	for _, p := range neighbors[:hive.Above] {
+		if p != hive.ZeroPiece {
+			score += surroundedValue
+		}
+	}
+	return score
+}
+
+const (
+	surroundedValue      = 100
+	unplacedQueenPenalty = 50
+
+	// winScore is the score of a won position, it's larger than anything Evaluate can return. Wins found closer to
+	// the root score higher so the search prefers the fastest win.
+	winScore = 1000000
+)
@@ -0,0 +1,347 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// Options configures a Search.
+type Options struct {
+	// Workers is the number of goroutines to search with, defaults to the number of CPUs.
+	Workers int
+
+	// Depth limits how many plies deep the search will go. When zero the search keeps deepening until the context
+	// is done or MaxDepth is reached, so either Depth or a context deadline should be set.
+	Depth int
+
+	// Seed feeds the move ordering of the helper workers. With the same Seed and Depth the search will always return
+	// the same result.
+	Seed int64
+
+	// Table is shared by all of the workers. It may be reused between searches to keep what was learned, when nil a
+	// table of DefaultTableSize megabytes is created for the search.
+	Table *TranspositionTable
+}
+
+// Result is the outcome of a Search.
+type Result struct {
+	// Move is the best action that was found for the player whose turn it is.
+	Move hive.Action
+
+	// Score of the Move from the perspective of the player whose turn it is.
+	Score int
+
+	// Depth is the deepest iteration that was completed. A zero depth means the search was stopped before it could
+	// finish the first iteration and Move is only the first legal action.
+	Depth int
+
+	// Nodes is the number of positions that were visited by all of the workers.
+	Nodes uint64
+
+	// the error that stopped a worker, see play
+	err error
+}
+
+// Search looks for the best action for the player whose turn it is.
+//
+// The search is a Lazy SMP search. Every worker runs its own iterative deepening alpha-beta search over its own copy of
+// the game and the only thing they share is the transposition table. The workers naturally drift apart, helpers
+// start at alternating depths and shuffle their move ordering, so they end up filling the table with results the
+// others can use.
+//
+// The search stops when every worker has finished the requested depth or when the context is done, whichever comes
+// first. An iteration that was interrupted is thrown away.
+//
+// Merging is deterministic. The result of the deepest completed iteration wins, with ties going to the lowest worker.
+// To keep the score of an iteration independent of what the other workers wrote to the table, entries are only
+// trusted for cut-offs at exactly the depth they were searched to, and the root moves are always searched in the
+// same order, with the first of the best scoring moves being picked. As a consequence every worker that completes an
+// iteration agrees on its result.
+//
+// The clock of a timed game doesn't run while the positions are searched, see game.Game.FreezeClock. Should the game
+// refuse one of its legal actions the worker stops, and when no iteration was completed the error is returned along
+// with the first legal action, wrapping ErrActionRefused.
+func Search(ctx context.Context, g *game.Game, opts Options) (Result, error) {
+	root := g.LegalActions()
+	if len(root) == 0 {
+		return Result{}, ErrGameOver
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	depth := opts.Depth
+	if depth <= 0 || depth > MaxDepth {
+		depth = MaxDepth
+	}
+
+	table := opts.Table
+	if table == nil {
+		table = NewTranspositionTable(DefaultTableSize)
+	}
+	table.NewSearch()
+
+	var stop int32
+	if ctx.Err() != nil {
+		stop = 1
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			atomic.StoreInt32(&stop, 1)
+		case <-done:
+		}
+	}()
+
+	results := make([]Result, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		w := &worker{
+			id:    i,
+			table: table,
+			stop:  &stop,
+			rnd:   rand.New(rand.NewSource(opts.Seed + int64(i))),
+		}
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			// the clock of a timed game is frozen so that the search can't run it out for the players
+			c := g.Clone()
+			c.FreezeClock()
+			results[w.id] = w.run(c, root, depth)
+		}(w)
+	}
+	wg.Wait()
+
+	best := Result{Move: root[0]}
+	var err error
+	for i, r := range results {
+		best.Nodes += r.Nodes
+		if r.Depth > best.Depth {
+			best.Move, best.Score, best.Depth = r.Move, r.Score, r.Depth
+		}
+		if err == nil && r.err != nil {
+			err = fmt.Errorf("worker %d: %w", i, r.err)
+		}
+	}
+
+	// a worker that was refused an action stops, its error only matters when no iteration was completed
+	if best.Depth > 0 {
+		err = nil
+	}
+	return best, err
+}
+
+// worker is a single thread of the search.
+type worker struct {
+	id    int
+	table *TranspositionTable
+	stop  *int32
+	rnd   *rand.Rand
+	nodes uint64
+
+	// set when the game refused an action, the worker stops as if the search was stopped
+	err error
+}
+
+// run performs the iterative deepening and returns the result of the last iteration that was completed.
+func (w *worker) run(g *game.Game, root []hive.Action, depth int) Result {
+	var result Result
+
+	// helpers with an odd id start one ply deeper, this is the usual trick for getting the workers out of step.
+	for d := 1 + w.id%2; d <= depth; d++ {
+		move, score, ok := w.root(g, root, d)
+		if !ok {
+			break
+		}
+		result.Move, result.Score, result.Depth = move, score, d
+	}
+
+	// the odd helpers skip the first iteration, if the requested depth is one they never complete one.
+	if result.Depth == 0 && depth == 1 && !w.stopped() {
+		if move, score, ok := w.root(g, root, 1); ok {
+			result.Move, result.Score, result.Depth = move, score, 1
+		}
+	}
+
+	result.Nodes, result.err = w.nodes, w.err
+	return result
+}
+
+// root searches each of the root actions, in order, and returns the first action with the best score. It returns false
+// when the search was stopped before it could finish.
+func (w *worker) root(g *game.Game, actions []hive.Action, depth int) (hive.Action, int, bool) {
+	alpha, beta := -infinity, infinity
+	best := actions[0]
+	for _, a := range actions {
+		child, ok := w.play(g, a)
+		if !ok {
+			return best, 0, false
+		}
+		score := -w.negamax(child, depth-1, 1, -beta, -alpha)
+		if w.stopped() {
+			return best, 0, false
+		}
+		if score > alpha {
+			alpha, best = score, a
+		}
+	}
+	return best, alpha, true
+}
+
+func (w *worker) negamax(g *game.Game, depth, ply, alpha, beta int) int {
+	if w.stopped() {
+		return 0
+	}
+	w.nodes++
+
+	if g.Over() {
+		return terminalScore(g, ply)
+	}
+	if depth == 0 {
+		return Evaluate(g)
+	}
+
+	key := g.Hash()
+	var hint hive.Action
+	var hasHint bool
+	if e, ok := w.table.Probe(key); ok {
+		hint, hasHint = e.Move, true
+		if int(e.Depth) == depth {
+			score := scoreFromTable(int(e.Score), ply)
+			switch {
+			case e.Bound == ExactBound:
+				return score
+			case e.Bound == LowerBound && score >= beta:
+				return score
+			case e.Bound == UpperBound && score <= alpha:
+				return score
+			}
+		}
+	}
+
+	actions := g.LegalActions()
+	w.order(actions, hint, hasHint)
+
+	origAlpha := alpha
+	best, bestMove := -infinity, actions[0]
+	for _, a := range actions {
+		child, ok := w.play(g, a)
+		if !ok {
+			return 0
+		}
+		score := -w.negamax(child, depth-1, ply+1, -beta, -alpha)
+		if w.stopped() {
+			return 0
+		}
+		if score > best {
+			best, bestMove = score, a
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	bound := ExactBound
+	if best <= origAlpha {
+		bound = UpperBound
+	} else if best >= beta {
+		bound = LowerBound
+	}
+	w.table.Store(key, depth, bound, scoreToTable(best, ply), bestMove)
+
+	return best
+}
+
+// order moves the table hint to the front, the helpers also shuffle the remaining actions so that they explore the
+// tree in a different order than the main worker.
+func (w *worker) order(actions []hive.Action, hint hive.Action, hasHint bool) {
+	if w.id > 0 {
+		w.rnd.Shuffle(len(actions), func(i, j int) {
+			actions[i], actions[j] = actions[j], actions[i]
+		})
+	}
+	if !hasHint {
+		return
+	}
+	for i, a := range actions {
+		if a == hint {
+			copy(actions[1:i+1], actions[:i])
+			actions[0] = hint
+			return
+		}
+	}
+}
+
+func (w *worker) stopped() bool {
+	return w.err != nil || atomic.LoadInt32(w.stop) != 0
+}
+
+// terminalScore scores a game that is over from the perspective of the player whose turn it is.
+func terminalScore(g *game.Game, ply int) int {
+	winner, err := g.Winner()
+	if err != nil || winner == game.Tie {
+		return 0
+	}
+	if uint8(winner) == g.Turn() {
+		return winScore - ply
+	}
+	return -winScore + ply
+}
+
+// Win scores depend on the distance from the root, in the table they are stored relative to the position instead so
+// that they remain correct when the position is reached at a different ply.
+func scoreToTable(score, ply int) int {
+	if score > winScore-MaxDepth*2 {
+		return score + ply
+	} else if score < -winScore+MaxDepth*2 {
+		return score - ply
+	}
+	return score
+}
+func scoreFromTable(score, ply int) int {
+	if score > winScore-MaxDepth*2 {
+		return score - ply
+	} else if score < -winScore+MaxDepth*2 {
+		return score + ply
+	}
+	return score
+}
+
+// play returns a copy of the game with the action performed. The actions come from LegalActions so the game should
+// never refuse one, when it does the worker stops with the error and play returns false.
+func (w *worker) play(g *game.Game, a hive.Action) (*game.Game, bool) {
+	c := g.Clone()
+	if err := c.Play(a); err != nil {
+		w.err = fmt.Errorf("%w: %s: %s", ErrActionRefused, a, err)
+		return nil, false
+	}
+	return c, true
+}
+
+const (
+	// MaxDepth is the deepest a search will go.
+	MaxDepth = 64
+
+	// DefaultTableSize is the size in megabytes of the table created when Options doesn't provide one.
+	DefaultTableSize = 16
+
+	infinity = winScore + 1
+)
+
+var ErrGameOver = fmt.Errorf("the game is over and there is nothing to search")
+var ErrActionRefused = fmt.Errorf("the game refused a legal action")
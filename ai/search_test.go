@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+func TestSearch(t *testing.T) {
+	t.Run("When searching the opening a legal action is returned", func(t *testing.T) {
+		g := game.New(nil)
+		r, err := Search(context.Background(), g, Options{Workers: 2, Depth: 2})
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+		if r.Depth != 2 {
+			t.Errorf("Expected the search to reach depth %d instead it reached %d", 2, r.Depth)
+		}
+		if err := g.Play(r.Move); err != nil {
+			t.Errorf("The search returned an action %s the engine rejected with %#v", r.Move, err)
+		}
+	})
+
+	t.Run("When searching with the same seed the result is the same regardless of the number of workers", func(t *testing.T) {
+		g := randomGame(t, rand.New(rand.NewSource(7)), 10)
+
+		expected, err := Search(context.Background(), g, Options{Workers: 1, Depth: 3, Seed: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+		for i := 0; i < 3; i++ {
+			r, err := Search(context.Background(), g, Options{Workers: 4, Depth: 3, Seed: 1})
+			if err != nil {
+				t.Fatalf("Unexpected error %#v", err)
+			}
+			if r.Move != expected.Move || r.Score != expected.Score || r.Depth != expected.Depth {
+				t.Errorf("Expected %s (%d) instead received %s (%d)", expected.Move, expected.Score, r.Move, r.Score)
+			}
+		}
+	})
+
+	t.Run("When the context is already done the first legal action is returned", func(t *testing.T) {
+		g := game.New(nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		r, err := Search(ctx, g, Options{Workers: 2})
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+		if r.Depth != 0 || r.Move != g.LegalActions()[0] {
+			t.Errorf("Expected the first legal action at depth zero instead received %s at depth %d", r.Move, r.Depth)
+		}
+	})
+
+	t.Run("When the clock of a timed game runs out during the search the search carries on", func(t *testing.T) {
+		g := game.New(nil)
+		clock := &tickingClock{start: time.Now()}
+		if err := g.StartClock(game.SuddenDeath(20*time.Millisecond), clock); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+
+		r, err := Search(context.Background(), g, Options{Workers: 2, Depth: 3})
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+		if r.Depth != 3 {
+			t.Errorf("Expected the search to reach depth %d instead it reached %d", 3, r.Depth)
+		}
+	})
+
+	t.Run("When a winning action is available it is found", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(3))
+		for i := 0; i < 200; i++ {
+			g := randomGame(t, rnd, 60)
+			win, ok := winningAction(g)
+			if !ok {
+				continue
+			}
+
+			r, err := Search(context.Background(), g, Options{Workers: 2, Depth: 1})
+			if err != nil {
+				t.Fatalf("Unexpected error %#v", err)
+			}
+			if r.Score < winScore-MaxDepth {
+				t.Errorf("Expected a winning score for %s instead the search returned %s (%d)", win, r.Move, r.Score)
+			}
+			return
+		}
+		t.Skip("Unable to find a position with a winning action")
+	})
+}
+
+// randomGame plays up to plies random legal actions, stopping early so that the game is never over.
+// tickingClock moves a millisecond forward every time it's read.
+type tickingClock struct {
+	start time.Time
+	reads int64
+}
+
+func (c *tickingClock) Now() time.Time {
+	return c.start.Add(time.Duration(atomic.AddInt64(&c.reads, 1)) * time.Millisecond)
+}
+
+func randomGame(t *testing.T, rnd *rand.Rand, plies int) *game.Game {
+	g := game.New(nil)
+	for i := 0; i < plies; i++ {
+		actions := g.LegalActions()
+		a := actions[rnd.Intn(len(actions))]
+		next := g.Clone()
+		if err := next.Play(a); err != nil {
+			t.Fatalf("Unexpected error %#v while playing %s", err, a)
+		}
+		if next.Over() {
+			break
+		}
+		g = next
+	}
+	return g
+}
+
+// winningAction returns an action that immediately wins the game for the player whose turn it is.
+func winningAction(g *game.Game) (hive.Action, bool) {
+	for _, a := range g.LegalActions() {
+		c := g.Clone()
+		if err := c.Play(a); err != nil {
+			continue
+		}
+		if w, err := c.Winner(); err == nil && uint8(w) == g.Turn() {
+			return a, true
+		}
+	}
+	return hive.Action{}, false
+}
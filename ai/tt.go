@@ -0,0 +1,174 @@
+package ai
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/theshadow/hive"
+)
+
+// Bound describes how the score stored in an Entry relates to the true score of the position.
+type Bound uint8
+
+const (
+	NoBound Bound = iota
+	// ExactBound the score is the exact score of the position for the searched depth.
+	ExactBound
+	// LowerBound the search failed high, the true score is at least the stored score.
+	LowerBound
+	// UpperBound the search failed low, the true score is at most the stored score.
+	UpperBound
+)
+
+// Entry is a single record in the TranspositionTable.
+type Entry struct {
+	Key   uint64
+	Move  hive.Action
+	Score int32
+	Depth int8
+	Bound Bound
+
+	// generation the entry was written in, used by the replacement policy to prefer evicting entries left over
+	// from previous searches.
+	generation uint8
+}
+
+// TranspositionTable is a fixed size hash table of search results keyed by a position hash. It is safe for concurrent
+// use, which lets several search goroutines share what they've learned.
+//
+// The table is split into buckets of four entries. A key always maps to the same bucket and the entry that gets
+// replaced when the bucket is full is picked with the following policy:
+//
+//   - An entry with the same key is always overwritten.
+//   - An empty entry is used next.
+//   - Otherwise, the entry with the lowest worth is evicted, where worth is the depth of the entry minus a penalty for
+//     every generation (search) that has passed since it was written. Shallow and stale entries go first.
+//
+// Locking is striped across the buckets so that goroutines only contend when they touch buckets that share a lock.
+type TranspositionTable struct {
+	buckets []bucket
+	mask    uint64
+
+	locks [lockStripes]sync.Mutex
+
+	generation uint32
+}
+
+// NewTranspositionTable creates a table that uses roughly the specified number of megabytes. The number of buckets is
+// rounded down to a power of two, a table is never smaller than a single bucket.
+func NewTranspositionTable(megabytes int) *TranspositionTable {
+	n := uint64(1)
+	if megabytes > 0 {
+		size := uint64(megabytes) << 20 / uint64(unsafe.Sizeof(bucket{}))
+		for n<<1 <= size {
+			n <<= 1
+		}
+	}
+
+	return &TranspositionTable{
+		buckets: make([]bucket, n),
+		mask:    n - 1,
+	}
+}
+
+// Probe looks up the entry for the key, it returns false when the table doesn't have an entry for it.
+func (tt *TranspositionTable) Probe(key uint64) (Entry, bool) {
+	idx := key & tt.mask
+	lock := &tt.locks[idx%lockStripes]
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	b := &tt.buckets[idx]
+	for i := range b {
+		if b[i].Bound != NoBound && b[i].Key == key {
+			return b[i], true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// Store records the result of a search of the position identified by key. See the TranspositionTable for how the
+// entry to replace is chosen.
+func (tt *TranspositionTable) Store(key uint64, depth int, bound Bound, score int, move hive.Action) {
+	idx := key & tt.mask
+	lock := &tt.locks[idx%lockStripes]
+	gen := tt.currentGeneration()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	b := &tt.buckets[idx]
+	victim := 0
+	for i := range b {
+		if b[i].Bound == NoBound || b[i].Key == key {
+			victim = i
+			break
+		}
+		if b[i].worth(gen) < b[victim].worth(gen) {
+			victim = i
+		}
+	}
+
+	b[victim] = Entry{
+		Key:        key,
+		Move:       move,
+		Score:      int32(score),
+		Depth:      clampDepth(depth),
+		Bound:      bound,
+		generation: gen,
+	}
+}
+
+// NewSearch should be called before starting a new search, it ages the existing entries so they are replaced before
+// the entries of the new search.
+func (tt *TranspositionTable) NewSearch() {
+	atomic.AddUint32(&tt.generation, 1)
+}
+
+// Clear empties the table.
+func (tt *TranspositionTable) Clear() {
+	for i := range tt.locks {
+		tt.locks[i].Lock()
+	}
+	for i := range tt.buckets {
+		tt.buckets[i] = bucket{}
+	}
+	for i := range tt.locks {
+		tt.locks[i].Unlock()
+	}
+}
+
+// Len returns the number of entries the table can hold.
+func (tt *TranspositionTable) Len() int {
+	return len(tt.buckets) * bucketSize
+}
+
+func (tt *TranspositionTable) currentGeneration() uint8 {
+	return uint8(atomic.LoadUint32(&tt.generation))
+}
+
+// worth is used to rank entries for replacement, the higher the worth the longer we want to keep the entry.
+func (e Entry) worth(gen uint8) int {
+	return int(e.Depth) - agePenalty*int(gen-e.generation)
+}
+
+func clampDepth(depth int) int8 {
+	if depth > maxDepth {
+		return maxDepth
+	} else if depth < -maxDepth {
+		return -maxDepth
+	}
+	return int8(depth)
+}
+
+type bucket [bucketSize]Entry
+
+const (
+	bucketSize  = 4
+	lockStripes = 256
+	agePenalty  = 8
+	maxDepth    = 127
+)
@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/theshadow/hive"
+)
+
+func TestNewTranspositionTable(t *testing.T) {
+	t.Run("When creating a table the size in memory does not exceed the budget", func(t *testing.T) {
+		tt := NewTranspositionTable(4)
+		if size := uintptr(len(tt.buckets)) * unsafe.Sizeof(bucket{}); size > 4<<20 {
+			t.Errorf("Expected the table to use at most %d bytes instead it uses %d", 4<<20, size)
+		}
+		if n := len(tt.buckets); n&(n-1) != 0 {
+			t.Errorf("Expected the number of buckets to be a power of two instead received %d", n)
+		}
+	})
+
+	t.Run("When creating a table with a zero budget a single bucket is allocated", func(t *testing.T) {
+		tt := NewTranspositionTable(0)
+		if tt.Len() != bucketSize {
+			t.Errorf("Expected the table to hold %d entries instead it holds %d", bucketSize, tt.Len())
+		}
+	})
+}
+
+func TestTranspositionTable_Store(t *testing.T) {
+	move := hive.NewAction(hive.Placed, hive.NewWhitePiece(hive.Ant, hive.PieceA), hive.Origin, hive.Origin)
+
+	t.Run("When storing an entry the same entry is returned by Probe", func(t *testing.T) {
+		tt := NewTranspositionTable(1)
+		tt.Store(42, 3, ExactBound, -17, move)
+
+		e, ok := tt.Probe(42)
+		if !ok {
+			t.Fatal("Expected to find the stored entry")
+		}
+		if e.Depth != 3 || e.Bound != ExactBound || e.Score != -17 || e.Move != move {
+			t.Errorf("Unexpected entry %#v", e)
+		}
+
+		if _, ok := tt.Probe(43); ok {
+			t.Error("Found an entry for a key that was never stored")
+		}
+	})
+
+	t.Run("When a bucket is full the shallowest entry is replaced", func(t *testing.T) {
+		tt := NewTranspositionTable(0)
+		for i := 0; i < bucketSize; i++ {
+			tt.Store(uint64(i+1), 10+i, ExactBound, 0, move)
+		}
+		tt.Store(100, 1, ExactBound, 0, move)
+
+		if _, ok := tt.Probe(1); ok {
+			t.Error("Expected the shallowest entry to be replaced")
+		}
+		if _, ok := tt.Probe(100); !ok {
+			t.Error("Expected the new entry to be stored")
+		}
+	})
+
+	t.Run("When a bucket is full entries from older searches are replaced first", func(t *testing.T) {
+		tt := NewTranspositionTable(0)
+		tt.Store(1, 20, ExactBound, 0, move)
+		tt.NewSearch()
+		tt.NewSearch()
+		tt.NewSearch()
+		for i := 1; i < bucketSize; i++ {
+			tt.Store(uint64(i+1), 5, ExactBound, 0, move)
+		}
+		tt.Store(100, 5, ExactBound, 0, move)
+
+		if _, ok := tt.Probe(1); ok {
+			t.Error("Expected the stale entry to be replaced even though it was the deepest")
+		}
+	})
+
+	t.Run("When clearing the table all entries are removed", func(t *testing.T) {
+		tt := NewTranspositionTable(1)
+		tt.Store(42, 3, ExactBound, 0, move)
+		tt.Clear()
+		if _, ok := tt.Probe(42); ok {
+			t.Error("Found an entry after clearing the table")
+		}
+	})
+
+	t.Run("When several goroutines share a table entries are not corrupted", func(t *testing.T) {
+		tt := NewTranspositionTable(1)
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					key := uint64(i*8 + w)
+					tt.Store(key, i%16, LowerBound, int(key), move)
+					if e, ok := tt.Probe(key); ok && e.Score != int32(key) {
+						t.Errorf("Entry for key %d has score %d", key, e.Score)
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+	})
+}
@@ -2,6 +2,7 @@ package hive
 
 import (
 	"fmt"
+	"sort"
 )
 
 // Board represents a 4D hex grid (x, y, z, height). It works by storing
@@ -124,6 +125,70 @@ func (brd *Board) Pieces() []cell {
 	return brd.cells
 }
 
+// Clone returns an independent copy of the board, changes to the copy are never visible on the original and vice
+// versa.
+func (brd *Board) Clone() *Board {
+	c := &Board{
+		locationMap: make(map[Coordinate]int, len(brd.locationMap)),
+		cells:       make([]cell, len(brd.cells)),
+	}
+	copy(c.cells, brd.cells)
+	for k, v := range brd.locationMap {
+		c.locationMap[k] = v
+	}
+	return c
+}
+
+// Surface returns the empty cells on the ground that touch the hive, in ascending order. The pieces on the ground at
+// the lifted coordinates are treated as if they weren't on the board, a piece that is moving can't move to a cell that
+// is only touching itself.
+func (brd *Board) Surface(lifted ...Coordinate) []Coordinate {
+	skip := make(map[Coordinate]bool, len(lifted))
+	for _, c := range lifted {
+		skip[c] = true
+	}
+
+	seen := make(map[Coordinate]bool)
+	var cells []Coordinate
+	for _, cl := range brd.cells {
+		if cl.Coordinate.H() != 0 || skip[cl.Coordinate] {
+			continue
+		}
+		for _, n := range NeighborsMatrix[:Above] {
+			n = cl.Coordinate.Add(n)
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			if _, ok := brd.Cell(n); !ok {
+				cells = append(cells, n)
+			}
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	return cells
+}
+
+// StackTops returns the empty cell on top of each stack, or single piece, that neighbors the coordinate on the ground.
+func (brd *Board) StackTops(c Coordinate) []Coordinate {
+	var cells []Coordinate
+	for _, n := range NeighborsMatrix[:Above] {
+		top := NewCoordinate(c.X(), c.Y(), c.Z(), 0).Add(n)
+		if _, ok := brd.Cell(top); !ok {
+			continue
+		}
+		for {
+			above := top.Add(NeighborsMatrix[Above])
+			if _, ok := brd.Cell(above); !ok {
+				cells = append(cells, above)
+				break
+			}
+			top = above
+		}
+	}
+	return cells
+}
+
 var Origin = Coordinate(0)
 
 var ErrInvalidCoordinate = fmt.Errorf("the specified coordinate is invalid")
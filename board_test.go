@@ -110,3 +110,45 @@ func TestBoard_Neighbors(t *testing.T) {
 		}
 	}
 }
+
+func TestBoard_Clone(t *testing.T) {
+	board := NewBoard()
+	p := NewPiece(WhiteColor, Grasshopper, PieceA)
+	cA := NewCoordinate(0, 0, 0, 0)
+	cB := NewCoordinate(1, -1, 0, 0)
+	_ = board.Place(p, cA)
+
+	clone := board.Clone()
+	if err := clone.Move(cA, cB); err != nil {
+		t.Fatalf("couldn't move piece on the cloned board: %s", err)
+	}
+	_ = clone.Place(NewPiece(BlackColor, Ant, PieceA), cA)
+
+	if piece, ok := board.Cell(cA); !ok || piece != p {
+		t.Error("moving a piece on the clone changed the original board")
+	}
+	if _, ok := board.Cell(cB); ok {
+		t.Error("found the moved piece on the original board")
+	}
+	if len(board.Pieces()) != 1 {
+		t.Errorf("expected the original board to have 1 piece, found %d", len(board.Pieces()))
+	}
+}
+
+func BenchmarkBoard_Clone(b *testing.B) {
+	board := NewBoard()
+	pieces := []Piece{
+		NewPiece(WhiteColor, Ant, PieceA), NewPiece(WhiteColor, Ant, PieceB), NewPiece(WhiteColor, Ant, PieceC),
+		NewPiece(WhiteColor, Grasshopper, PieceA), NewPiece(WhiteColor, Grasshopper, PieceB),
+		NewPiece(WhiteColor, Grasshopper, PieceC),
+	}
+	for i, c := range NeighborsMatrix[:Above] {
+		_ = board.Place(pieces[i], c)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = board.Clone()
+	}
+}
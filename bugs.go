@@ -0,0 +1,451 @@
+package hive
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Profile is how a bug gets around the hive. A bug without a profile crawls around the edge of the hive, a climber may
+// also climb on top of it and a jumper leaps over it.
+//
+//	J - Jumper
+//	C - Climber
+//
+//	......JC
+//	11111111
+//	 uint8
+type Profile uint8
+
+func (p Profile) IsClimber() bool {
+	return p&Climber > 0
+}
+func (p Profile) IsJumper() bool {
+	return p&Jumper > 0
+}
+
+const (
+	Climber Profile = 0b00000001
+	Jumper  Profile = 0b00000010
+)
+
+// Movement generates the cells a piece of a bug may move to. A movement decides where the bug can get to, including
+// whether it fits through the gaps on its way, the rules every piece follows, such as not splitting the hive, are
+// checked by the game.
+type Movement interface {
+	// Destinations returns the empty cells the piece at the source may move to on the board.
+	Destinations(b *Board, src Coordinate) []Coordinate
+}
+
+// MovementFunc adapts a function to a Movement.
+type MovementFunc func(b *Board, src Coordinate) []Coordinate
+
+func (f MovementFunc) Destinations(b *Board, src Coordinate) []Coordinate {
+	return f(b, src)
+}
+
+// BugDefinition describes a bug of the game, see BugRegistry.
+type BugDefinition struct {
+	// Label is the name of the bug, used by Piece.BugS and the encodings.
+	Label string
+
+	// Letter is the letter of the bug in notations such as UHP.
+	Letter byte
+
+	// Pieces is the most pieces of the bug an inventory may have, from one to three. See MaxPieces.
+	Pieces int
+
+	// Profile is how the bug gets around the hive, a Mosquito borrows the profiles of its neighbors, see ProfileAt.
+	Profile Profile
+
+	// Reach is the farthest a piece of the bug may end up from where it started, in cells on the ground, zero when
+	// there is no limit. A destination out of reach is refused as too far before the Movement is asked.
+	Reach int
+
+	// Movement generates where a piece of the bug may move.
+	Movement Movement
+}
+
+// BugRegistry holds the definition of every bug, the eight bugs of the game and any registered by an application. A
+// bug is identified by its number in a Piece, the numbers after PillBug up to MaxBug are free for new bugs. A new bug
+// is only played when it's in the inventory of a player, see NewInventory.
+//
+// A BugRegistry is safe for concurrent use, though bugs are usually registered once while a program starts up.
+type BugRegistry struct {
+	mu   sync.RWMutex
+	defs map[uint8]BugDefinition
+}
+
+// NewBugRegistry returns a registry of the bugs of the game.
+func NewBugRegistry() *BugRegistry {
+	r := &BugRegistry{defs: make(map[uint8]BugDefinition)}
+	for bug, def := range builtinBugs() {
+		r.defs[bug] = def
+	}
+	return r
+}
+
+// Register adds the bug to the registry. The bug must be a free number and its label and letter must not be used by
+// another bug.
+func (r *BugRegistry) Register(bug uint8, def BugDefinition) error {
+	switch {
+	case bug == NoBug || bug > MaxBug:
+		return fmt.Errorf("%w: %d is outside of the range of bugs", ErrInvalidBug, bug)
+	case def.Label == "" || def.Letter == 0 || def.Movement == nil:
+		return fmt.Errorf("%w: a bug needs a label, a letter, and a movement", ErrInvalidBug)
+	case def.Pieces < int(PieceA) || def.Pieces > int(PieceC):
+		return fmt.Errorf("%w: %s may have from %d to %d pieces", ErrInvalidBug, def.Label, PieceA, PieceC)
+	case def.Reach < 0:
+		return fmt.Errorf("%w: the reach of %s is negative", ErrInvalidBug, def.Label)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.defs[bug]; ok {
+		return fmt.Errorf("%w: %d is the %s", ErrBugRegistered, bug, existing.Label)
+	}
+	for _, existing := range r.defs {
+		if existing.Label == def.Label || existing.Letter == def.Letter {
+			return fmt.Errorf("%w: the %s has the label or letter of the %s", ErrBugRegistered, def.Label,
+				existing.Label)
+		}
+	}
+	r.defs[bug] = def
+	return nil
+}
+
+// Lookup returns the definition of the bug, it returns false when the bug isn't registered.
+func (r *BugRegistry) Lookup(bug uint8) (BugDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.defs[bug]
+	return def, ok
+}
+
+// ByLabel returns the bug with the label.
+func (r *BugRegistry) ByLabel(label string) (uint8, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for bug, def := range r.defs {
+		if def.Label == label {
+			return bug, true
+		}
+	}
+	return NoBug, false
+}
+
+// ByLetter returns the bug with the letter.
+func (r *BugRegistry) ByLetter(letter byte) (uint8, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for bug, def := range r.defs {
+		if def.Letter == letter {
+			return bug, true
+		}
+	}
+	return NoBug, false
+}
+
+// Bugs returns every registered bug in ascending order.
+func (r *BugRegistry) Bugs() []uint8 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	bugs := make([]uint8, 0, len(r.defs))
+	for bug := range r.defs {
+		bugs = append(bugs, bug)
+	}
+	sort.Slice(bugs, func(i, j int) bool { return bugs[i] < bugs[j] })
+	return bugs
+}
+
+// RegisterBug adds the bug to DefaultBugs, see BugRegistry.Register.
+func RegisterBug(bug uint8, def BugDefinition) error {
+	return DefaultBugs.Register(bug, def)
+}
+
+// bugLabel returns the label of the bug in DefaultBugs.
+func bugLabel(bug uint8) string {
+	if def, ok := DefaultBugs.Lookup(bug); ok {
+		return def.Label
+	}
+	return noBugLabel
+}
+
+// builtinBugs returns the definitions of the bugs of the game.
+func builtinBugs() map[uint8]BugDefinition {
+	return map[uint8]BugDefinition{
+		Queen:       {Label: "Queen", Letter: 'Q', Pieces: 1, Reach: 1, Movement: MovementFunc(step)},
+		Beetle:      {Label: "Beetle", Letter: 'B', Pieces: 2, Reach: 1, Profile: Climber, Movement: MovementFunc(climb)},
+		Grasshopper: {Label: "Grasshopper", Letter: 'G', Pieces: 3, Profile: Jumper, Movement: MovementFunc(jump)},
+		Spider:      {Label: "Spider", Letter: 'S', Pieces: 2, Reach: 3, Movement: MovementFunc(walk)},
+		Ant:         {Label: "Ant", Letter: 'A', Pieces: 3, Movement: MovementFunc(crawl)},
+		Mosquito:    {Label: "Mosquito", Letter: 'M', Pieces: 1, Movement: mosquitoMovement{}},
+		Ladybug:     {Label: "Ladybug", Letter: 'L', Pieces: 1, Reach: 3, Profile: Climber, Movement: MovementFunc(scurry)},
+		PillBug:     {Label: "PillBug", Letter: 'P', Pieces: 1, Reach: 1, Movement: MovementFunc(step)},
+	}
+}
+
+// step slides a single cell around the hive, the queen and the pill bug.
+func step(b *Board, src Coordinate) []Coordinate {
+	cells := transit{b, src}.slides(src)
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	return cells
+}
+
+// crawl slides any number of cells around the hive, the ant.
+func crawl(b *Board, src Coordinate) []Coordinate {
+	t := transit{b, src}
+	found := map[Coordinate]bool{src: true}
+	queue := []Coordinate{src}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, n := range t.slides(c) {
+			if !found[n] {
+				found[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	delete(found, src)
+	return cellsOf(found)
+}
+
+// walk slides exactly three cells around the hive without going back over a cell it already passed, the spider.
+func walk(b *Board, src Coordinate) []Coordinate {
+	t := transit{b, src}
+	found := make(map[Coordinate]bool)
+	var visit func(path []Coordinate)
+	visit = func(path []Coordinate) {
+		c := path[len(path)-1]
+		if len(path) == spiderSteps+1 {
+			found[c] = true
+			return
+		}
+	next:
+		for _, n := range t.slides(c) {
+			for _, passed := range path {
+				if n == passed {
+					continue next
+				}
+			}
+			visit(append(path, n))
+		}
+	}
+	visit([]Coordinate{src})
+	return cellsOf(found)
+}
+
+// jump leaps in a straight line over at least one piece and lands on the first empty cell, the grasshopper. A jump
+// isn't a slide, the freedom to move rule doesn't apply.
+func jump(b *Board, src Coordinate) []Coordinate {
+	t := transit{b, src}
+	var cells []Coordinate
+	for _, d := range NeighborsMatrix[:Above] {
+		c := ground(src).Add(d)
+		if t.height(c) == 0 {
+			continue
+		}
+		for t.height(c) > 0 {
+			c = c.Add(d)
+		}
+		cells = append(cells, c)
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	return cells
+}
+
+// climb moves a single cell in any direction, around the hive, on to it, across its top, or back down, the beetle.
+func climb(b *Board, src Coordinate) []Coordinate {
+	t := transit{b, src}
+	var cells []Coordinate
+	for i := range NeighborsMatrix[:Above] {
+		if c, ok := t.climbStep(src, i); ok {
+			cells = append(cells, c)
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	return cells
+}
+
+// scurry moves two cells across the top of the hive and a third cell back down to the ground, the ladybug.
+func scurry(b *Board, src Coordinate) []Coordinate {
+	t := transit{b, src}
+	found := make(map[Coordinate]bool)
+	for i := range NeighborsMatrix[:Above] {
+		first, ok := t.climbStep(src, i)
+		if !ok || first.H() == 0 {
+			continue
+		}
+		for j := range NeighborsMatrix[:Above] {
+			second, ok := t.climbStep(first, j)
+			if !ok || second.H() == 0 {
+				continue
+			}
+			for k := range NeighborsMatrix[:Above] {
+				if last, ok := t.climbStep(second, k); ok && last.H() == 0 && last != ground(src) {
+					found[last] = true
+				}
+			}
+		}
+	}
+	return cellsOf(found)
+}
+
+// mosquitoMovement moves the way any of the bugs the piece touches moves. A Mosquito that only touches other Mosquitoes
+// can't move, and one on top of the hive moves as a beetle until it's back on the ground.
+type mosquitoMovement struct{}
+
+func (mosquitoMovement) Destinations(b *Board, src Coordinate) []Coordinate {
+	if src.H() > 0 {
+		return climb(b, src)
+	}
+
+	t := transit{b, src}
+	copied := map[uint8]bool{Mosquito: true}
+	found := make(map[Coordinate]bool)
+	for _, d := range NeighborsMatrix[:Above] {
+		p, ok := t.top(src.Add(d))
+		if !ok || copied[p.Bug()] {
+			continue
+		}
+		copied[p.Bug()] = true
+		if def, ok := DefaultBugs.Lookup(p.Bug()); ok {
+			for _, c := range def.Movement.Destinations(b, src) {
+				found[c] = true
+			}
+		}
+	}
+	return cellsOf(found)
+}
+
+// ProfileAt returns the profile of the piece at the cell in DefaultBugs. A Mosquito on the ground borrows the profiles
+// of the bugs it touches and one on top of the hive is a climber.
+func ProfileAt(b *Board, c Coordinate) Profile {
+	p, ok := b.Cell(c)
+	if !ok {
+		return 0
+	}
+	if p.Bug() != Mosquito {
+		def, _ := DefaultBugs.Lookup(p.Bug())
+		return def.Profile
+	}
+	if c.H() > 0 {
+		return Climber
+	}
+
+	t := transit{b, c}
+	var profile Profile
+	for _, d := range NeighborsMatrix[:Above] {
+		if n, ok := t.top(c.Add(d)); ok && n.Bug() != Mosquito {
+			def, _ := DefaultBugs.Lookup(n.Bug())
+			profile |= def.Profile
+		}
+	}
+	return profile
+}
+
+// transit is the board as a piece that is moving sees it. The piece is lifted off the board, it neither blocks its
+// own way nor holds the hive together while it moves.
+type transit struct {
+	b   *Board
+	src Coordinate
+}
+
+// height returns the number of pieces on the ground cell, the moving piece aside.
+func (t transit) height(c Coordinate) int {
+	n := 0
+	for h := int8(0); ; h++ {
+		cc := NewCoordinate(c.X(), c.Y(), c.Z(), h)
+		if _, ok := t.b.Cell(cc); !ok {
+			return n
+		}
+		if cc != t.src {
+			n++
+		}
+	}
+}
+
+// top returns the piece on top of the ground cell, the moving piece aside.
+func (t transit) top(c Coordinate) (Piece, bool) {
+	h := t.height(c)
+	if h == 0 {
+		return ZeroPiece, false
+	}
+	return t.b.Cell(NewCoordinate(c.X(), c.Y(), c.Z(), int8(h-1)))
+}
+
+// gate returns the heights of the two cells on either side of a move from the ground cell (c) in the direction (i),
+// the cells the piece passes between.
+func (t transit) gate(c Coordinate, i int) (left, right int) {
+	return t.height(c.Add(NeighborsMatrix[(i+Above-1)%Above])), t.height(c.Add(NeighborsMatrix[(i+1)%Above]))
+}
+
+// slides returns the empty cells a piece on the ground at (c) may slide to. A sliding piece has to fit through the
+// gate, it can't squeeze between two pieces, the freedom to move rule, and it has to keep touching the hive on the way,
+// so exactly one side of the gate holds a piece.
+func (t transit) slides(c Coordinate) []Coordinate {
+	var cells []Coordinate
+	for i, d := range NeighborsMatrix[:Above] {
+		n := c.Add(d)
+		if t.height(n) > 0 {
+			continue
+		}
+		if left, right := t.gate(c, i); (left > 0) != (right > 0) {
+			cells = append(cells, n)
+		}
+	}
+	return cells
+}
+
+// climbStep returns where a climber at (c) ends up when it moves a cell in the direction (i), on top of the stack in
+// that direction or on the ground when there is none. It returns false when the gate blocks the move. On the ground a
+// climber slides like any other piece, up on the hive it may not pass between two stacks that are both higher than
+// where it starts and where it ends up.
+func (t transit) climbStep(c Coordinate, i int) (Coordinate, bool) {
+	from := ground(c)
+	n := from.Add(NeighborsMatrix[i])
+	start, end := int(c.H()), t.height(n)
+	left, right := t.gate(from, i)
+	if start == 0 && end == 0 {
+		return n, (left > 0) != (right > 0)
+	}
+
+	lower, higher := left, start
+	if right < lower {
+		lower = right
+	}
+	if end > higher {
+		higher = end
+	}
+	return NewCoordinate(n.X(), n.Y(), n.Z(), int8(end)), lower <= higher
+}
+
+// ground returns the ground cell under the coordinate.
+func ground(c Coordinate) Coordinate {
+	return NewCoordinate(c.X(), c.Y(), c.Z(), 0)
+}
+
+// cellsOf returns the cells of the set in ascending order.
+func cellsOf(set map[Coordinate]bool) []Coordinate {
+	cells := make([]Coordinate, 0, len(set))
+	for c := range set {
+		cells = append(cells, c)
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	return cells
+}
+
+const (
+	// MaxBug is the largest bug a Piece has room for.
+	MaxBug uint8 = BugMask >> 16
+
+	// spiderSteps is how many cells a Spider slides.
+	spiderSteps = 3
+)
+
+// DefaultBugs is the registry used by the pieces, the inventories, and the game.
+var DefaultBugs = NewBugRegistry()
+
+var ErrInvalidBug = fmt.Errorf("the bug definition is invalid")
+var ErrBugRegistered = fmt.Errorf("the bug is already registered")
@@ -0,0 +1,237 @@
+package hive
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBugRegistry_Register(t *testing.T) {
+	hopper := BugDefinition{Label: "Hopper", Letter: 'H', Pieces: 2, Profile: Jumper, Movement: MovementFunc(crawl)}
+
+	t.Run("When a bug is registered it may be looked up", func(t *testing.T) {
+		r := NewBugRegistry()
+		if err := r.Register(PillBug+1, hopper); err != nil {
+			t.Fatalf("Unexpected error %#v while registering the bug", err)
+		}
+		if def, ok := r.Lookup(PillBug + 1); !ok || def.Label != hopper.Label {
+			t.Errorf("Expected the %s instead received %#v", hopper.Label, def)
+		}
+		if bug, ok := r.ByLetter('H'); !ok || bug != PillBug+1 {
+			t.Errorf("Expected the bug %d for the letter instead received %d", PillBug+1, bug)
+		}
+		if bugs := r.Bugs(); len(bugs) != 9 || bugs[8] != PillBug+1 {
+			t.Errorf("Expected the bug after the bugs of the game instead received %v", bugs)
+		}
+	})
+
+	t.Run("When a bug is already registered it may not be registered again", func(t *testing.T) {
+		r := NewBugRegistry()
+		if err := r.Register(Queen, hopper); !errors.Is(err, ErrBugRegistered) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrBugRegistered, err)
+		}
+		taken := hopper
+		taken.Letter = 'Q'
+		if err := r.Register(PillBug+1, taken); !errors.Is(err, ErrBugRegistered) {
+			t.Errorf("Expected an error of type %#v for a letter in use instead received %#v", ErrBugRegistered, err)
+		}
+	})
+
+	t.Run("When a definition is incomplete or out of range an error is returned", func(t *testing.T) {
+		r := NewBugRegistry()
+		noMovement := hopper
+		noMovement.Movement = nil
+		tooMany := hopper
+		tooMany.Pieces = 4
+		negativeReach := hopper
+		negativeReach.Reach = -1
+		for bug, def := range map[uint8]BugDefinition{NoBug: hopper, MaxBug + 1: hopper, 20: noMovement, 21: tooMany,
+			22: negativeReach} {
+			if err := r.Register(bug, def); !errors.Is(err, ErrInvalidBug) {
+				t.Errorf("Expected an error of type %#v for bug %d instead received %#v", ErrInvalidBug, bug, err)
+			}
+		}
+	})
+}
+
+func TestBugDefinition_Movement(t *testing.T) {
+	north, northeast, southeast := NewCoordinate(0, 1, -1, 0), NewCoordinate(1, 0, -1, 0), NewCoordinate(1, -1, 0, 0)
+	south, southwest, northwest := NewCoordinate(0, -1, 1, 0), NewCoordinate(-1, 0, 1, 0), NewCoordinate(-1, 1, 0, 0)
+	farNorth := NewCoordinate(0, 2, -2, 0)
+
+	// column returns a board with the piece at the origin and a column of two black ants running north of it
+	column := func(p Piece) *Board {
+		b := NewBoard()
+		_ = b.Place(p, Origin)
+		_ = b.Place(NewBlackPiece(Ant, PieceA), north)
+		_ = b.Place(NewBlackPiece(Ant, PieceB), farNorth)
+		return b
+	}
+
+	t.Run("When the queen or the pill bug moves it slides a single cell", func(t *testing.T) {
+		for _, bug := range []uint8{Queen, PillBug} {
+			b := column(NewWhitePiece(bug, PieceA))
+			expectCells(t, bug, destinations(bug, b, Origin), northeast, northwest)
+		}
+	})
+
+	t.Run("When a gate is too narrow to slide through the piece can't pass it", func(t *testing.T) {
+		b := NewBoard()
+		_ = b.Place(NewWhitePiece(Queen, PieceA), Origin)
+		for i, c := range []Coordinate{north, southeast, southwest} {
+			_ = b.Place(NewBlackPiece(Ant, uint8(i+1)), c)
+		}
+		expectCells(t, Queen, destinations(Queen, b, Origin))
+	})
+
+	t.Run("When the spider moves it slides exactly three cells without going back", func(t *testing.T) {
+		b := column(NewWhitePiece(Spider, PieceA))
+		expectCells(t, Spider, destinations(Spider, b, Origin), NewCoordinate(1, 2, -3, 0), NewCoordinate(-1, 3, -2, 0))
+	})
+
+	t.Run("When the ant moves it reaches every cell it can slide to", func(t *testing.T) {
+		b := column(NewWhitePiece(Ant, PieceA))
+		expectCells(t, Ant, destinations(Ant, b, Origin), northeast, NewCoordinate(1, 1, -2, 0),
+			NewCoordinate(1, 2, -3, 0), NewCoordinate(0, 3, -3, 0), NewCoordinate(-1, 3, -2, 0),
+			NewCoordinate(-1, 2, -1, 0), northwest)
+	})
+
+	t.Run("When a cell is closed off by a gate the ant can't enter it", func(t *testing.T) {
+		b := NewBoard()
+		// the pieces surround the origin but for the northwest, the way in is too narrow
+		pieces := []Piece{NewBlackPiece(Ant, PieceA), NewBlackPiece(Ant, PieceB), NewBlackPiece(Ant, PieceC),
+			NewBlackPiece(Spider, PieceA), NewBlackPiece(Spider, PieceB)}
+		for i, c := range []Coordinate{north, northeast, southeast, south, southwest} {
+			_ = b.Place(pieces[i], c)
+		}
+		src := NewCoordinate(2, -1, -1, 0)
+		_ = b.Place(NewWhitePiece(Ant, PieceA), src)
+
+		cells := destinations(Ant, b, src)
+		if contains(cells, Origin) || !contains(cells, northwest) {
+			t.Errorf("Expected the ant to reach %s but not the origin instead received %v", northwest, cells)
+		}
+	})
+
+	t.Run("When the grasshopper moves it jumps in a straight line over at least one piece", func(t *testing.T) {
+		b := column(NewWhitePiece(Grasshopper, PieceA))
+		_ = b.Place(NewBlackPiece(Spider, PieceA), southeast)
+		expectCells(t, Grasshopper, destinations(Grasshopper, b, Origin), NewCoordinate(0, 3, -3, 0),
+			NewCoordinate(2, -2, 0, 0))
+	})
+
+	t.Run("When the beetle moves it steps a single cell and may climb on to the hive", func(t *testing.T) {
+		b := column(NewWhitePiece(Beetle, PieceA))
+		expectCells(t, Beetle, destinations(Beetle, b, Origin), NewCoordinate(0, 1, -1, 1), northeast, northwest)
+	})
+
+	t.Run("When the beetle is on the hive it may not pass between two higher stacks", func(t *testing.T) {
+		b := NewBoard()
+		_ = b.Place(NewBlackPiece(Queen, PieceA), Origin)
+		src := NewCoordinate(0, 0, 0, 1)
+		_ = b.Place(NewWhitePiece(Beetle, PieceA), src)
+		_ = b.Place(NewBlackPiece(Ant, PieceA), north)
+		_ = b.Place(NewBlackPiece(Beetle, PieceA), NewCoordinate(0, 1, -1, 1))
+		_ = b.Place(NewBlackPiece(Ant, PieceB), southeast)
+		_ = b.Place(NewBlackPiece(Beetle, PieceB), NewCoordinate(1, -1, 0, 1))
+
+		cells := destinations(Beetle, b, src)
+		if contains(cells, northeast) || !contains(cells, NewCoordinate(0, 1, -1, 2)) {
+			t.Errorf("Expected the beetle to climb the stack to the north but not to pass to %s instead received %v",
+				northeast, cells)
+		}
+	})
+
+	t.Run("When the ladybug moves it takes two cells over the hive and one down", func(t *testing.T) {
+		b := column(NewWhitePiece(Ladybug, PieceA))
+		expectCells(t, Ladybug, destinations(Ladybug, b, Origin), NewCoordinate(1, 1, -2, 0),
+			NewCoordinate(1, 2, -3, 0), NewCoordinate(0, 3, -3, 0), NewCoordinate(-1, 3, -2, 0),
+			NewCoordinate(-1, 2, -1, 0))
+	})
+
+	t.Run("When the mosquito moves it moves like the bugs it touches", func(t *testing.T) {
+		b := NewBoard()
+		_ = b.Place(NewWhitePiece(Mosquito, PieceA), Origin)
+		_ = b.Place(NewBlackPiece(Grasshopper, PieceA), north)
+		_ = b.Place(NewBlackPiece(Ant, PieceA), farNorth)
+		expectCells(t, Mosquito, destinations(Mosquito, b, Origin), NewCoordinate(0, 3, -3, 0))
+	})
+
+	t.Run("When the mosquito only touches a mosquito it can't move", func(t *testing.T) {
+		b := NewBoard()
+		_ = b.Place(NewWhitePiece(Mosquito, PieceA), Origin)
+		_ = b.Place(NewBlackPiece(Mosquito, PieceA), north)
+		expectCells(t, Mosquito, destinations(Mosquito, b, Origin))
+	})
+
+	t.Run("When the mosquito is on the hive it moves like a beetle", func(t *testing.T) {
+		b := column(NewBlackPiece(Queen, PieceA))
+		src := NewCoordinate(0, 0, 0, 1)
+		_ = b.Place(NewWhitePiece(Mosquito, PieceA), src)
+		expectCells(t, Mosquito, destinations(Mosquito, b, src), destinations(Beetle, b, src)...)
+	})
+}
+
+// destinations returns where the piece of the bug at the source may move to.
+func destinations(bug uint8, b *Board, src Coordinate) []Coordinate {
+	def, _ := DefaultBugs.Lookup(bug)
+	return def.Movement.Destinations(b, src)
+}
+
+// expectCells fails the test unless the cells are the expected cells, in any order.
+func expectCells(t *testing.T, bug uint8, cells []Coordinate, expected ...Coordinate) {
+	t.Helper()
+	if len(cells) != len(expected) {
+		t.Errorf("Expected the %s to reach %v instead received %v", bugLabel(bug), expected, cells)
+		return
+	}
+	for _, c := range expected {
+		if !contains(cells, c) {
+			t.Errorf("Expected the %s to reach %v instead received %v", bugLabel(bug), expected, cells)
+			return
+		}
+	}
+}
+
+func contains(cells []Coordinate, c Coordinate) bool {
+	for _, cell := range cells {
+		if cell == c {
+			return true
+		}
+	}
+	return false
+}
+
+func TestProfileAt(t *testing.T) {
+	north, south := NewCoordinate(0, 1, -1, 0), NewCoordinate(0, -1, 1, 0)
+
+	t.Run("When a bug is at the cell its profile is returned", func(t *testing.T) {
+		b := NewBoard()
+		_ = b.Place(NewWhitePiece(Grasshopper, PieceA), Origin)
+		if p := ProfileAt(b, Origin); p != Jumper {
+			t.Errorf("Expected the profile %08b instead received %08b", Jumper, p)
+		}
+		if p := ProfileAt(b, north); p != 0 {
+			t.Errorf("Expected no profile for an empty cell instead received %08b", p)
+		}
+	})
+
+	t.Run("When a mosquito touches other bugs it borrows their profiles", func(t *testing.T) {
+		b := NewBoard()
+		_ = b.Place(NewWhitePiece(Mosquito, PieceA), Origin)
+		_ = b.Place(NewBlackPiece(Beetle, PieceA), north)
+		_ = b.Place(NewBlackPiece(Grasshopper, PieceA), south)
+		if p := ProfileAt(b, Origin); p != Climber|Jumper {
+			t.Errorf("Expected the profile %08b instead received %08b", Climber|Jumper, p)
+		}
+	})
+
+	t.Run("When a mosquito is on the hive it is a climber", func(t *testing.T) {
+		b := NewBoard()
+		_ = b.Place(NewBlackPiece(Queen, PieceA), Origin)
+		src := NewCoordinate(0, 0, 0, 1)
+		_ = b.Place(NewWhitePiece(Mosquito, PieceA), src)
+		if p := ProfileAt(b, src); p != Climber {
+			t.Errorf("Expected the profile %08b instead received %08b", Climber, p)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Command hive-match plays a match between two players and reports the results, see the match package. A player is
+// one of the agents of the agent package, an external engine that speaks the Universal Hive Protocol included:
+//
+//	random                      random legal moves
+//	greedy                      the move that leaves the best position
+//	search                      the built in search at the default depth
+//	search:depth=4              the built in search to a fixed depth
+//	search:time=500ms           the built in search for a fixed time per move
+//	uhp:/path/to/engine --flag  an engine started with the arguments
+//
+// For example, to test an engine against the built in search until an SPRT decides:
+//
+//	hive-match -a "uhp:./engine" -b search:depth=3 -games 1000 -sprt 0,10 -tc 1m -inc 1s -records games.txt
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/agent"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/match"
+	"github.com/theshadow/hive/uhp"
+)
+
+func main() {
+	first := flag.String("a", "search", "the first player, the results are reported from their perspective")
+	second := flag.String("b", "search", "the second player")
+	games := flag.Int("games", 100, "the number of games to play")
+	gameType := flag.String("gametype", "Base", "the game type, Base+MLP plays with every expansion")
+	openings := flag.String("openings", "", "a file of openings, one per line with the moves separated by semicolons")
+	base := flag.Duration("tc", 0, "the time each player starts with, zero plays untimed games")
+	increment := flag.Duration("inc", 0, "the time added after each action")
+	delay := flag.Duration("delay", 0, "the delay before a clock starts to run on each turn")
+	maxTurns := flag.Int("maxturns", match.DefaultMaxTurns, "the turn at which a game is drawn")
+	sprt := flag.String("sprt", "", "stop early with an SPRT of elo0,elo1, 0,5 for example")
+	alpha := flag.Float64("alpha", 0.05, "the false positive rate of the SPRT")
+	beta := flag.Float64("beta", 0.05, "the false negative rate of the SPRT")
+	records := flag.String("records", "", "a file to append the record of each game to")
+	event := flag.String("event", "", "the event named in the records")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	features, err := uhp.ParseGameType(*gameType)
+	if err != nil {
+		log.Fatalf("hive-match: %s", err)
+	}
+	cfg := match.Config{
+		Games:    *games,
+		Features: features,
+		Control:  game.TimeControl{Base: *base, Increment: *increment, Delay: *delay},
+		MaxTurns: *maxTurns,
+		Event:    *event,
+	}
+	if *openings != "" {
+		if cfg.Openings, err = readOpenings(*openings); err != nil {
+			log.Fatalf("hive-match: %s", err)
+		}
+	}
+	if *sprt != "" {
+		if cfg.SPRT, err = parseSPRT(*sprt, *alpha, *beta); err != nil {
+			log.Fatalf("hive-match: %s", err)
+		}
+	}
+
+	a, engineA, err := newPlayer(ctx, *first, "a")
+	if err != nil {
+		log.Fatalf("hive-match: %s", err)
+	}
+	defer closeEngine(engineA)
+	b, engineB, err := newPlayer(ctx, *second, "b")
+	if err != nil {
+		closeEngine(engineA)
+		log.Fatalf("hive-match: %s", err)
+	}
+	defer closeEngine(engineB)
+
+	var out *os.File
+	if *records != "" {
+		if out, err = os.OpenFile(*records, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644); err != nil {
+			log.Fatalf("hive-match: %s", err)
+		}
+		defer out.Close()
+	}
+
+	cfg.OnGame = func(g match.Game) {
+		r, _ := g.Game.Result()
+		if g.Termination != "" {
+			log.Printf("round %d: %s, %s", g.Round, r, g.Termination)
+		} else {
+			log.Printf("round %d: %s", g.Round, r)
+		}
+		log.Printf("%s", g.Summary)
+		if out != nil {
+			if _, err := g.Record.WriteTo(out); err != nil {
+				log.Printf("hive-match: %s", err)
+			}
+		}
+	}
+
+	log.Printf("hive-match %s (%s): %s vs %s", hive.Version, hive.BuildID, a.Name(), b.Name())
+	res, err := match.Run(ctx, a, b, cfg)
+	fmt.Println(res.Summary)
+	if cfg.SPRT != nil {
+		lower, upper := cfg.SPRT.Bounds()
+		fmt.Printf("SPRT: LLR %.2f (%.2f, %.2f) %s\n", cfg.SPRT.LLR(res.Summary), lower, upper, res.Decision)
+	}
+	if err != nil {
+		log.Printf("hive-match: %s", err)
+	}
+}
+
+// newPlayer builds the player of the spec, see the command documentation. The engine of a uhp player is returned
+// so that it may be closed.
+func newPlayer(ctx context.Context, spec, label string) (match.Player, *uhp.Engine, error) {
+	kind, args := cut(spec, ":")
+	name := label + ":" + spec
+
+	switch kind {
+	case "random":
+		return match.Named(name, agent.NewRandom(time.Now().UnixNano())), nil, nil
+	case "greedy":
+		return match.Named(name, agent.Greedy{}), nil, nil
+	case "search":
+		s := &agent.Search{}
+		for _, opt := range strings.Split(args, ",") {
+			if opt == "" {
+				continue
+			}
+			var err error
+			switch key, value := cut(opt, "="); key {
+			case "depth":
+				s.Options.Depth, err = strconv.Atoi(value)
+			case "time":
+				s.MoveTime, err = time.ParseDuration(value)
+			case "workers":
+				s.Options.Workers, err = strconv.Atoi(value)
+			default:
+				err = fmt.Errorf("unknown search option %q", key)
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("player %s: %w", spec, err)
+			}
+		}
+		return match.Named(name, s), nil, nil
+	case "uhp":
+		fields := strings.Fields(args)
+		if len(fields) == 0 {
+			return nil, nil, fmt.Errorf("player %s: the engine has no path", spec)
+		}
+		e, err := uhp.StartEngine(ctx, fields[0], fields[1:]...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("player %s: %w", spec, err)
+		}
+		if id := e.ID(); id != "" {
+			name = label + ":" + id
+		}
+		return match.Named(name, &agent.Engine{Engine: e, Limit: uhp.Limit{Time: defaultEngineTime}}), e, nil
+	}
+	return nil, nil, fmt.Errorf("player %s: unknown kind of player %q", spec, kind)
+}
+
+func closeEngine(e *uhp.Engine) {
+	if e == nil {
+		return
+	}
+	if err := e.Close(); err != nil {
+		log.Printf("hive-match: %s", err)
+	}
+}
+
+func readOpenings(path string) ([]match.Opening, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return match.ReadOpenings(f)
+}
+
+func parseSPRT(s string, alpha, beta float64) (*match.SPRT, error) {
+	elo0, elo1 := cut(s, ",")
+	t := &match.SPRT{Alpha: alpha, Beta: beta}
+	var err error
+	if t.Elo0, err = strconv.ParseFloat(strings.TrimSpace(elo0), 64); err != nil {
+		return nil, fmt.Errorf("sprt %s: %w", s, err)
+	}
+	if t.Elo1, err = strconv.ParseFloat(strings.TrimSpace(elo1), 64); err != nil {
+		return nil, fmt.Errorf("sprt %s: %w", s, err)
+	}
+	return t, nil
+}
+
+// cut splits the string around the first separator, strings.Cut isn't available to every supported version of Go.
+func cut(s, sep string) (before, after string) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):]
+	}
+	return s, ""
+}
+
+// defaultEngineTime is how long an engine thinks about each move of an untimed game.
+const defaultEngineTime = 5 * time.Second
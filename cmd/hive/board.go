@@ -0,0 +1,165 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/uhp"
+)
+
+// layout places the hexagons of the board on a grid of text. The board is drawn with its flat sides at the top and
+// the bottom, each column of hexagons is a column of text and neighbors in the next column are half a row up or down,
+// so a row of hexagons spans two lines.
+type layout struct {
+	minCol, minRow int
+	cols, rows     int
+}
+
+// newLayout returns a layout that fits every cell.
+func newLayout(cells []hive.Coordinate) layout {
+	if len(cells) == 0 {
+		cells = []hive.Coordinate{hive.Origin}
+	}
+	minCol, minRow := column(cells[0]), row(cells[0])
+	maxCol, maxRow := minCol, minRow
+	for _, c := range cells[1:] {
+		col, r := column(c), row(c)
+		minCol, maxCol = minInt(minCol, col), maxInt(maxCol, col)
+		minRow, maxRow = minInt(minRow, r), maxInt(maxRow, r)
+	}
+	return layout{
+		minCol: minCol,
+		minRow: minRow,
+		cols:   maxCol - minCol + 1,
+		rows:   maxRow - minRow + 1,
+	}
+}
+
+// position returns the line and the column of text the label of the cell starts at.
+func (l layout) position(c hive.Coordinate) (line, col int) {
+	return row(c) - l.minRow, (column(c) - l.minCol) * cellWidth
+}
+
+// cell returns the ground coordinate of the hexagon drawn at the line and column of text, it returns false when the
+// text is outside of the layout.
+func (l layout) cell(line, col int) (hive.Coordinate, bool) {
+	if line < 0 || col < 0 || line >= l.height() || col >= l.width() {
+		return hive.Origin, false
+	}
+	d := hive.Doubled{Col: col/cellWidth + l.minCol, Row: line + l.minRow}
+	// the row of a hexagon has the parity of its column, the line in between belongs to the hexagon above
+	if (d.Row-d.Col)%2 != 0 {
+		d.Row--
+	}
+	return d.Coordinate(), true
+}
+
+// width and height return the size of the layout in text.
+func (l layout) width() int {
+	return l.cols * cellWidth
+}
+
+func (l layout) height() int {
+	return l.rows
+}
+
+// column and row return where the cell is on the grid, see hive.Doubled.
+func column(c hive.Coordinate) int {
+	return c.Doubled().Col
+}
+
+func row(c hive.Coordinate) int {
+	return c.Doubled().Row
+}
+
+// stacks returns the pieces of the board grouped by the ground cell of their stack, bottom first.
+func stacks(g *game.Game) map[hive.Coordinate][]hive.Piece {
+	pieces := g.Pieces()
+	heights := make(map[hive.Coordinate][]hive.Coordinate)
+	for c := range pieces {
+		ground := hive.NewCoordinate(c.X(), c.Y(), c.Z(), 0)
+		heights[ground] = append(heights[ground], c)
+	}
+
+	s := make(map[hive.Coordinate][]hive.Piece, len(heights))
+	for ground, cells := range heights {
+		sort.Slice(cells, func(i, j int) bool { return cells[i].H() < cells[j].H() })
+		for _, c := range cells {
+			s[ground] = append(s[ground], pieces[c])
+		}
+	}
+	return s
+}
+
+// drawBoard draws the board as text. The marks are drawn on the cells they're keyed by in place of the top piece,
+// which is how the legal destinations of a piece are shown. A stack is drawn as its top piece with a + and the pieces
+// underneath are listed below the board.
+func drawBoard(g *game.Game, marks map[hive.Coordinate]string) string {
+	s := stacks(g)
+	cells := make([]hive.Coordinate, 0, len(s)+len(marks))
+	for c := range s {
+		cells = append(cells, c)
+	}
+	for c := range marks {
+		cells = append(cells, hive.NewCoordinate(c.X(), c.Y(), c.Z(), 0))
+	}
+	l := newLayout(cells)
+
+	grid := make([][]byte, l.height())
+	for i := range grid {
+		grid[i] = []byte(strings.Repeat(" ", l.width()))
+	}
+	put := func(c hive.Coordinate, label string) {
+		line, col := l.position(c)
+		copy(grid[line][col:], label)
+	}
+
+	var under []string
+	for c, pieces := range s {
+		top := pieces[len(pieces)-1]
+		label := uhp.PieceString(top)
+		if len(pieces) > 1 {
+			label += "+"
+			var names []string
+			for i := len(pieces) - 2; i >= 0; i-- {
+				names = append(names, uhp.PieceString(pieces[i]))
+			}
+			under = append(under, label[:len(label)-1]+" is on "+strings.Join(names, " on "))
+		}
+		put(c, label)
+	}
+	for c, mark := range marks {
+		put(hive.NewCoordinate(c.X(), c.Y(), c.Z(), 0), mark)
+	}
+
+	var b strings.Builder
+	for _, line := range grid {
+		b.WriteString(strings.TrimRight(string(line), " "))
+		b.WriteString("\n")
+	}
+	sort.Strings(under)
+	for _, u := range under {
+		b.WriteString(u)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// cellWidth is the number of characters of text each column of hexagons takes, a label such as wA1+ and a space.
+const cellWidth = 5
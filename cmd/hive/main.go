@@ -0,0 +1,65 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Command hive plays and explores games of hive in a terminal. Moves are typed in the notation of the uhp package,
+// wS1 or bG1 -wS1, and the commands print the board, list the legal moves, take moves back, load and save games,
+// toggle features, and let the search play a move. Type help for the list of commands.
+//
+// With -tui the game is played full-screen instead, a hot-seat game for two players at one terminal. Pieces are
+// selected with the cursor, moved with qweasd, or with the mouse, and their legal destinations are highlighted. The
+// left and right arrows step through the history.
+//
+//	hive -gametype Base+MLP
+//	hive -handicap "White removed=A; Black placements=1"
+//	hive -load game.txt
+//	hive -tui
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/game/i18n"
+	"github.com/theshadow/hive/uhp"
+)
+
+func main() {
+	gameType := flag.String("gametype", "Base", "the game type, Base+MLP plays with every expansion")
+	load := flag.String("load", "", "a game record or game string to start from")
+	lang := flag.String("lang", os.Getenv("LANG"), "the language rule errors are explained in")
+	full := flag.Bool("tui", false, "play full-screen, with the keyboard or the mouse")
+	handicap := flag.String("handicap", "", "the handicap of the game, such as \"Black placements=1 queen=5\"")
+	flag.Parse()
+
+	features, err := uhp.ParseGameType(*gameType)
+	if err != nil {
+		log.Fatalf("hive: %s", err)
+	}
+
+	h, err := uhp.ParseHandicap(*handicap)
+	if err != nil {
+		log.Fatalf("hive: %s", err)
+	}
+	g := game.New(features)
+	if err := g.ApplyHandicap(h); err != nil {
+		log.Fatalf("hive: %s", err)
+	}
+
+	r := newREPL(g, os.Stdout, i18n.Lookup(*lang))
+	if *load != "" {
+		if err := r.load([]string{*load}); err != nil {
+			log.Fatalf("hive: %s", err)
+		}
+	}
+	if *full {
+		err = runTUI(r.g, r.catalog, os.Stdin, os.Stdout)
+	} else {
+		err = r.run(os.Stdin)
+	}
+	if err != nil {
+		log.Fatalf("hive: %s", err)
+	}
+}
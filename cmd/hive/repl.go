@@ -0,0 +1,438 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/agent"
+	"github.com/theshadow/hive/ai"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/game/i18n"
+	"github.com/theshadow/hive/record"
+	"github.com/theshadow/hive/uhp"
+)
+
+// repl reads commands and moves a line at a time and plays them on a game.
+type repl struct {
+	g       *game.Game
+	out     io.Writer
+	catalog *i18n.Catalog
+
+	// auto prints the board after every move.
+	auto bool
+}
+
+// command is a command of the repl, the arguments are the words that follow its name.
+type command struct {
+	usage string
+	help  string
+	run   func(r *repl, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	// the table refers to help, which prints the table, so it's filled in here
+	commands = map[string]command{
+		"board":    {"board", "print the board", (*repl).board},
+		"moves":    {"moves [piece]", "list the legal moves, of a piece they're also marked on the board", (*repl).moves},
+		"undo":     {"undo [n]", "take back the last move, or the last n", (*repl).undo},
+		"new":      {"new [gametype]", "start a new game, Base+MLP for example", (*repl).newGame},
+		"load":     {"load file", "load a game record, or a game string", (*repl).load},
+		"save":     {"save file", "save the game as a game record", (*repl).save},
+		"position": {"position", "print the game string of the position", (*repl).position},
+		"features": {"features", "list the features and whether they're enabled", (*repl).features},
+		"toggle":   {"toggle feature", "enable or disable a feature and replay the game", (*repl).toggle},
+		"ai":       {"ai [depth|duration]", "let the search play a move, to a depth or for a time", (*repl).ai},
+		"why":      {"why move", "explain every rule that stands in the way of a move", (*repl).why},
+		"auto":     {"auto", "toggle printing the board after every move", (*repl).toggleAuto},
+		"help":     {"help", "print this help", (*repl).help},
+	}
+}
+
+func newREPL(g *game.Game, out io.Writer, catalog *i18n.Catalog) *repl {
+	return &repl{g: g, out: out, catalog: catalog, auto: true}
+}
+
+// run reads lines until the input ends or the player quits.
+func (r *repl) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	r.prompt()
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		if line != "" {
+			if err := r.exec(line); err != nil {
+				fmt.Fprintf(r.out, "%s\n", r.explain(err))
+			}
+		}
+		r.prompt()
+	}
+	return scanner.Err()
+}
+
+func (r *repl) prompt() {
+	fmt.Fprintf(r.out, "%s> ", r.status())
+}
+
+// exec runs the command on the line, a line that isn't a command is a move.
+func (r *repl) exec(line string) error {
+	fields := strings.Fields(line)
+	if cmd, ok := commands[fields[0]]; ok {
+		return cmd.run(r, fields[1:])
+	}
+	err := r.play(line)
+	if len(fields) == 1 && errors.Is(err, uhp.ErrInvalidPiece) {
+		return fmt.Errorf("%w: %q, type help for the commands", ErrUnknownCommand, line)
+	}
+	return err
+}
+
+// play plays the move in notation. A move that isn't legal is played anyway so that the game reports the rule it
+// breaks.
+func (r *repl) play(move string) error {
+	a, err := uhp.ParseMove(r.g, move)
+	if errors.Is(err, uhp.ErrInvalidMove) {
+		if a, err = uhp.ParseAction(r.g, move); err == nil {
+			return r.g.Play(a)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return r.played(a)
+}
+
+// played plays an action that's known to be legal and reports it.
+func (r *repl) played(a hive.Action) error {
+	notation, err := uhp.MoveString(r.g, a)
+	if err != nil {
+		return err
+	}
+	if err := r.g.Play(a); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.out, "played %s\n", notation)
+	r.show()
+	if res, err := r.g.Result(); err == nil {
+		fmt.Fprintf(r.out, "%s\n", res)
+	}
+	return nil
+}
+
+// show prints the board when the board is printed after every move.
+func (r *repl) show() {
+	if r.auto {
+		fmt.Fprint(r.out, drawBoard(r.g, nil))
+	}
+}
+
+func (r *repl) board(args []string) error {
+	fmt.Fprint(r.out, drawBoard(r.g, nil))
+	for _, color := range []uint8{hive.WhiteColor, hive.BlackColor} {
+		var hand []string
+		for _, p := range r.g.Hand(color) {
+			hand = append(hand, uhp.PieceString(p))
+		}
+		fmt.Fprintf(r.out, "%s in hand: %s\n", colorName(color), strings.Join(hand, " "))
+	}
+	return nil
+}
+
+func (r *repl) moves(args []string) error {
+	var piece hive.Piece
+	if len(args) > 0 {
+		var err error
+		if piece, err = uhp.ParsePiece(args[0]); err != nil {
+			return err
+		}
+	}
+
+	marks := make(map[hive.Coordinate]string)
+	var notations []string
+	for _, a := range r.g.LegalActions() {
+		if len(args) > 0 && a.Piece() != piece {
+			continue
+		}
+		m, err := uhp.MoveString(r.g, a)
+		if err != nil {
+			return err
+		}
+		notations = append(notations, m)
+		marks[a.Dst()] = destinationMark
+	}
+	if len(notations) == 0 {
+		fmt.Fprintln(r.out, "no legal moves")
+		return nil
+	}
+
+	if len(args) > 0 {
+		fmt.Fprint(r.out, drawBoard(r.g, marks))
+	}
+	sort.Strings(notations)
+	fmt.Fprintf(r.out, "%d moves: %s\n", len(notations), strings.Join(notations, ", "))
+	return nil
+}
+
+// undo replays the game without its last moves, as the game itself only moves forward.
+func (r *repl) undo(args []string) error {
+	n := 1
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			return fmt.Errorf("%w: undo takes a number of moves", ErrUsage)
+		}
+	}
+	history := r.g.History()
+	if n > len(history) {
+		return fmt.Errorf("%w: there are only %d moves to take back", ErrUsage, len(history))
+	}
+	return r.replay(r.g.Features(), history[:len(history)-n])
+}
+
+func (r *repl) newGame(args []string) error {
+	features := r.g.Features()
+	if len(args) > 0 {
+		var err error
+		if features, err = uhp.ParseGameType(args[0]); err != nil {
+			return err
+		}
+	}
+	// the handicap and the inventories carry over to the new game
+	return r.replay(features, nil)
+}
+
+// load reads a game record, or when the file isn't a record a game string.
+func (r *repl) load(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: load takes a file", ErrUsage)
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	text := strings.TrimSpace(string(data))
+	var g *game.Game
+	if strings.HasPrefix(text, "Base") {
+		g, err = uhp.ParseGame(text)
+	} else {
+		var rec *record.Record
+		if rec, err = record.Read(strings.NewReader(text)); err == nil {
+			g, err = rec.Game()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	r.g = g
+	r.show()
+	return nil
+}
+
+func (r *repl) save(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: save takes a file", ErrUsage)
+	}
+	rec, err := record.FromGame(r.g, record.Tag{Name: record.TagDate, Value: time.Now().Format("2006.01.02")})
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := rec.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (r *repl) position(args []string) error {
+	s, err := uhp.GameString(r.g)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(r.out, s)
+	return nil
+}
+
+func (r *repl) features(args []string) error {
+	enabled := make(map[game.Feature]bool)
+	for _, f := range r.g.Features() {
+		enabled[f] = true
+	}
+	for _, f := range allFeatures() {
+		state := "off"
+		if enabled[f] {
+			state = "on"
+		}
+		fmt.Fprintf(r.out, "%s %s\n", f, state)
+	}
+	return nil
+}
+
+// toggle replays the game with the feature enabled or disabled, a game that can't be replayed is left as it was.
+func (r *repl) toggle(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: toggle takes a feature", ErrUsage)
+	}
+	feature, err := game.ParseFeature(args[0])
+	if err != nil {
+		return err
+	}
+
+	var features []game.Feature
+	found := false
+	for _, f := range r.g.Features() {
+		if f == feature {
+			found = true
+			continue
+		}
+		features = append(features, f)
+	}
+	if !found {
+		features = append(features, feature)
+	}
+	if err := r.replay(features, r.g.History()); err != nil {
+		return err
+	}
+	return r.features(nil)
+}
+
+// ai asks the search for a move and plays it.
+func (r *repl) ai(args []string) error {
+	s := &agent.Search{Options: ai.Options{Depth: agent.DefaultDepth}}
+	if len(args) > 0 {
+		if depth, err := strconv.Atoi(args[0]); err == nil {
+			s.Options.Depth = depth
+		} else if d, err := time.ParseDuration(args[0]); err == nil {
+			s.Options.Depth, s.MoveTime = 0, d
+		} else {
+			return fmt.Errorf("%w: ai takes a depth or a duration", ErrUsage)
+		}
+	}
+
+	a, err := s.Next(context.Background(), r.g.Clone())
+	if err != nil {
+		return err
+	}
+	return r.played(a)
+}
+
+// why explains every rule a movement breaks, see game.Explain. A placement only has the one rule it breaks.
+func (r *repl) why(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: why takes a move", ErrUsage)
+	}
+	a, err := uhp.ParseAction(r.g, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	if !a.WasMoved() {
+		if err := r.g.Clone().Play(a); err != nil {
+			return err
+		}
+		fmt.Fprintln(r.out, "the move is legal")
+		return nil
+	}
+
+	e := r.g.Explain(a.Src(), a.Dst())
+	switch {
+	case e.Err != nil:
+		return e.Err
+	case e.Legal:
+		fmt.Fprintln(r.out, "the move is legal")
+	}
+	for _, v := range e.Violations {
+		fmt.Fprintln(r.out, r.explain(v))
+	}
+	return nil
+}
+
+func (r *repl) toggleAuto(args []string) error {
+	r.auto = !r.auto
+	return nil
+}
+
+func (r *repl) help(args []string) error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(r.out, "Type a move in notation to play it, wS1, bG1 -wS1, or pass. The commands are:")
+	for _, name := range names {
+		fmt.Fprintf(r.out, "  %-22s %s\n", commands[name].usage, commands[name].help)
+	}
+	fmt.Fprintf(r.out, "  %-22s %s\n", "quit", "leave")
+	return nil
+}
+
+// replay replaces the game with a new game with the features and the history, the players keep their inventories and
+// the handicap.
+func (r *repl) replay(features []game.Feature, history []hive.Action) error {
+	g := game.New(features, r.g.Inventory(hive.WhiteColor), r.g.Inventory(hive.BlackColor))
+	if err := g.ApplyHandicap(r.g.Handicap()); err != nil {
+		return err
+	}
+	for i, a := range history {
+		if err := g.Play(a); err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+	r.g = g
+	r.show()
+	return nil
+}
+
+// status describes the position for the prompt.
+func (r *repl) status() string {
+	if res, err := r.g.Result(); err == nil {
+		return res.String()
+	}
+	return fmt.Sprintf("%s[%d]", colorName(r.g.Turn()), r.g.Turns())
+}
+
+// explain renders an error for the player, rule errors are explained in their language.
+func (r *repl) explain(err error) string {
+	if game.RuleOf(err) != game.NoRule {
+		return r.catalog.Explain(err)
+	}
+	return err.Error()
+}
+
+func colorName(color uint8) string {
+	if color == hive.WhiteColor {
+		return "White"
+	}
+	return "Black"
+}
+
+func allFeatures() []game.Feature {
+	var features []game.Feature
+	for f := game.LadybugPieceFeature; f <= game.TournamentQueensRuleFeature; f++ {
+		features = append(features, f)
+	}
+	return features
+}
+
+// destinationMark is drawn on the cells a piece may move or be placed to.
+const destinationMark = " * "
+
+var ErrUsage = fmt.Errorf("the command was used incorrectly")
+var ErrUnknownCommand = fmt.Errorf("unknown command or move")
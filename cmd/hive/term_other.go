@@ -0,0 +1,17 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package main
+
+import "os"
+
+// makeRaw isn't supported on this platform, the full-screen interface isn't available.
+func makeRaw(fd int) (func(), error) {
+	return nil, ErrNoTerminal
+}
+
+func terminalSize(fd int) (cols, lines int, err error) {
+	return 0, 0, ErrNoTerminal
+}
+
+func notifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,653 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/agent"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/game/i18n"
+	"github.com/theshadow/hive/uhp"
+)
+
+// tui is the full-screen interface, a hot-seat game played with the keyboard or the mouse. A piece is selected on the
+// board or in hand, its legal destinations are highlighted, and selecting one plays the move. The history may be
+// stepped through at any time, moves are only played at the latest position.
+type tui struct {
+	replay  *game.Replay
+	catalog *i18n.Catalog
+
+	// g is a copy of the game at the position of the replay that's shown.
+	g *game.Game
+
+	cursor   hive.Coordinate
+	selected hive.Piece
+	targets  map[hive.Coordinate]hive.Action
+	message  string
+
+	// the screen as last drawn, to find what the mouse clicked on
+	screen screenMap
+}
+
+// screenMap records where things were drawn.
+type screenMap struct {
+	layout               layout
+	boardTop, boardLines int
+	offLine, offCol      int
+	hand                 []handSpot
+}
+
+// handSpot is a piece in hand drawn at the line, from the column up to the end.
+type handSpot struct {
+	line, col, end int
+	piece          hive.Piece
+}
+
+func newTUI(g *game.Game, catalog *i18n.Catalog) (*tui, error) {
+	replay, err := g.Replay()
+	if err != nil {
+		return nil, err
+	}
+	t := &tui{replay: replay, catalog: catalog}
+	t.seek(replay.Len())
+	return t, nil
+}
+
+// seek shows the position after the first n actions of the history.
+func (t *tui) seek(n int) {
+	_ = t.replay.Seek(clamp(n, 0, t.replay.Len()))
+	t.g = t.replay.Game()
+	t.deselect()
+}
+
+func (t *tui) latest() bool {
+	return t.replay.Ply() == t.replay.Len()
+}
+
+// play plays the action at the latest position.
+func (t *tui) play(a hive.Action) {
+	if !t.latest() {
+		t.message = notLatest
+		return
+	}
+	notation, _ := uhp.MoveString(t.g, a)
+	if err := t.g.Play(a); err != nil {
+		t.message = t.explain(err)
+		return
+	}
+	// the game accepted the action, the replay can't refuse it
+	_ = t.replay.Append(a)
+	t.message = fmt.Sprintf("%s played %s", colorName(a.Piece().Color()), notation)
+	if a.WasPassed() {
+		t.message = "The player passed."
+	}
+	t.deselect()
+}
+
+// choose acts on the cell, it plays the selected piece there or selects the piece on top of it.
+func (t *tui) choose(c hive.Coordinate) {
+	t.cursor = c
+	if a, ok := t.targets[c]; ok {
+		t.play(a)
+		return
+	}
+
+	top, ok := t.top(c)
+	if !ok || top == t.selected {
+		t.deselect()
+		return
+	}
+	t.selectPiece(top)
+}
+
+// selectPiece highlights the legal destinations of the piece.
+func (t *tui) selectPiece(p hive.Piece) {
+	t.deselect()
+	for _, a := range t.g.LegalActions() {
+		if a.Piece() != p || a.WasPassed() {
+			continue
+		}
+		ground := hive.NewCoordinate(a.Dst().X(), a.Dst().Y(), a.Dst().Z(), 0)
+		// a movement is preferred over a throw to the same cell
+		if prev, ok := t.targets[ground]; !ok || prev.WasThrown() {
+			t.targets[ground] = a
+		}
+	}
+	if len(t.targets) == 0 {
+		t.message = fmt.Sprintf("%s has nowhere to go.", uhp.PieceString(p))
+		return
+	}
+	t.selected = p
+	t.message = ""
+}
+
+func (t *tui) deselect() {
+	t.selected = hive.ZeroPiece
+	t.targets = make(map[hive.Coordinate]hive.Action)
+}
+
+// nextInHand selects the next piece in hand that may be placed, after the one that's selected.
+func (t *tui) nextInHand() {
+	var placeable []hive.Piece
+	seen := make(map[hive.Piece]bool)
+	for _, a := range t.g.LegalActions() {
+		if a.WasPlaced() && !seen[a.Piece()] {
+			seen[a.Piece()] = true
+			placeable = append(placeable, a.Piece())
+		}
+	}
+	if len(placeable) == 0 {
+		t.message = "There is nothing to place."
+		return
+	}
+
+	next := placeable[0]
+	for i, p := range placeable {
+		if p == t.selected {
+			next = placeable[(i+1)%len(placeable)]
+		}
+	}
+	t.selectPiece(next)
+}
+
+func (t *tui) pass() {
+	for _, a := range t.g.LegalActions() {
+		if a.WasPassed() {
+			t.play(a)
+			return
+		}
+	}
+	t.message = t.explain(t.g.Clone().Pass())
+}
+
+// undo takes back the last action, the history after it is forgotten.
+func (t *tui) undo() {
+	if t.replay.Len() == 0 {
+		return
+	}
+	_ = t.replay.Truncate(t.replay.Len() - 1)
+	t.seek(t.replay.Len())
+	t.message = "The last move was taken back."
+}
+
+// engine lets the search play a move.
+func (t *tui) engine() {
+	if !t.latest() {
+		t.message = notLatest
+		return
+	}
+	a, err := (&agent.Search{}).Next(context.Background(), t.g.Clone())
+	if err != nil {
+		t.message = t.explain(err)
+		return
+	}
+	t.play(a)
+}
+
+func (t *tui) top(c hive.Coordinate) (hive.Piece, bool) {
+	s := stacks(t.g)[c]
+	if len(s) == 0 {
+		return hive.ZeroPiece, false
+	}
+	return s[len(s)-1], true
+}
+
+func (t *tui) explain(err error) string {
+	if game.RuleOf(err) != game.NoRule {
+		return t.catalog.Explain(err)
+	}
+	return err.Error()
+}
+
+// handle acts on a key, it returns false when the player quits.
+func (t *tui) handle(k key) bool {
+	if k.mouse {
+		t.click(k.line, k.col)
+		return true
+	}
+	switch k.name {
+	case "ctrl-c":
+		return false
+	case "left":
+		t.seek(t.replay.Ply() - 1)
+	case "right":
+		t.seek(t.replay.Ply() + 1)
+	case "home":
+		t.seek(0)
+	case "end":
+		t.seek(t.replay.Len())
+	case "up":
+		t.move(hive.North)
+	case "down":
+		t.move(hive.South)
+	case "enter":
+		t.choose(t.cursor)
+	case "esc":
+		t.deselect()
+		t.message = ""
+	case "tab":
+		t.nextInHand()
+	}
+
+	switch k.r {
+	case 'Q':
+		return false
+	case 'w':
+		t.move(hive.North)
+	case 'e':
+		t.move(hive.Northeast)
+	case 'd':
+		t.move(hive.Southeast)
+	case 's':
+		t.move(hive.South)
+	case 'a':
+		t.move(hive.Southwest)
+	case 'q':
+		t.move(hive.Northwest)
+	case ' ':
+		t.choose(t.cursor)
+	case 'p':
+		t.pass()
+	case 'u':
+		t.undo()
+	case 'g':
+		t.engine()
+	}
+	return true
+}
+
+func (t *tui) move(dir int) {
+	t.cursor = t.cursor.Add(hive.NeighborsMatrix[dir])
+}
+
+// click acts on the text at the line and column the mouse clicked.
+func (t *tui) click(line, col int) {
+	for _, s := range t.screen.hand {
+		if line == s.line && col >= s.col && col < s.end {
+			t.selectPiece(s.piece)
+			return
+		}
+	}
+	if line < t.screen.boardTop || line >= t.screen.boardTop+t.screen.boardLines {
+		return
+	}
+	if c, ok := t.screen.layout.cell(line-t.screen.boardTop+t.screen.offLine, col-boardLeft+t.screen.offCol); ok {
+		t.choose(c)
+	}
+}
+
+// draw renders the screen to fit the number of columns and lines.
+func (t *tui) draw(cols, lines int) string {
+	s := newCanvas(cols, lines)
+	t.screen = screenMap{boardTop: 2, boardLines: lines - 2 - footerLines}
+
+	status := fmt.Sprintf("%s to move, turn %d", colorName(t.g.Turn()), t.g.Turns())
+	if res, err := t.g.Result(); err == nil {
+		status = res.String()
+	}
+	if !t.latest() {
+		status += fmt.Sprintf("   (move %d of %d)", t.replay.Ply(), t.replay.Len())
+	}
+	s.text(0, 0, status, styleBold)
+
+	t.drawBoard(s)
+
+	footer := lines - footerLines
+	for i, color := range []uint8{hive.WhiteColor, hive.BlackColor} {
+		t.drawHand(s, footer+i, color)
+	}
+	s.text(footer+2, 0, t.message, styleMessage)
+	s.text(footer+3, 0, "qweasd ↑↓ cursor   enter or click select   tab hand   esc cancel", styleDim)
+	s.text(footer+4, 0, "← → home end history   p pass   u undo   g engine move   Q quit", styleDim)
+	return s.String()
+}
+
+func (t *tui) drawBoard(s *canvas) {
+	st := stacks(t.g)
+
+	// the board shows the empty cells around the hive, the highlighted cells, and the cursor
+	cells := []hive.Coordinate{t.cursor}
+	empty := make(map[hive.Coordinate]bool)
+	for c := range st {
+		cells = append(cells, c)
+		for dir := hive.North; dir < hive.Above; dir++ {
+			n := c.Add(hive.NeighborsMatrix[dir])
+			if _, ok := st[n]; !ok {
+				empty[n] = true
+				cells = append(cells, n)
+			}
+		}
+	}
+	if len(st) == 0 {
+		empty[hive.Origin] = true
+		cells = append(cells, hive.Origin)
+	}
+	for c := range t.targets {
+		cells = append(cells, c)
+	}
+	l := newLayout(cells)
+	t.screen.layout = l
+
+	// keep the cursor in view when the board is larger than the screen
+	cursorLine, cursorCol := l.position(t.cursor)
+	if l.height() > t.screen.boardLines {
+		t.screen.offLine = clamp(cursorLine-t.screen.boardLines/2, 0, l.height()-t.screen.boardLines)
+	}
+	if width := s.cols - boardLeft; l.width() > width {
+		t.screen.offCol = clamp(cursorCol-width/2, 0, l.width()-width)
+	}
+
+	put := func(c hive.Coordinate, label, style string) {
+		line, col := l.position(c)
+		line -= t.screen.offLine
+		if line < 0 || line >= t.screen.boardLines {
+			return
+		}
+		s.text(t.screen.boardTop+line, boardLeft+col-t.screen.offCol, label, style)
+	}
+
+	for c := range empty {
+		put(c, " . ", styleDim)
+	}
+	for c, pieces := range st {
+		top := pieces[len(pieces)-1]
+		label := uhp.PieceString(top)
+		for len(label) < 3 {
+			label += " "
+		}
+		if len(pieces) > 1 {
+			label += strconv.Itoa(len(pieces))
+		}
+		style := styleBlack
+		if top.Color() == hive.WhiteColor {
+			style = styleWhite
+		}
+		if top == t.selected {
+			style = styleSelected
+		}
+		put(c, label, style)
+	}
+	for c := range t.targets {
+		label := " * "
+		if top, ok := t.top(c); ok {
+			label = uhp.PieceString(top)
+		}
+		put(c, label, styleTarget)
+	}
+
+	// the cursor brackets the cell
+	line, col := l.position(t.cursor)
+	line -= t.screen.offLine
+	if line >= 0 && line < t.screen.boardLines {
+		s.text(t.screen.boardTop+line, boardLeft+col-t.screen.offCol-1, "[", styleCursor)
+		s.text(t.screen.boardTop+line, boardLeft+col-t.screen.offCol+3, "]", styleCursor)
+	}
+}
+
+func (t *tui) drawHand(s *canvas, line int, color uint8) {
+	placeable := make(map[hive.Piece]bool)
+	if t.g.Turn() == color {
+		for _, a := range t.g.LegalActions() {
+			if a.WasPlaced() {
+				placeable[a.Piece()] = true
+			}
+		}
+	}
+
+	col := s.text(line, 0, colorName(color)+" in hand: ", styleBold)
+	for _, p := range t.g.Hand(color) {
+		style := styleDim
+		switch {
+		case p == t.selected:
+			style = styleSelected
+		case placeable[p]:
+			style = styleBlack
+			if color == hive.WhiteColor {
+				style = styleWhite
+			}
+		}
+		end := s.text(line, col, uhp.PieceString(p), style)
+		if placeable[p] {
+			t.screen.hand = append(t.screen.hand, handSpot{line: line, col: col, end: end, piece: p})
+		}
+		col = end + 1
+	}
+}
+
+func clamp(v, low, high int) int {
+	if v < low {
+		return low
+	}
+	if v > high {
+		return high
+	}
+	return v
+}
+
+// canvas is a screen of text where each character may be styled.
+type canvas struct {
+	cols, lines int
+	chars       [][]rune
+	styles      [][]string
+}
+
+func newCanvas(cols, lines int) *canvas {
+	c := &canvas{cols: cols, lines: lines, chars: make([][]rune, lines), styles: make([][]string, lines)}
+	for i := range c.chars {
+		c.chars[i] = []rune(strings.Repeat(" ", cols))
+		c.styles[i] = make([]string, cols)
+	}
+	return c
+}
+
+// text writes the text at the line starting at the column and returns the column after it. Text outside of the
+// canvas is dropped.
+func (c *canvas) text(line, col int, s, style string) int {
+	for _, r := range s {
+		if line >= 0 && line < c.lines && col >= 0 && col < c.cols {
+			c.chars[line][col] = r
+			c.styles[line][col] = style
+		}
+		col++
+	}
+	return col
+}
+
+// String returns the escape sequences that draw the canvas from the top left corner of the screen.
+func (c *canvas) String() string {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i := range c.chars {
+		style := ""
+		for j, r := range c.chars[i] {
+			if c.styles[i][j] != style {
+				style = c.styles[i][j]
+				b.WriteString(styleReset + style)
+			}
+			b.WriteRune(r)
+		}
+		b.WriteString(styleReset)
+		if i < len(c.chars)-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	return b.String()
+}
+
+// key is a key press or a mouse click, the line and column of a click count from zero.
+type key struct {
+	name string
+	r    rune
+
+	mouse     bool
+	line, col int
+}
+
+// parseKeys reads the keys of the input, which may hold more than one.
+func parseKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		k, n := parseKey(b)
+		if k.name != "" || k.r != 0 || k.mouse {
+			keys = append(keys, k)
+		}
+		b = b[n:]
+	}
+	return keys
+}
+
+// parseKey reads the first key of the input and returns how many bytes it took.
+func parseKey(b []byte) (key, int) {
+	switch b[0] {
+	case 3:
+		return key{name: "ctrl-c"}, 1
+	case '\r', '\n':
+		return key{name: "enter"}, 1
+	case '\t':
+		return key{name: "tab"}, 1
+	case 0x1b:
+		if len(b) == 1 || b[1] != '[' && b[1] != 'O' {
+			return key{name: "esc"}, 1
+		}
+	default:
+		r := []rune(string(b))[0]
+		return key{r: r}, len(string(r))
+	}
+
+	// an escape sequence, ESC [ followed by parameters and a final letter
+	end := 2
+	for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+		end++
+	}
+	if end == len(b) {
+		return key{name: "esc"}, len(b)
+	}
+	params, final := string(b[2:end]), b[end]
+	n := end + 1
+
+	if strings.HasPrefix(params, "<") {
+		// an SGR mouse report, ESC [ < button ; column ; line M, only presses of the first button are clicks
+		fields := strings.Split(params[1:], ";")
+		if len(fields) != 3 || final != 'M' || fields[0] != "0" {
+			return key{}, n
+		}
+		col, _ := strconv.Atoi(fields[1])
+		line, _ := strconv.Atoi(fields[2])
+		return key{mouse: true, line: line - 1, col: col - 1}, n
+	}
+
+	switch {
+	case final == 'A':
+		return key{name: "up"}, n
+	case final == 'B':
+		return key{name: "down"}, n
+	case final == 'C':
+		return key{name: "right"}, n
+	case final == 'D':
+		return key{name: "left"}, n
+	case final == 'H' || final == '~' && (params == "1" || params == "7"):
+		return key{name: "home"}, n
+	case final == 'F' || final == '~' && (params == "4" || params == "8"):
+		return key{name: "end"}, n
+	}
+	return key{}, n
+}
+
+// runTUI plays the game full-screen until the player quits.
+func runTUI(g *game.Game, catalog *i18n.Catalog, in *os.File, out io.Writer) error {
+	t, err := newTUI(g, catalog)
+	if err != nil {
+		return err
+	}
+	fd := int(in.Fd())
+	restore, err := makeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	w := bufio.NewWriter(out)
+	w.WriteString(enterScreen)
+	defer func() {
+		w.WriteString(leaveScreen)
+		w.Flush()
+	}()
+
+	input := make(chan []byte)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				close(input)
+				return
+			}
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+			input <- chunk
+		}
+	}()
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+
+	for {
+		cols, lines, err := terminalSize(fd)
+		if err != nil || cols < minCols || lines < minLines {
+			cols, lines = minCols, minLines
+		}
+		w.WriteString(t.draw(cols, lines))
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		select {
+		case chunk, ok := <-input:
+			if !ok {
+				return nil
+			}
+			for _, k := range parseKeys(chunk) {
+				if !t.handle(k) {
+					return nil
+				}
+			}
+		case <-resized:
+			w.WriteString(clearScreen)
+		}
+	}
+}
+
+const (
+	// footerLines are the lines below the board, the hands, the message, and the help.
+	footerLines = 5
+
+	// boardLeft leaves room for the cursor to bracket a cell in the first column.
+	boardLeft = 1
+
+	minCols  = 80
+	minLines = 24
+
+	// the alternate screen is used with the cursor hidden and mouse reports turned on
+	enterScreen = "\x1b[?1049h\x1b[?25l\x1b[?1000h\x1b[?1006h\x1b[2J"
+	leaveScreen = "\x1b[?1006l\x1b[?1000l\x1b[?25h\x1b[?1049l"
+	clearScreen = "\x1b[2J"
+
+	notLatest = "Return to the latest move to play, press End."
+
+	styleReset    = "\x1b[0m"
+	styleBold     = "\x1b[1m"
+	styleDim      = "\x1b[2m"
+	styleWhite    = "\x1b[30;47m"
+	styleBlack    = "\x1b[97;40m"
+	styleSelected = "\x1b[30;45m"
+	styleTarget   = "\x1b[30;42m"
+	styleCursor   = "\x1b[1;33m"
+	styleMessage  = "\x1b[36m"
+)
+
+var ErrNoTerminal = fmt.Errorf("the input is not a terminal")
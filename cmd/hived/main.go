@@ -0,0 +1,71 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Command hived is a reference server that hosts games of hive over a JSON API, see the server package for the
+// endpoints.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/server"
+	"github.com/theshadow/hive/store"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "the address to listen on")
+	data := flag.String("data", "", "the directory to keep the games in, when empty the games are kept in memory")
+	admin := flag.String("admin-token", os.Getenv("HIVED_ADMIN_TOKEN"),
+		"the token that lets a request adjudicate games, when empty nobody may adjudicate")
+	flag.Parse()
+
+	var gs store.GameStore = store.NewMemory()
+	if *data != "" {
+		fl, err := store.NewFileLog(*data)
+		if err != nil {
+			log.Fatalf("hived: %s", err)
+		}
+		gs = fl
+	}
+
+	handler := server.New(gs)
+	handler.AdminToken = *admin
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("hived %s (%s) listening on %s", hive.Version, hive.BuildID, *addr)
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		log.Fatalf("hived: %s", err)
+	case <-ctx.Done():
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil {
+		log.Printf("hived: shutdown: %s", err)
+	}
+}
+
+const shutdownTimeout = 5 * time.Second
@@ -15,9 +15,9 @@ import "fmt"
 // the sign. This shouldn't be an issue if we assume that the world will wrap and if it does become an issue we
 // can maintain the interface and modify the type to use an uint64 instead.
 //
-//     X        Y        Z       H
-// 11111111|11111111|11111111|11111111
-//    int8    int8     int8     int8
+//	    X        Y        Z       H
+//	11111111|11111111|11111111|11111111
+//	   int8    int8     int8     int8
 type Coordinate uint32
 
 func NewCoordinate(x, y, z, h int8) Coordinate {
@@ -94,6 +94,19 @@ func (c Coordinate) H() int8 {
 	}
 	return int8(c)
 }
+
+// Distance returns the number of steps between the two coordinates on the ground, the height of either is ignored.
+func Distance(a, b Coordinate) int {
+	return (abs(int(a.X())-int(b.X())) + abs(int(a.Y())-int(b.Y())) + abs(int(a.Z())-int(b.Z()))) / 2
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
 func (c Coordinate) String() string {
 	return fmt.Sprintf("X: %d, Y: %d, Z: %d, H: %d", c.X(), c.Y(), c.Z(), c.H())
 }
@@ -12,8 +12,8 @@ Basics
 
 The library consists of an engine for managing the rules and state of a single game instance.
 The engine attempts to be efficient and compact in its memory usage. It hides this behind a
-layer of data types. The most expensive algorithm implemented is A* which can be used for
-many things but is primarily used for validating the movement of pieces.
+layer of data types. The movement of each bug is generated by its definition in the
+BugRegistry, a movement is valid when its destination is one of the cells the bug can reach.
 
 At the core you can instantiate a new Game instance and interact with the state of the game
 using one of the player actions, Place or Move. If either action being performed would be in
@@ -23,6 +23,22 @@ about the types of errors the action interface will return see the Game type.
 To support the game rules and manage the state the game instance will use a collection of types
 dedicated to tracking the location of pieces, player pieces, turn number, and turn history.
 
+Bugs
+
+Every bug, the eight of the game included, is described by a BugDefinition in DefaultBugs with
+its label, its letter, the most pieces an inventory may have, its profile, how far it may reach,
+and a Movement that generates where its pieces may move. The movements of the bugs of the game
+follow the rules of the game, a piece that slides must fit through the gap it passes and keep
+touching the hive. Applications register new bugs with RegisterBug using the numbers after
+PillBug and put them in play through an Inventory, no change to the engine needed.
+
+Layouts
+
+A Coordinate is a cube coordinate with a height. Front-ends that draw the board convert it to the
+axial, offset, or doubled coordinates of a grid with Axial, Offset, and Doubled, and a Layout
+places the cells in pixel space, flat-top like the board or pointy-top, with the center and the
+corners of each hexagon and the cell a point falls in.
+
 Types and Values
 
 Game maintains the state of an instance of the game engine and is where the rules are implemented.
@@ -0,0 +1,171 @@
+package hive
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The JSON encodings of the core types favor readability over size, they are meant for the APIs of servers and
+// clients rather than for storage. A piece is encoded as its labels and a coordinate as its four values.
+//
+//   {"color": "White", "bug": "Queen", "piece": "Piece A"}
+//   {"x": 0, "y": 1, "z": -1, "h": 0}
+//   {"act": "Moved", "piece": {...}, "src": {...}, "dst": {...}}
+//   {"act": "Resigned", "color": "Black"}
+//   {"Queen": 1, "Ant": 2}
+
+type jsonPiece struct {
+	Color string `json:"color"`
+	Bug   string `json:"bug"`
+	Piece string `json:"piece"`
+}
+
+type jsonCoordinate struct {
+	X int8 `json:"x"`
+	Y int8 `json:"y"`
+	Z int8 `json:"z"`
+	H int8 `json:"h"`
+}
+
+type jsonAction struct {
+	Act   string      `json:"act"`
+	Color string      `json:"color,omitempty"`
+	Piece *Piece      `json:"piece,omitempty"`
+	Src   *Coordinate `json:"src,omitempty"`
+	Dst   *Coordinate `json:"dst,omitempty"`
+}
+
+func (p Piece) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonPiece{Color: p.ColorS(), Bug: p.BugS(), Piece: p.PieceS()})
+}
+func (p *Piece) UnmarshalJSON(data []byte) error {
+	var v jsonPiece
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	color, ok := label(colorLabels, v.Color)
+	if !ok || color == NoColor {
+		return fmt.Errorf("%w: unknown color %q", ErrInvalidEncoding, v.Color)
+	}
+	bug, ok := DefaultBugs.ByLabel(v.Bug)
+	if !ok {
+		return fmt.Errorf("%w: unknown bug %q", ErrInvalidEncoding, v.Bug)
+	}
+	piece, ok := label(pieceLabels, v.Piece)
+	if !ok || piece == NoPiece {
+		return fmt.Errorf("%w: unknown piece %q", ErrInvalidEncoding, v.Piece)
+	}
+
+	*p = NewPiece(color, bug, piece)
+	return nil
+}
+
+func (c Coordinate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonCoordinate{X: c.X(), Y: c.Y(), Z: c.Z(), H: c.H()})
+}
+func (c *Coordinate) UnmarshalJSON(data []byte) error {
+	var v jsonCoordinate
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = NewCoordinate(v.X, v.Y, v.Z, v.H)
+	return nil
+}
+
+// MarshalJSON only includes the fields that are meaningful for the act, a pass has no piece or coordinates, a
+// placement has no source, and the actions that conclude a game only have a color.
+func (m Action) MarshalJSON() ([]byte, error) {
+	v := jsonAction{Act: m.ActS()}
+	if m.Concludes() {
+		if color := m.Piece().Color(); color != NoColor {
+			v.Color = m.Piece().ColorS()
+		}
+		return json.Marshal(v)
+	}
+	if !m.WasPassed() {
+		piece, dst := m.Piece(), m.Dst()
+		v.Piece, v.Dst = &piece, &dst
+	}
+	if m.WasMoved() || m.WasThrown() {
+		src := m.Src()
+		v.Src = &src
+	}
+	return json.Marshal(v)
+}
+func (m *Action) UnmarshalJSON(data []byte) error {
+	var v jsonAction
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	act, ok := label(actLabels, v.Act)
+	if !ok {
+		return fmt.Errorf("%w: unknown act %q", ErrInvalidEncoding, v.Act)
+	}
+
+	piece, src, dst := ZeroPiece, Coordinate(0), Coordinate(0)
+	if v.Piece != nil {
+		piece = *v.Piece
+	}
+	if v.Color != "" {
+		color, ok := label(colorLabels, v.Color)
+		if !ok || color == NoColor {
+			return fmt.Errorf("%w: unknown color %q", ErrInvalidEncoding, v.Color)
+		}
+		piece = NewPiece(color, NoBug, NoPiece)
+	}
+	if v.Src != nil {
+		src = *v.Src
+	}
+	if v.Dst != nil {
+		dst = *v.Dst
+	}
+
+	*m = NewAction(act, piece, src, dst)
+	return nil
+}
+
+// MarshalJSON encodes the inventory as the number of pieces of each bug it has, keyed by the label of the bug.
+func (inv Inventory) MarshalJSON() ([]byte, error) {
+	v := make(map[string]int)
+	for _, bug := range DefaultBugs.Bugs() {
+		if n := inv.Count(bug); n > 0 {
+			v[bugLabel(bug)] = n
+		}
+	}
+	return json.Marshal(v)
+}
+func (inv *Inventory) UnmarshalJSON(data []byte) error {
+	var v map[string]int
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	counts := make(map[uint8]int, len(v))
+	for name, n := range v {
+		bug, ok := DefaultBugs.ByLabel(name)
+		if !ok {
+			return fmt.Errorf("%w: unknown bug %q", ErrInvalidEncoding, name)
+		}
+		counts[bug] = n
+	}
+	decoded, err := NewInventory(counts)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidEncoding, err)
+	}
+	*inv = decoded
+	return nil
+}
+
+// label returns the index of the label, the labels are indexed by the value they describe.
+func label(labels []string, s string) (uint8, bool) {
+	for i, l := range labels {
+		if l == s {
+			return uint8(i), true
+		}
+	}
+	return 0, false
+}
+
+var ErrInvalidEncoding = fmt.Errorf("the encoded value is invalid")
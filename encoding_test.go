@@ -0,0 +1,52 @@
+package hive
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestAction_MarshalJSON(t *testing.T) {
+	actions := []Action{
+		NewAction(Placed, NewPiece(WhiteColor, Queen, PieceA), 0, NewCoordinate(0, 1, -1, 0)),
+		NewAction(Moved, NewPiece(BlackColor, Beetle, PieceB), NewCoordinate(1, -1, 0, 0), NewCoordinate(0, 0, 0, 1)),
+		NewAction(Thrown, NewPiece(BlackColor, Ant, PieceC), NewCoordinate(-1, 0, 1, 0), NewCoordinate(0, -1, 1, 0)),
+		NewAction(Passed, ZeroPiece, 0, 0),
+		NewAction(Resigned, NewPiece(BlackColor, NoBug, NoPiece), 0, 0),
+		NewAction(Drawn, ZeroPiece, 0, 0),
+	}
+
+	for _, a := range actions {
+		t.Run("When encoding and decoding the "+a.ActS()+" action the action is unchanged", func(t *testing.T) {
+			data, err := json.Marshal(a)
+			if err != nil {
+				t.Fatalf("Unexpected error %#v while encoding %s", err, a)
+			}
+			var decoded Action
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unexpected error %#v while decoding %s", err, data)
+			}
+			if decoded != a {
+				t.Errorf("Expected %s instead received %s", a, decoded)
+			}
+		})
+	}
+
+	t.Run("When encoding a placement the source is left out", func(t *testing.T) {
+		data, _ := json.Marshal(actions[0])
+		expected := `{"act":"Placed","piece":{"color":"White","bug":"Queen","piece":"Piece A"},"dst":{"x":0,"y":1,"z":-1,"h":0}}`
+		if string(data) != expected {
+			t.Errorf("Expected %s instead received %s", expected, data)
+		}
+	})
+}
+
+func TestPiece_UnmarshalJSON(t *testing.T) {
+	t.Run("When decoding an unknown bug an error is returned", func(t *testing.T) {
+		var p Piece
+		err := json.Unmarshal([]byte(`{"color":"White","bug":"Dragonfly","piece":"Piece A"}`), &p)
+		if !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidEncoding, err)
+		}
+	})
+}
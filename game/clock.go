@@ -0,0 +1,366 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/theshadow/hive"
+)
+
+// Clock tells the time. The game asks a Clock instead of the time package so that timed games can be tested, and
+// replayed, without waiting on the wall clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock is a Clock that only moves when it's told to, it's safe to share between goroutines.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by the duration.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// TimeControl describes how much time each player has. Every player starts with the Base time and their clock runs
+// during their turns. At most one of Increment and Delay is set, see the constructors.
+type TimeControl struct {
+	// Base is the time each player starts with.
+	Base time.Duration
+
+	// Increment is added to the players time after each of their actions, Fischer timing.
+	Increment time.Duration
+
+	// Delay is how long a players clock waits at the start of each of their turns before it starts to run, Bronstein
+	// timing. Unlike an increment the delay is never banked, at most the time used on the turn is given back.
+	Delay time.Duration
+}
+
+// SuddenDeath gives each player the base time for the whole game.
+func SuddenDeath(base time.Duration) TimeControl {
+	return TimeControl{Base: base}
+}
+
+// Fischer gives each player the base time and adds the increment after each of their actions.
+func Fischer(base, increment time.Duration) TimeControl {
+	return TimeControl{Base: base, Increment: increment}
+}
+
+// Bronstein gives each player the base time and gives back the time used on each action, up to the delay.
+func Bronstein(base, delay time.Duration) TimeControl {
+	return TimeControl{Base: base, Delay: delay}
+}
+
+func (tc TimeControl) String() string {
+	switch {
+	case tc.Increment > 0:
+		return fmt.Sprintf("Fischer %s + %s", tc.Base, tc.Increment)
+	case tc.Delay > 0:
+		return fmt.Sprintf("Bronstein %s delay %s", tc.Base, tc.Delay)
+	}
+	return fmt.Sprintf("Sudden Death %s", tc.Base)
+}
+
+// ClockState is a reading of the clocks of a timed game.
+type ClockState struct {
+	Control TimeControl
+
+	// White and Black are the time the players have left, the time used on the current turn is already deducted.
+	White, Black time.Duration
+
+	// Running is the color of the player whose clock is running, NoColor once the game is over.
+	Running uint8
+}
+
+// Remaining returns the time the player of the color has left.
+func (cs ClockState) Remaining(color uint8) time.Duration {
+	if color == WhiteColor {
+		return cs.White
+	}
+	return cs.Black
+}
+
+// MarshalJSON encodes the durations in milliseconds.
+func (cs ClockState) MarshalJSON() ([]byte, error) {
+	v := jsonClock{
+		Base:      cs.Control.Base.Milliseconds(),
+		Increment: cs.Control.Increment.Milliseconds(),
+		Delay:     cs.Control.Delay.Milliseconds(),
+		White:     cs.White.Milliseconds(),
+		Black:     cs.Black.Milliseconds(),
+	}
+	if cs.Running != NoColor {
+		v.Running = colorName(cs.Running)
+	}
+	return json.Marshal(v)
+}
+func (cs *ClockState) UnmarshalJSON(data []byte) error {
+	var v jsonClock
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*cs = ClockState{
+		Control: TimeControl{
+			Base:      time.Duration(v.Base) * time.Millisecond,
+			Increment: time.Duration(v.Increment) * time.Millisecond,
+			Delay:     time.Duration(v.Delay) * time.Millisecond,
+		},
+		White: time.Duration(v.White) * time.Millisecond,
+		Black: time.Duration(v.Black) * time.Millisecond,
+	}
+	switch v.Running {
+	case colorName(WhiteColor):
+		cs.Running = WhiteColor
+	case colorName(BlackColor):
+		cs.Running = BlackColor
+	}
+	return nil
+}
+
+type jsonClock struct {
+	Base      int64  `json:"base_ms"`
+	Increment int64  `json:"increment_ms,omitempty"`
+	Delay     int64  `json:"delay_ms,omitempty"`
+	White     int64  `json:"white_ms"`
+	Black     int64  `json:"black_ms"`
+	Running   string `json:"running,omitempty"`
+}
+
+// StartClock puts the game under the time control. The clock of the player whose turn it is starts right away.
+func (g *Game) StartClock(tc TimeControl, clock Clock) error {
+	if tc.Base <= 0 || tc.Increment < 0 || tc.Delay < 0 || (tc.Increment > 0 && tc.Delay > 0) {
+		return ErrInvalidTimeControl
+	}
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	now := clock.Now()
+	g.timer = &timer{
+		control:   tc,
+		clock:     clock,
+		white:     tc.Base,
+		black:     tc.Base,
+		turnStart: now,
+		now:       now,
+		started:   now,
+	}
+	return nil
+}
+
+// Clock returns a reading of the clocks, it returns false when the game isn't timed.
+func (g *Game) Clock() (ClockState, bool) {
+	if g.timer == nil {
+		return ClockState{}, false
+	}
+
+	t := g.timer
+	cs := ClockState{Control: t.control, White: t.white, Black: t.black}
+	if !t.stopped {
+		cs.Running = g.turn
+		left := t.remaining(g.turn, t.clock.Now())
+		if left < 0 {
+			left = 0
+		}
+		if g.turn == WhiteColor {
+			cs.White = left
+		} else {
+			cs.Black = left
+		}
+	}
+	return cs, true
+}
+
+// FreezeClock stops time for the game, the clocks keep the time the players had left and no longer run. It's meant for
+// copies of a game that are analyzed, such as the positions of a search, so that a copy can't run out of time while
+// it's being looked at. A game that isn't timed is left alone.
+func (g *Game) FreezeClock() {
+	if t := g.timer; t != nil && !t.stopped {
+		t.clock = frozenClock(t.clock.Now())
+	}
+}
+
+// SetClock replaces the Clock of a timed game, the time the players have left is kept. It's meant for a game rebuilt
+// from a record, which is replayed on a ManualClock that follows the Timestamps of the record and is then handed over
+// to the wall clock. A game that isn't timed is left alone.
+func (g *Game) SetClock(clock Clock) {
+	if g.timer == nil {
+		return
+	}
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	g.timer.clock = clock
+}
+
+// Timestamps returns when the clock of a timed game was started and when each action played since then was performed.
+// The times are those of the last actions of the history, an action played before the clock was started has none. It
+// returns false when the game isn't timed.
+func (g *Game) Timestamps() (started time.Time, played []time.Time, ok bool) {
+	if g.timer == nil {
+		return time.Time{}, nil, false
+	}
+	played = make([]time.Time, len(g.timer.played))
+	copy(played, g.timer.played)
+	return g.timer.started, played, true
+}
+
+// PlayedAt returns when the action at the index of the history was performed. It returns false when the game isn't
+// timed or the action was played before the clock was started.
+func (g *Game) PlayedAt(i int) (time.Time, bool) {
+	if g.timer == nil {
+		return time.Time{}, false
+	}
+	i -= len(g.history) - len(g.timer.played)
+	if i < 0 || i >= len(g.timer.played) {
+		return time.Time{}, false
+	}
+	return g.timer.played[i], true
+}
+
+// PlayAt plays the action as if it was performed at the time, see Play. It's meant for a copy of a game that follows
+// the actions of another, such as a stored game, so that the clocks of both agree on when each action was performed,
+// see PlayedAt. The Clock of the game is kept for the actions that follow. An untimed game, or the zero time, plays the
+// action on the Clock of the game.
+func (g *Game) PlayAt(a Action, at time.Time) error {
+	if g.timer == nil || at.IsZero() {
+		return g.Play(a)
+	}
+	clock := g.timer.clock
+	g.timer.clock = frozenClock(at)
+	defer func() { g.timer.clock = clock }()
+	return g.Play(a)
+}
+
+// frozenClock is a Clock that always tells the same time, see FreezeClock.
+type frozenClock time.Time
+
+func (c frozenClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// flagged returns true when the player whose turn it is has run out of time.
+func (g *Game) flagged() bool {
+	t := g.timer
+	if t == nil {
+		return false
+	}
+	if t.stopped {
+		return t.flagged
+	}
+	return t.remaining(g.turn, t.clock.Now()) <= 0
+}
+
+// checkClock is called at the start of every action. It refuses the action when the player has run out of time, which
+// ends the game with the timeout recorded in the history, and otherwise notes the time of the action, which is what
+// the player is charged for in chargeClock.
+func (g *Game) checkClock(act uint8, src, dst Coordinate) error {
+	t := g.timer
+	if t == nil || t.stopped {
+		return nil
+	}
+	t.now = t.clock.Now()
+	if t.remaining(g.turn, t.now) <= 0 {
+		g.timeout()
+		return newRuleError(ErrRuleOutOfTime, act, ZeroPiece, src, dst)
+	}
+	return nil
+}
+
+// chargeClock is called once an action was performed, before the turn changes. It charges the player for the time
+// they took and starts the clock of their opponent, unless the action ended the game.
+func (g *Game) chargeClock() {
+	t := g.timer
+	if t == nil || t.stopped {
+		return
+	}
+
+	left := t.remaining(g.turn, t.now) + t.control.Increment
+	if g.turn == WhiteColor {
+		t.white = left
+	} else {
+		t.black = left
+	}
+	t.turnStart = t.now
+	t.played = append(t.played, t.now)
+}
+
+// stopClock freezes the clocks, it's called when the game is over. A player that ran out of time is remembered so
+// that the game stays lost on time.
+func (g *Game) stopClock() {
+	t := g.timer
+	if t == nil || t.stopped {
+		return
+	}
+	now := t.clock.Now()
+	left := t.remaining(g.turn, now)
+	t.flagged = left <= 0
+	if left < 0 {
+		left = 0
+	}
+	if g.turn == WhiteColor {
+		t.white = left
+	} else {
+		t.black = left
+	}
+	t.stopped = true
+}
+
+// timer tracks the time of a timed game.
+type timer struct {
+	control TimeControl
+	clock   Clock
+
+	// the time each player had left at the start of their current, or next, turn
+	white, black time.Duration
+
+	// when the current turn started and when the action being performed was started
+	turnStart, now time.Time
+
+	// set once the game is over, flagged remembers if it was over on time
+	stopped, flagged bool
+
+	// when the clock was started and when each action since was performed, see Timestamps
+	started time.Time
+	played  []time.Time
+}
+
+// remaining returns the time the player has left when it's their turn, taking the delay into account.
+func (t *timer) remaining(color uint8, now time.Time) time.Duration {
+	left := t.black
+	if color == WhiteColor {
+		left = t.white
+	}
+
+	used := now.Sub(t.turnStart) - t.control.Delay
+	if used < 0 {
+		used = 0
+	}
+	return left - used
+}
+
+var ErrInvalidTimeControl = fmt.Errorf("a time control needs a base time and at most one of an increment or a delay")
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+)
+
+func TestGame_StartClock(t *testing.T) {
+	white := hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA)
+	black := hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA)
+	south := hive.NewCoordinate(0, -1, 1, 0)
+
+	timed := func(t *testing.T, tc TimeControl) (*Game, *ManualClock) {
+		t.Helper()
+		clock := NewManualClock(time.Unix(0, 0))
+		g := New(nil)
+		if err := g.StartClock(tc, clock); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		return g, clock
+	}
+
+	t.Run("When a player acts under a Fischer control the increment is added", func(t *testing.T) {
+		g, clock := timed(t, Fischer(time.Minute, 5*time.Second))
+		clock.Advance(10 * time.Second)
+		if err := g.Place(white, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		clock.Advance(3 * time.Second)
+
+		cs, _ := g.Clock()
+		if cs.White != 55*time.Second || cs.Black != 57*time.Second || cs.Running != hive.BlackColor {
+			t.Errorf("Expected white 55s and black 57s with blacks clock running instead received %#v", cs)
+		}
+	})
+
+	t.Run("When a player acts under a Bronstein control the time used is given back up to the delay", func(t *testing.T) {
+		g, clock := timed(t, Bronstein(time.Minute, 5*time.Second))
+		clock.Advance(3 * time.Second)
+		if err := g.Place(white, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		clock.Advance(8 * time.Second)
+		if err := g.Place(black, south); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+		}
+
+		cs, _ := g.Clock()
+		if cs.White != time.Minute || cs.Black != 57*time.Second {
+			t.Errorf("Expected white 60s and black 57s instead received %#v", cs)
+		}
+	})
+
+	t.Run("When a player runs out of time they lose", func(t *testing.T) {
+		g, clock := timed(t, SuddenDeath(time.Minute))
+		if err := g.Place(white, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if g.Over() {
+			t.Fatal("Expected the game to be running")
+		}
+
+		clock.Advance(time.Minute)
+		if !g.Over() {
+			t.Fatal("Expected the game to be over once black ran out of time")
+		}
+		if winner, err := g.Winner(); err != nil || winner != WhitePlayer {
+			t.Errorf("Expected white to win on time instead received %s, %v", winner, err)
+		}
+		if err := g.Place(black, south); !errors.Is(err, ErrRuleOutOfTime) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleOutOfTime, err)
+		}
+		if actions := g.LegalActions(); len(actions) != 0 {
+			t.Errorf("Expected no legal actions instead received %d", len(actions))
+		}
+	})
+
+	t.Run("When a game is cloned the clock keeps running on the clone", func(t *testing.T) {
+		g, clock := timed(t, SuddenDeath(time.Minute))
+		c := g.Clone()
+		clock.Advance(20 * time.Second)
+		if err := c.Place(white, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+
+		if cs, _ := c.Clock(); cs.White != 40*time.Second {
+			t.Errorf("Expected white to have 40s on the clone instead received %s", cs.White)
+		}
+		if cs, _ := g.Clock(); cs.White != 40*time.Second || cs.Running != hive.WhiteColor {
+			t.Errorf("Expected whites clock to still be running on the original instead received %#v", cs)
+		}
+	})
+
+	t.Run("When actions are played on a timed game the time of each is recorded", func(t *testing.T) {
+		g, clock := timed(t, SuddenDeath(time.Minute))
+		clock.Advance(10 * time.Second)
+		if err := g.Place(white, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		clock.Advance(5 * time.Second)
+		if err := g.Resign(hive.BlackColor); err != nil {
+			t.Fatalf("Unexpected error %#v while black was resigning", err)
+		}
+
+		started, played, ok := g.Timestamps()
+		if !ok || !started.Equal(time.Unix(0, 0)) || len(played) != 2 ||
+			!played[0].Equal(time.Unix(10, 0)) || !played[1].Equal(time.Unix(15, 0)) {
+			t.Errorf("Expected the clock to start at 0s and the actions at 10s and 15s instead received %s, %v", started, played)
+		}
+	})
+
+	t.Run("When an action is played at a time the clock is charged for that time and keeps running", func(t *testing.T) {
+		g, clock := timed(t, SuddenDeath(time.Minute))
+		a := hive.NewAction(hive.Placed, white, hive.Origin, hive.Origin)
+		if err := g.PlayAt(a, time.Unix(10, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if at, ok := g.PlayedAt(0); !ok || !at.Equal(time.Unix(10, 0)) {
+			t.Errorf("Expected the placement at 10s instead received %s", at)
+		}
+		if cs, _ := g.Clock(); cs.White != 50*time.Second {
+			t.Errorf("Expected white to have 50s left instead received %s", cs.White)
+		}
+
+		clock.Advance(30 * time.Second)
+		if err := g.Place(black, south); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+		}
+		if cs, _ := g.Clock(); cs.Black != 40*time.Second {
+			t.Errorf("Expected black to have 40s left instead received %s", cs.Black)
+		}
+	})
+
+	t.Run("When a time control has both an increment and a delay an error is returned", func(t *testing.T) {
+		tc := TimeControl{Base: time.Minute, Increment: time.Second, Delay: time.Second}
+		if err := New(nil).StartClock(tc, nil); !errors.Is(err, ErrInvalidTimeControl) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidTimeControl, err)
+		}
+	})
+
+	t.Run("When a game is not timed there is no clock", func(t *testing.T) {
+		if _, ok := New(nil).Clock(); ok {
+			t.Error("Expected an untimed game to have no clock")
+		}
+	})
+}
@@ -5,7 +5,8 @@
 Package game contains the implementation of the state management and rules engine type.
 The Game type contains manages the state and provides the rules engine interface. This
 interface is described as the two actions a player may take each turn. Those are Place
-and Move. A Place action is where a player takes a piece from their pool and sets it
+and Move, a player that can do neither must Pass. With the Pill Bug feature enabled a
+player may also Throw a piece using their Pill Bug. A Place action is where a player takes a piece from their pool and sets it
 at a particular coordinate on the board while Move is where the player updates the
 location of a piece that has already been placed.
 
@@ -21,6 +22,52 @@ Features
 The engine has implemented feature flags for rules beyond the base game. These rules
 may be toggled on and off at the instantiation of the game type.
 
+Inventories
+
+The players start with the standard set of pieces unless New is given an Inventory, one for both players or one for
+each, for house rules, handicaps, and teaching setups. A player without a queen in their inventory may move their
+pieces from the start and can't lose by suffocation. Expansion bugs in an inventory still need their feature enabled.
+
+Handicaps
+
+A Handicap evens a game between players of different strength. Applied with ApplyHandicap before the first action it
+removes pieces from the inventory of a player, gives a player extra placements at the start of the game, or puts back
+the turn by which a player must have placed their queen. Initial returns a new game set up the same way, which is how
+replays, encodings, and records reproduce a handicap game.
+
+Rule Sets
+
+Every action is checked against the RuleSet of the game, lists of turn, placement, and movement validators, and its
+victory rules decide when a game on the board is over. StandardRules returns the rules of the game as a Variant whose
+lists may be changed, a house variant adds its own validators, replaces, or leaves out standard ones and is applied with
+ApplyRules before the first action. A validator refuses an action by returning an error, which the game wraps in a
+*RuleError unless it's one already.
+
+Events
+
+A Listener may be registered with a Game to be notified after each change to its state, for
+example when a piece is placed, moved, thrown, or paralyzed and when the game ends.
+
+Clocks
+
+A game may be played under a time control with StartClock, using Fischer increments, Bronstein delays, or sudden
+death. The time is read from a Clock, which tests replace with a ManualClock. A player that runs out of time loses the
+game, Over reports it and Winner names their opponent.
+
+Results
+
+Result reports how a game ended along with the winner. Besides a queen being surrounded a game
+ends when a player resigns with Resign, a draw is agreed to with AgreeDraw, a result is declared
+from outside of the game with Adjudicate, the same position comes up for the third time, or a
+player runs out of time. Resignations, draws, adjudications, and timeouts are recorded in the
+history with their own actions so that replaying a history reproduces the result.
+
+Replays
+
+A Replay steps through the positions of a history, forward and back or straight to a ply with Seek, and reads the
+board, the hands, and the result at each of them. Copies of the game are kept at intervals so that seeking doesn't
+replay the history from the start.
+
 Types and Values
 
 The Game type should act as the primary interface for the library if you want to just
@@ -36,12 +83,28 @@ attempted interaction with the game state is invalid.
 State Errors
 
 - ErrGameNotOver : Returned when using the Winner interface and the game hasn't reached an end state.
+- ErrGameOver : Returned when acting on a game that has already ended.
+- ErrGameStarted : Returned when applying a handicap or rules to a game that already has actions.
+- ErrInvalidHandicap : Returned when a handicap can't be met by the inventories of the game.
+- ErrTimeRemaining : Returned when claiming a timeout while the player still has time.
 - ErrUnknownPiece : Returned when attempting to place a piece that isn't recognized by the engine.
+- ErrUnknownAction : Returned when attempting to play an action that isn't recognized by the engine.
+- ErrPlyOutOfRange : Returned when seeking a replay to a ply outside of its history.
 - ErrUnknownBoardError : Returned if there is an unexpected error while updating the state of the board.
 
 Rule Errors
 
 Returned when either a Place or a Move action violates a rule of the game. For more information see
 the rules file. These errors should be very specific and clear when compared to the rules of the game.
+
+Rule errors are returned as a *RuleError which carries a stable RuleID along with the piece, the
+coordinates, and the conflicting pieces or formation involved. A RuleError wraps the sentinel for
+the rule so errors.Is may still be used to check for a specific rule.
+
+Explaining Moves
+
+Move stops at the first rule a movement breaks. Explain checks the same rules without changing
+the game and reports every one of them, which is what a tutorial wants when a player asks why a
+piece can't move.
 */
 package game
@@ -1,8 +1,11 @@
 package game
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	. "github.com/theshadow/hive"
 )
@@ -16,9 +19,17 @@ type Game struct {
 	// tracks which players turn it is, WhiteColor or BlackColor.
 	turn uint8
 
-	// These track the state of each player
-	white *Player
-	black *Player
+	// These track the pieces each player has left to place
+	white Stock
+	black Stock
+
+	// the handicap the game was set up with and the extra placements of each player that are left, indexed by color
+	// less one. See ApplyHandicap.
+	handicap        Handicap
+	extraPlacements [2]int
+
+	// the rules the game is played by, nil for the standard rules. See ApplyRules.
+	rules RuleSet
 
 	// Track the coordinate of each queen to quickly detect victory
 	// states without having to perform an O(n) over all pieces
@@ -26,8 +37,13 @@ type Game struct {
 	whiteQueen Coordinate
 	blackQueen Coordinate
 
-	// When true the game is determined to be a tie.
-	tie bool
+	// set when the game was ended by an action that doesn't touch the board, a resignation for example. See Result.
+	result Result
+
+	// the hash of the position after each board action along with whether a position came up for the third time,
+	// which draws the game.
+	positions []uint64
+	repeated  bool
 
 	// Current board state
 	board *Board
@@ -39,26 +55,46 @@ type Game struct {
 	// time till free value. When the value is zero, the piece is removed from the map
 	// and freed.
 	//
-	// After each turn the value is decremented by one.
+	// After each players action the value is decremented by one.
 	paralyzedPieces map[Coordinate]int
 
 	// maps a Feature to a boolean. When the boolean is true the feature is
 	// enabled.
 	features map[Feature]bool
+
+	// notified after each change to the state of the game, see Listener.
+	listeners []Listener
+
+	// set once the listeners have been told the game is over so that they're only told once.
+	overNotified bool
+
+	// tracks the time of the players in a timed game, nil when the game isn't timed. See StartClock.
+	timer *timer
 }
 
-func New(features []Feature) *Game {
+// New returns a game with the features enabled. The players start with the standard set of pieces unless inventories
+// are given, a single inventory is the set of both players and two are the sets of white and black.
+func New(features []Feature, inventories ...Inventory) *Game {
 	featureMap := copyFeatureMap()
 	if features != nil {
 		for _, f := range features {
 			featureMap[f] = true
 		}
 	}
+
+	var white, black Stock = NewPlayer(), NewPlayer()
+	switch {
+	case len(inventories) == 1:
+		white, black = inventories[0].Stock(), inventories[0].Stock()
+	case len(inventories) > 1:
+		white, black = inventories[0].Stock(), inventories[1].Stock()
+	}
+
 	return &Game{
 		turns:           1, // makes math clearer and makes more sense to start at 1 instead of 0
 		turn:            WhiteColor,
-		white:           NewPlayer(),
-		black:           NewPlayer(),
+		white:           white,
+		black:           black,
 		board:           NewBoard(),
 		history:         []Action{},
 		paralyzedPieces: make(map[Coordinate]int),
@@ -67,6 +103,46 @@ func New(features []Feature) *Game {
 
 }
 
+// Clone returns an independent copy of the game. Acting on the copy never changes the original, which makes it safe to
+// use for speculative analysis or to hand to another goroutine.
+func (g *Game) Clone() *Game {
+	c := &Game{
+		turns:           g.turns,
+		turn:            g.turn,
+		white:           g.white.Copy(),
+		black:           g.black.Copy(),
+		handicap:        g.handicap,
+		extraPlacements: g.extraPlacements,
+		rules:           g.rules,
+		whiteQueen:      g.whiteQueen,
+		blackQueen:      g.blackQueen,
+		result:          g.result,
+		positions:       make([]uint64, len(g.positions), cap(g.positions)),
+		repeated:        g.repeated,
+		overNotified:    g.overNotified,
+		board:           g.board.Clone(),
+		history:         make([]Action, len(g.history), cap(g.history)),
+		paralyzedPieces: make(map[Coordinate]int, len(g.paralyzedPieces)),
+		features:        make(map[Feature]bool, len(g.features)),
+	}
+	copy(c.history, g.history)
+	copy(c.positions, g.positions)
+	if g.timer != nil {
+		t := *g.timer
+		t.played = make([]time.Time, len(g.timer.played))
+		copy(t.played, g.timer.played)
+		c.timer = &t
+	}
+	for k, v := range g.paralyzedPieces {
+		c.paralyzedPieces[k] = v
+	}
+	for k, v := range g.features {
+		c.features[k] = v
+	}
+
+	return c
+}
+
 // Place will accept a piece and a coordinate and attempt to place it on the board at the specified coordinate
 // if the specified coordinate is an invalid space due to game rules or if the player does not have the piece to
 // place it will return an error.
@@ -80,56 +156,26 @@ func New(features []Feature) *Game {
 // - If it's not the first turn that the paced piece is not in contact with an opponents piece.
 // - If there is a piece where this piece is attempting to be placed at.
 //
+// The rules above are those of StandardRules, a game may be played by others, see ApplyRules.
+//
 // Once the placement has been validating it will update the state of the history. Also note that if the piece moved was
 // a queen piece the location of that piece for that player will be updated.
 //
 // Finally, the function will toggle whose turn it is.
 func (g *Game) Place(p Piece, c Coordinate) error {
-	// Is it this players turn to place a piece?
-	if p.Color() != g.turn {
-		return ErrRuleNotPlayersTurn
-	}
-
-	// the first piece to be placed must be at origin
-	if g.turns == FirstTurn && g.turn == WhiteColor && c != Origin {
-		return ErrRuleFirstPieceMustBeAtOrigin
-	}
-
-	player := g.currentPlayer()
-
-	// take a piece
-	if err := g.takeAPiece(p, player); err != nil {
+	if err := g.checkClock(Placed, Origin, c); err != nil {
 		return err
 	}
-
-	// If it is the fourth turn and the player has a queen in their inventory and the piece being placed is not a queen
-	// then the player must place a queen.
-	if g.turns == FourthTurn && player.HasQueen() && !p.IsQueen() {
-		return ErrRuleMustPlaceQueen
+	if g.Over() {
+		return ErrGameOver
 	}
-
-	// If where the piece is being placed is above the surface of the board and there isn't a piece below the the piece
-	// then this is an invalid move.
-	var h int8
-	if c.H() > 0 {
-		h--
-	}
-	cc := NewCoordinate(c.X(), c.Y(), c.Z(), h)
-	if _, existing := g.board.Cell(cc); !existing && c.H() > 0 {
-		return ErrRuleMustPlacePieceOnSurface
-	}
-
-	// If the feature flag for tournament rules is enabled then the first piece placed must not be a queen.
-	if g.turns == FirstTurn && g.featureEnabled(TournamentQueensRuleFeature) && p.IsQueen() {
-		return ErrRuleMayNotPlaceQueenOnFirstTurn
+	if err := g.validatePlace(p, c); err != nil {
+		return err
 	}
 
-	// Validate that every piece placed after the first turn is not in contact with an opponents piece.
-	if g.turns != FirstTurn {
-		// we must allow the players to place pieces that touch each other on the first turn, but never again.
-		if neighbors := g.board.Neighbors(c); contactWithOpponentsPiece(p, neighbors) {
-			return ErrRuleMayNotPlaceTouchingOpponentsPiece
-		}
+	// take a piece, the validation already proved the player has one to take
+	if err := g.takeAPiece(p, g.currentPlayer()); err != nil {
+		return err
 	}
 
 	// place the piece, we're not allowed to place two pieces at the same coordinate
@@ -144,191 +190,528 @@ func (g *Game) Place(p Piece, c Coordinate) error {
 
 	// turn management
 	if p.IsQueen() {
-		g.updatePlayerQueen(c)
+		g.updateQueen(p, c)
 	}
 
-	g.toggleTurn()
+	// a handicap may give the player another placement before the turn passes
+	if g.takeExtraPlacement() {
+		g.keepTurn()
+	} else {
+		g.toggleTurn()
+	}
+	g.notify()
 
 	return nil
 }
 
 // Move accepts two coordinates and attempts to move the piece found at (a) to (b).
 // It will return an error if the movement violates any game rules or if the specified
-// coordinate for (a) is invalid.
-// TODO: Implement rules for movement
+// coordinate for (a) is invalid. The movement is checked against the movement rules of the game, see RuleSet, where
+// the movement of each bug comes from its definition in DefaultBugs.
 func (g *Game) Move(a, b Coordinate) error {
-	// Is this a valid piece to move?
-	piece, ok := g.board.Cell(a)
-	if !ok {
-		return ErrInvalidCoordinate
+	if err := g.checkClock(Moved, a, b); err != nil {
+		return err
 	}
-
-	// Verify that the source and destination are not at the same coordinate
-	if a == b {
-		return ErrInvalidCoordinate
+	if g.Over() {
+		return ErrGameOver
 	}
-
-	// figure out which player we should be working with
-	player := g.currentPlayer()
-
-	// Is this currentPlayer allowed to move?
-	if piece.Color() != g.turn {
-		return ErrRuleNotPlayersTurn
+	piece, err := g.validateMove(a, b)
+	if err != nil {
+		return err
 	}
 
-	// If the player hasn't placed their queen they cannot   a piece
-	if player.HasQueen() {
-		return ErrRuleMustPlaceQueenToMove
+	// Act the piece
+	if err := g.board.Move(a, b); errors.Is(err, ErrPauliExclusionPrinciple) {
+		return ErrRuleMayNotPlaceAPieceOnAPiece
+	} else if err != nil {
+		return &ErrUnknownBoardError{err}
 	}
 
-	// If the formation of the neighbors is pinning the piece at the specified coordinate
-	// then it may not move.
-	if neighbors := g.board.Neighbors(a); Formation(neighbors).IsPinned() {
-		return ErrRulePiecePinned
-	}
+	// update the history
+	g.history = append(g.history, NewAction(Moved, piece, a, b))
 
-	// if the piece is paralyzed the player can't move it
-	if g.featureEnabled(PillBugPieceFeature) && g.pieceIsParalyzed(a) {
-		return ErrRulePieceParalyzed
+	// turn management
+	if piece.IsQueen() {
+		g.updateQueen(piece, b)
 	}
 
-	// TODO: implement the check for splitting the hive
-	// If it can slide, and there are four neighbors there is no split.
-	// After some thought I'm wondering if I can use the pathing algorithm to look for a split in the hive.
-	// I would use the moving piece as the origin and assign a weighted value to each other piece such that the
+	g.toggleTurn()
+	g.notify()
 
-	// TODO: implement path validation
-	// Is this move valid?
-	//     - Can this piece move to this location (pathing)
-	//     no: ErrInvalidMove
-	if err := g.path(a, b, piece); err != nil {
+	return nil
+}
+
+// Throw uses the special ability of the Pill Bug to move the piece at (a) to (b). The Pill Bug, or a Mosquito touching
+// a Pill Bug, belonging to the current player must be touching both coordinates. The piece is carried up on to the
+// Pill Bug and back down to (b), it may belong to either player. After being thrown the piece is paralyzed and may
+// not move, or be thrown, on the next turn.
+//
+// Throw requires the Pill Bug feature to be enabled.
+//
+// Rules Checked
+// - If there is a piece at (a) and (b) is an empty cell on the surface of the board
+// - If the player has placed their queen
+// - If the piece at (a) is not part of a stack, not paralyzed, and wasn't the last piece to be moved
+// - If there is a Pill Bug that touches both (a) and (b), that isn't covered, paralyzed, or the last piece moved
+func (g *Game) Throw(a, b Coordinate) error {
+	if err := g.checkClock(Thrown, a, b); err != nil {
+		return err
+	}
+	if g.Over() {
+		return ErrGameOver
+	}
+	piece, err := g.validateThrow(a, b)
+	if err != nil {
 		return err
 	}
 
-	// Act the piece
 	if err := g.board.Move(a, b); errors.Is(err, ErrPauliExclusionPrinciple) {
 		return ErrRuleMayNotPlaceAPieceOnAPiece
 	} else if err != nil {
 		return &ErrUnknownBoardError{err}
 	}
 
-	// update the history
-	g.history = append(g.history, NewAction(Placed, piece, a, b))
+	g.history = append(g.history, NewAction(Thrown, piece, a, b))
 
-	// turn management
 	if piece.IsQueen() {
-		g.updatePlayerQueen(b)
+		g.updateQueen(piece, b)
+	}
+
+	// the piece was just moved so it can't already be paralyzed
+	_ = g.paralyzePiece(b)
+
+	g.toggleTurn()
+	g.notify()
+
+	return nil
+}
+
+// Pass will end the current players turn without them acting. A player may only pass when there is no other action
+// available to them.
+func (g *Game) Pass() error {
+	if err := g.checkClock(Passed, Origin, Origin); err != nil {
+		return err
+	}
+	if g.Over() {
+		return ErrGameOver
+	}
+	a := NewAction(Passed, ZeroPiece, 0, 0)
+	for _, rule := range g.RuleSet().TurnRules() {
+		if err := rule(g, a); err != nil {
+			return violation(err, a)
+		}
 	}
 
+	g.history = append(g.history, a)
 	g.toggleTurn()
+	g.notify()
 
 	return nil
 }
 
+// Play performs a recorded action, it's a convenience for replaying a history. See Place, Move, Throw, and Pass for
+// the errors it returns. The actions that end a game without touching the board are played with Resign, AgreeDraw,
+// Adjudicate, and ClaimTimeout.
+func (g *Game) Play(a Action) error {
+	switch a.Act() {
+	case Placed:
+		return g.Place(a.Piece(), a.Dst())
+	case Moved:
+		return g.Move(a.Src(), a.Dst())
+	case Thrown:
+		return g.Throw(a.Src(), a.Dst())
+	case Passed:
+		return g.Pass()
+	case Resigned, Drawn, Adjudicated, TimedOut:
+		return g.playConclusion(a)
+	}
+	return ErrUnknownAction
+}
+
 // Winner returns the player that won the game, if the game is not over
-// this method will return an error.
+// this method will return an error. See Result for how the game was won.
 //
 // If there is a tie it will return a ZeroPlayer with a nil error.
 func (g *Game) Winner() (Winner, error) {
-	if !g.Over() {
-		return 0, ErrGameNotOver
+	r, err := g.Result()
+	return r.Winner, err
+}
+
+// Over If either player has a suffocating queen then the game is over. The game is also over once a player resigns,
+// a draw is agreed or adjudicated, the same position comes up for the third time, or in a timed game the player whose
+// turn it is runs out of time, see StartClock.
+func (g *Game) Over() bool {
+	_, over := g.outcome()
+	return over
+}
+
+// suffocating returns true when the queen of the color has been placed and is surrounded.
+func (g *Game) suffocating(color uint8) bool {
+	c, placed := g.Queen(color)
+	return placed && Formation(g.board.Neighbors(c)).IsSuffocating()
+}
+
+// History will populate the supplied slice with a copy of the
+// actions performed for this game instance.
+func (g *Game) History() []Action {
+	history := make([]Action, len(g.history))
+	copy(history, g.history)
+	return history
+}
+
+// MarshalJSON encodes the game with its features, the state of the board, and the history. The state is there for the
+// benefit of clients, a game is decoded by replaying the history, see UnmarshalJSON.
+func (g *Game) MarshalJSON() ([]byte, error) {
+	v := jsonGame{
+		Features: []string{},
+		Turn:     colorName(g.turn),
+		Turns:    g.turns,
+		Over:     g.Over(),
+		Board:    []jsonCell{},
+		History:  g.History(),
+	}
+	for _, f := range g.Features() {
+		v.Features = append(v.Features, f.String())
+	}
+	if r, err := g.Result(); err == nil {
+		v.Winner = r.Winner.String()
+		v.Result = &r
+	}
+	if cs, ok := g.Clock(); ok {
+		v.Clock = &cs
+	}
+	if !g.handicap.IsZero() {
+		v.Handicap = &g.handicap
+	}
+	for _, color := range []uint8{WhiteColor, BlackColor} {
+		if inv := g.Inventory(color); inv != StandardInventory {
+			if v.Inventories == nil {
+				v.Inventories = make(map[string]Inventory)
+			}
+			v.Inventories[colorName(color)] = inv
+		}
 	}
+	for _, cl := range g.board.Pieces() {
+		v.Board = append(v.Board, jsonCell{Piece: cl.Piece, Coordinate: cl.Coordinate})
+	}
+	sort.Slice(v.Board, func(i, j int) bool { return v.Board[i].Coordinate < v.Board[j].Coordinate })
+
+	return json.Marshal(v)
+}
 
-	if g.tie {
-		return Tie, nil
+// UnmarshalJSON rebuilds the game by replaying its history against a new game with the same features, so a document
+// with an illegal history is rejected with the rule error of the first illegal action. Listeners registered with the
+// game are kept, as are the rules applied to it, the history is replayed with them. The clock isn't restored, a decoded
+// game is untimed until StartClock is called.
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var v jsonGame
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
 	}
 
-	// As we toggle the player at the end of a turn we determine
-	// the winner to be the person who the current player isn't.
-	var winner Winner
-	if !g.currentPlayer().IsWhite() {
-		winner = WhitePlayer
-	} else {
-		winner = BlackPlayer
+	var features []Feature
+	for _, name := range v.Features {
+		f, err := ParseFeature(name)
+		if err != nil {
+			return err
+		}
+		features = append(features, f)
+	}
+
+	white, black := StandardInventory, StandardInventory
+	for name, inv := range v.Inventories {
+		switch name {
+		case colorName(WhiteColor):
+			white = inv
+		case colorName(BlackColor):
+			black = inv
+		default:
+			return fmt.Errorf("%w: unknown color %q", ErrInvalidEncoding, name)
+		}
+	}
+	replayed := New(features, white, black)
+	replayed.rules = g.rules
+	if v.Handicap != nil {
+		if err := replayed.ApplyHandicap(*v.Handicap); err != nil {
+			return err
+		}
+	}
+	for i, a := range v.History {
+		if err := replayed.Play(a); err != nil {
+			return fmt.Errorf("action %d of the history: %w", i+1, err)
+		}
 	}
 
-	return winner, nil
+	replayed.listeners = g.listeners
+	*g = *replayed
+	return nil
 }
 
-// Over If either player has a suffocating queen then the game is over.
-func (g *Game) Over() bool {
-	// if both players have their queen then the game is not over.
-	if g.black.HasQueen() && g.white.HasQueen() {
-		return false
+// validatePlace checks if placing the piece at the coordinate is allowed by the rules of the game without changing
+// its state. Rule violations are returned as a *RuleError.
+func (g *Game) validatePlace(p Piece, c Coordinate) error {
+	if !knownBug(p.Bug()) {
+		return ErrUnknownPiece
 	}
 
-	whiteSuffocating := false
-	blackSuffocating := false
+	rules := g.RuleSet()
+	a := NewAction(Placed, p, Origin, c)
+	for _, rule := range rules.TurnRules() {
+		if err := rule(g, a); err != nil {
+			return violation(err, a)
+		}
+	}
+	for _, rule := range rules.PlacementRules() {
+		if err := rule(g, p, c); err != nil {
+			return violation(err, a)
+		}
+	}
+
+	return nil
+}
 
-	// have they placed their queen?
-	if g.black.HasQueen() == false {
-		neighbors := g.board.Neighbors(g.blackQueen)
-		formation := Formation(neighbors)
-		blackSuffocating = formation.IsSuffocating()
+// validateMove checks if moving the piece at (a) to (b) is allowed without changing the state of the game. It returns
+// the piece that would be moved. Rule violations are returned as a *RuleError.
+func (g *Game) validateMove(a, b Coordinate) (Piece, error) {
+	piece, violations, err := g.moveViolations(a, b, false)
+	if err != nil {
+		return ZeroPiece, err
+	} else if len(violations) > 0 {
+		return ZeroPiece, violations[0]
 	}
+	return piece, nil
+}
 
-	if g.white.HasQueen() == false {
-		neighbors := g.board.Neighbors(g.whiteQueen)
-		formation := Formation(neighbors)
-		whiteSuffocating = formation.IsSuffocating()
+// moveViolations runs the rules for moving the piece at (a) to (b) in the order Move reports them. When all is false it
+// stops at the first violation, otherwise every violated rule is returned. An error is only returned when there is no
+// piece to move.
+func (g *Game) moveViolations(a, b Coordinate, all bool) (Piece, []*RuleError, error) {
+	// Is this a valid piece to move?
+	piece, ok := g.board.Cell(a)
+	if !ok {
+		return ZeroPiece, nil, ErrInvalidCoordinate
 	}
 
-	// tie
-	if blackSuffocating && whiteSuffocating {
-		g.tie = true
-		return true
-	} else if blackSuffocating || whiteSuffocating {
-		return true
+	// Verify that the source and destination are not at the same coordinate
+	if a == b {
+		return ZeroPiece, nil, ErrInvalidCoordinate
 	}
 
-	return false
+	// the turn rules come first, the same as for a placement
+	rules := g.RuleSet()
+	act := NewAction(Moved, piece, a, b)
+	var violations []*RuleError
+	for _, rule := range rules.TurnRules() {
+		if err := rule(g, act); err != nil {
+			if violations = append(violations, violation(err, act)); !all {
+				return piece, violations, nil
+			}
+		}
+	}
+	for _, rule := range rules.MovementRules() {
+		if err := rule(g, piece, a, b); err != nil {
+			if violations = append(violations, violation(err, act)); !all {
+				return piece, violations, nil
+			}
+		}
+	}
+
+	return piece, violations, nil
 }
 
-// History will populate the supplied slice with a copy of the
-// actions performed for this game instance.
-func (g *Game) History() []Action {
-	history := make([]Action, len(g.history))
-	copy(history, g.history)
-	return history
+// validateThrow checks if the current player may throw the piece at (a) to (b) without changing the state of the
+// game. It returns the piece that would be thrown. Rule violations are returned as a *RuleError.
+func (g *Game) validateThrow(a, b Coordinate) (Piece, error) {
+	piece, ok := g.board.Cell(a)
+
+	fail := func(err error) *RuleError {
+		return newRuleError(err, Thrown, piece, a, b)
+	}
+
+	if !g.featureEnabled(PillBugPieceFeature) {
+		return ZeroPiece, fail(ErrRuleNoPillBugToThrow)
+	}
+
+	if !ok || a == b {
+		return ZeroPiece, ErrInvalidCoordinate
+	}
+
+	act := NewAction(Thrown, piece, a, b)
+	for _, rule := range g.RuleSet().TurnRules() {
+		if err := rule(g, act); err != nil {
+			return ZeroPiece, violation(err, act)
+		}
+	}
+
+	// The ability is a movement, so the queen must be placed first
+	if queenInHand(g.currentPlayer()) {
+		return ZeroPiece, fail(ErrRuleMustPlaceQueenToMove)
+	}
+
+	// The piece can't be part of a stack, either under or on top of another piece
+	if above, covered := g.board.Cell(a.Add(NeighborsMatrix[Above])); covered || a.H() != 0 {
+		return ZeroPiece, fail(ErrRuleMayNotThrowStackedPiece).withConflicts(above)
+	}
+
+	if g.pieceIsParalyzed(a) {
+		return ZeroPiece, fail(ErrRulePieceParalyzed)
+	}
+
+	if g.lastMoved(a) {
+		return ZeroPiece, fail(ErrRuleMayNotThrowLastMovedPiece)
+	}
+
+	if g.splitsHive(a) {
+		return ZeroPiece, fail(ErrRuleMayNotSplitHive)
+	}
+
+	if b.H() != 0 {
+		return ZeroPiece, fail(ErrRuleMustPlacePieceOnSurface)
+	}
+	if occupant, ok := g.board.Cell(b); ok {
+		return ZeroPiece, fail(ErrRuleMayNotPlaceAPieceOnAPiece).withConflicts(occupant)
+	}
+
+	if _, ok := g.thrower(a, b); !ok {
+		return ZeroPiece, fail(ErrRuleNoPillBugToThrow)
+	}
+
+	return piece, nil
 }
 
-func (g *Game) UnmarshalJSON([]byte) error {
-	return nil
+// thrower returns the coordinate of a piece belonging to the current player that may use the Pill Bug ability to
+// throw from (a) to (b).
+func (g *Game) thrower(a, b Coordinate) (Coordinate, bool) {
+	for _, c := range neighbors(a)[:Above] {
+		if distance(c, b) != 1 {
+			continue
+		}
+		p, ok := g.board.Cell(c)
+		if !ok || p.Color() != g.turn || !g.hasPillBugAbility(p, c) {
+			continue
+		}
+		if _, covered := g.board.Cell(c.Add(NeighborsMatrix[Above])); covered {
+			continue
+		}
+		if g.pieceIsParalyzed(c) || g.lastMoved(c) {
+			continue
+		}
+		return c, true
+	}
+	return Origin, false
 }
 
-func (g *Game) MarshalJSON() ([]byte, error) {
-	return nil, nil
+// hasPillBugAbility is true for Pill Bugs and for Mosquitoes that are touching a Pill Bug.
+func (g *Game) hasPillBugAbility(p Piece, c Coordinate) bool {
+	if p.IsPillBug() {
+		return true
+	}
+	if !p.IsMosquito() || !g.featureEnabled(MosquitoPieceFeature) {
+		return false
+	}
+	for _, n := range g.board.Neighbors(c) {
+		if n.IsPillBug() {
+			return true
+		}
+	}
+	return false
+}
+
+// lastMoved returns true when the piece at the coordinate was the piece moved by the previous action.
+func (g *Game) lastMoved(c Coordinate) bool {
+	if len(g.history) == 0 {
+		return false
+	}
+	last := g.history[len(g.history)-1]
+	return (last.WasMoved() || last.WasThrown()) && last.Dst() == c
 }
 
-func (g *Game) updatePlayerQueen(c Coordinate) {
-	if g.currentPlayer().IsWhite() {
+// splitsHive returns true when removing the piece at the coordinate would leave the pieces on the board in more than
+// one group. A piece on top of a stack never splits the hive as the piece below it keeps the stack connected.
+func (g *Game) splitsHive(c Coordinate) bool {
+	if c.H() != 0 {
+		return false
+	}
+	if _, covered := g.board.Cell(c.Add(NeighborsMatrix[Above])); covered {
+		return false
+	}
+
+	// Only the ground matters, every stack stands on a piece on the ground.
+	remaining := make(map[Coordinate]bool)
+	for _, cl := range g.board.Pieces() {
+		if cl.Coordinate.H() == 0 && cl.Coordinate != c {
+			remaining[cl.Coordinate] = true
+		}
+	}
+	// Flood fill from one of the neighbors, if that doesn't reach the other neighbors the hive is split.
+	var around []Coordinate
+	for _, n := range neighbors(c)[:Above] {
+		if remaining[n] {
+			around = append(around, n)
+		}
+	}
+	if len(around) < 2 {
+		return false
+	}
+
+	seen := map[Coordinate]bool{around[0]: true}
+	queue := []Coordinate{around[0]}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, n := range neighbors(current)[:Above] {
+			if remaining[n] && !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	for _, n := range around[1:] {
+		if !seen[n] {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) updateQueen(p Piece, c Coordinate) {
+	if p.IsWhite() {
 		g.whiteQueen = c
 	} else {
 		g.blackQueen = c
 	}
 }
-func (g *Game) takeAPiece(p Piece, player *Player) error {
-	if p.IsQueen() {
-		return player.TakeQueen()
-	} else if p.IsAnt() {
-		return player.TakeAnAnt()
-	} else if p.IsGrasshopper() {
-		return player.TakeAGrasshopper()
-	} else if p.IsSpider() {
-		return player.TakeASpider()
-	} else if p.IsBeetle() {
-		return player.TakeABeetle()
-	} else if p.IsLadybug() {
-		return player.TakeLadybug()
-	} else if p.IsMosquito() {
-		return player.TakeMosquito()
-	} else if p.IsPillBug() {
-		return player.TakePillBug()
-	}
-	return ErrUnknownPiece
-}
-
-func (g *Game) currentPlayer() *Player {
+func (g *Game) takeAPiece(p Piece, player Stock) error {
+	if !knownBug(p.Bug()) {
+		return ErrUnknownPiece
+	}
+	return player.Take(p.Bug())
+}
+
+// knownBug returns true for the bugs of the game and those registered with DefaultBugs.
+func knownBug(bug uint8) bool {
+	_, ok := DefaultBugs.Lookup(bug)
+	return ok
+}
+
+// placedAny returns true once the player has placed a piece.
+func placedAny(player Stock) bool {
+	for _, bug := range DefaultBugs.Bugs() {
+		if player.Remaining(bug) < player.Total(bug) {
+			return true
+		}
+	}
+	return false
+}
+
+// queenInHand returns true while the player has yet to place their queen. A player whose inventory has no queen never
+// has to place one.
+func queenInHand(player Stock) bool {
+	return player.Remaining(Queen) > 0
+}
+
+func (g *Game) currentPlayer() Stock {
 	// figure out which player we should be working with
 	if g.turn == WhiteColor {
 		return g.white
@@ -336,15 +719,29 @@ func (g *Game) currentPlayer() *Player {
 	return g.black
 }
 
+// keepTurn ends an action that the player follows with another of their own, the turn doesn't pass to the opponent.
+func (g *Game) keepTurn() {
+	g.tickParalyzedPieces()
+	g.chargeClock()
+	g.trackRepetition()
+}
+
 func (g *Game) toggleTurn() {
+	g.tickParalyzedPieces()
+	g.chargeClock()
+
 	if g.turn == WhiteColor {
 		g.turn = BlackColor
 	} else {
-		g.tickParalyzedPieces()
-
 		g.turn = WhiteColor
 		g.turns++
 	}
+	g.trackRepetition()
+
+	// the clocks stop once the game is decided on the board
+	if g.timer != nil && g.Over() {
+		g.stopClock()
+	}
 }
 
 func (g *Game) pieceIsParalyzed(c Coordinate) bool {
@@ -357,7 +754,7 @@ func (g *Game) paralyzePiece(c Coordinate) error {
 	if _, ok := g.paralyzedPieces[c]; ok {
 		return ErrRulePieceAlreadyParalyzed
 	}
-	g.paralyzedPieces[c] = 1
+	g.paralyzedPieces[c] = paralysisDuration
 	return nil
 }
 
@@ -379,166 +776,87 @@ func (g *Game) featureEnabled(f Feature) bool {
 	return enabled
 }
 
-// Attempt to path from a to b with piece p. Returns a slice of coordinates
-// that represents the discovered path.
-//
-// Uses the modified A* algorithm found at https://www.redblobgames.com/pathfinding/a-star/introduction.html
-// with additional rules specific for the game.
-//
-//
-// 1. Check if it's a climbing bug and if the distances from a to b is greater than allowed
-// 2. Attempt to discover a path to the destination
-// 3. Check if this is a bug with a range limit
-//
-// TODO: What if they path to a void?
-// TODO: What if they attempt to path through a space that would violate the rule of sliding?
-// TODO: How does ladybug movement work? Can only move TWO on top.
-// Probably a modified path algorithm where any cell with a piece within a distance of
-// 2 from the ladybug is considered to have a height of zero?
-// TODO: How does pill bug movement work? Could these be implemented as custom path rules?
-// What if part of the pathing rules allowed the bug to modify the terrain? None of
-// the pieces have height limits for their movement so we could create a terrain
-// mask that made the pathing algorithm see those cells as empty. Thus a lady
-// bug can path over pieces. That might work, I think there are some edge cases.
-// TODO: How does mosquito movement work? This may be another custom Act function,
-//       can I generalize?
-// If A is a mosquito, calculate for each bug type adjacent if B is a valid point
-// in that pieces path algorithm move the piece return nil
-//
-// If A is a piece touching a pill bug / (mosquito:pill bug) of the right color
-// and A is not paralyzed, and the pill bug / (mosquito:pill bug) is not paralyzed,
-// and B is an empty cell. Act A to B and return nil.
-// TODO: Potentially return the path as []Coordinate for rendering engines this would
-//   require making this an exported function receiver.
-func (g *Game) path(a, b Coordinate, p Piece) error {
-	// TODO this looks like it's off by one.
-	dist := distance(a, b)
-
-	// is the distance to great for this piece? As beetles and ladybugs
-	// can climb on things we check if we can move to the destination
-	// ignoring the distance and cost checks.
-	// We do this here as if the distance is too great we don't
-	// want to spend time on a pricey a* lookup.
-	// TODO should I group all of the distance checks together? Determine if order is important for this check.
-	if bug := g.pieceProfile(p, a); bug.IsClimber() {
-		if p.IsBeetle() && dist > beetleMaxDistance {
-			return ErrRuleMovementDistanceTooGreat
-		} else if p.IsLadybug() && dist > ladybugMaxDistance {
-			return ErrRuleMovementDistanceTooGreat
+// opponentsPieces returns the neighbors that belong to the opponent of the piece.
+func opponentsPieces(p Piece, neighbors [7]Piece) (pieces []Piece) {
+	for _, n := range neighbors {
+		if n != ZeroPiece && n.Color() != p.Color() {
+			pieces = append(pieces, n)
 		}
 	}
+	return pieces
+}
 
-	// TODO: if piece is jumper (Grasshopper) calculate pathing for a straight line
-	// TODO: pill bug pathing??? path MUST route through pill bug?
-	//     1. A must be touching a pill bug of the current players color
-	//     2. The adjoining pill bug and the piece at A MUST NOT be paralyzed
-	//     3. B must be a valid empty cell that is also touching the same pill bug
-
-	frontier := make(PriorityQueue, 127)
-	costs := map[Coordinate]int{a: 0}
-	from := map[Coordinate]Coordinate{a: Origin}
-
-	for frontier.Len() > 0 {
-		item := frontier.Pop()
-		if item == nil {
-			break
-		}
-		current := item.(Coordinate)
-		if current == b {
-			break
+func contactWithOpponentsPiece(p Piece, neighbors [7]Piece) bool {
+	for _, n := range neighbors {
+		// don't care about zero pieces
+		if n == ZeroPiece {
+			continue
 		}
 
-		for _, next := range neighbors(current) {
-			cost := costs[current] + g.movementCost(current, next, p)
-			// if not in the previous map or if the movementCost of moving to this location costs
-			// less than our current movementCost calculation from this location then we will adjust
-			if _, fromOK, curCost, _ := idx(next, from, costs); !fromOK || cost < curCost {
-				costs[next] = cost
-				priority := cost + heuristic(b, next)
-				frontier.Push(&Item{
-					Location: next,
-					Priority: priority,
-				})
-				from[next] = current
-			}
+		if n.Color() != p.Color() {
+			return true
 		}
 	}
 
-	// Check if the path is greater than the max distance for pieces
-	dist = len(from)
-	if p.IsQueen() && dist > queenMaxDistance {
-		return ErrRuleMovementDistanceTooGreat
-	} else if p.IsSpider() && dist > spiderMaxDistance {
-		return ErrRuleMovementDistanceTooGreat
-	} else if p.IsPillBug() && dist > pillBugMaxDistance {
-		return ErrRuleMovementDistanceTooGreat
-	}
-
-	return nil
+	return false
 }
 
-func (g *Game) movementCost(a, b Coordinate, p Piece) int {
-	cost := distance(a, b)
-	// When there is a bug we modify the cost to make
-	// it too high for a* to consider unless the bug can climb
-	bug := g.pieceProfile(p, a)
-	if _, ok := g.board.Cell(b); ok && !bug.IsClimber() {
-		cost *= 5
+type Winner int
+
+func (w Winner) String() string {
+	switch w {
+	case WhitePlayer:
+		return "White"
+	case BlackPlayer:
+		return "Black"
 	}
-	return cost
+	return "Tie"
 }
 
-// Returns the profile of the supplied piece and coordinate
-func (g *Game) pieceProfile(p Piece, c Coordinate) profile {
-	var climber, jumper uint8
-
-	if p.IsMosquito() {
-		// neighbors only returns an error with an invalid coordinate,
-		// by this point we should definitely have a valid coordinate.
-		neighbors := g.board.Neighbors(c)
-		for _, piece := range neighbors {
-			if piece.IsLadybug() || piece.IsBeetle() {
-				climber &= Climber
-			} else if piece.IsGrasshopper() {
-				jumper &= Jumper
-			}
+// ParseWinner returns the winner with the name, see Winner.String.
+func ParseWinner(name string) (Winner, error) {
+	for _, w := range []Winner{WhitePlayer, BlackPlayer, Tie} {
+		if w.String() == name {
+			return w, nil
 		}
-	} else if p.IsBeetle() || p.IsLadybug() {
-		climber &= Climber
-	} else if p.IsGrasshopper() {
-		jumper &= Jumper
 	}
-
-	return profile(climber | jumper)
+	return Tie, fmt.Errorf("%w: %q", ErrUnknownWinner, name)
 }
 
-func idx(c Coordinate, fromM map[Coordinate]Coordinate, costM map[Coordinate]int) (Coordinate, bool, int, bool) {
-	a, aOK := fromM[c]
-	b, bOK := costM[c]
-	return a, aOK, b, bOK
+type jsonGame struct {
+	Features []string    `json:"features"`
+	Turn     string      `json:"turn"`
+	Turns    uint        `json:"turns"`
+	Over     bool        `json:"over"`
+	Winner   string      `json:"winner,omitempty"`
+	Result   *Result     `json:"result,omitempty"`
+	Clock    *ClockState `json:"clock,omitempty"`
+
+	// the inventories that aren't the standard set, keyed by color, and the handicap
+	Inventories map[string]Inventory `json:"inventories,omitempty"`
+	Handicap    *Handicap            `json:"handicap,omitempty"`
+
+	Board   []jsonCell `json:"board"`
+	History []Action   `json:"history"`
 }
 
-func contactWithOpponentsPiece(p Piece, neighbors [7]Piece) bool {
-	for _, n := range neighbors {
-		// don't care about zero pieces
-		if n == ZeroPiece {
-			continue
-		}
-
-		if n.Color() != p.Color() {
-			return true
-		}
-	}
-
-	return false
+type jsonCell struct {
+	Piece      Piece      `json:"piece"`
+	Coordinate Coordinate `json:"coordinate"`
 }
 
-type Winner int
+func colorName(color uint8) string {
+	return NewPiece(color, NoBug, NoPiece).ColorS()
+}
 
 const (
 	FirstTurn  = 1
 	FourthTurn = 4
 
+	// paralyzed pieces are ticked after every action, this keeps the piece paralyzed through the action after the
+	// one that paralyzed it.
+	paralysisDuration = 2
+
 	Tie         Winner = 0
 	BlackPlayer Winner = 1
 	WhitePlayer Winner = 2
@@ -546,6 +864,7 @@ const (
 
 var ErrGameNotOver = fmt.Errorf("there isn't a declared winner as the game is not over")
 var ErrUnknownPiece = fmt.Errorf("an unknown piece was encountered")
+var ErrUnknownAction = fmt.Errorf("an unknown action was encountered")
 
 type ErrUnknownBoardError struct {
 	Err error
@@ -555,25 +874,3 @@ func (e *ErrUnknownBoardError) Error() string {
 	return fmt.Sprintf("encountered an unknown board error")
 }
 func (e *ErrUnknownBoardError) Unwrap() error { return e.Err }
-
-// profile defines the behavior of a piece given a specified board state
-//
-//  J - Jumper
-//  C - Climber
-//
-//  ......JC
-//  11111111
-//   uint8
-type profile uint8
-
-func (p profile) IsClimber() bool {
-	return p&Climber > 0
-}
-func (p profile) IsJumper() bool {
-	return p&Jumper > 0
-}
-
-const (
-	Climber = 0b00000001
-	Jumper  = 0b00000010
-)
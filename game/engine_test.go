@@ -1,6 +1,7 @@
 package game
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -284,6 +285,55 @@ func TestGame_Move(t *testing.T) {
 		}
 	})
 
+	// surrounded returns a game where the white piece at the origin touches five pieces, only the northwest is open,
+	// and it's whites turn. Pieces may touch an opponent when they're placed to build the position.
+	surrounded := func(t *testing.T, bug uint8) *Game {
+		variant := StandardRules()
+		variant.Placement = []PlacementRule{FirstPieceAtOrigin, PieceInHand, PlaceOnSurface, PlaceOnEmptyCell}
+		g := New(nil)
+		if err := g.ApplyRules(variant); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the rules", err)
+		}
+		placements := []struct {
+			p hive.Piece
+			c hive.Coordinate
+		}{
+			{hive.NewPiece(hive.WhiteColor, bug, hive.PieceA), hive.Origin},
+			{hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, 1, -1, 0)},
+			{hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.NewCoordinate(1, 0, -1, 0)},
+			{hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA), hive.NewCoordinate(1, -1, 0, 0)},
+			{hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), hive.NewCoordinate(0, -1, 1, 0)},
+			{hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceB), hive.NewCoordinate(-1, 0, 1, 0)},
+		}
+		for _, pl := range placements {
+			if err := g.Place(pl.p, pl.c); err != nil {
+				t.Fatalf("Unexpected error %#v while placing %s", err, pl.p)
+			}
+		}
+		return g
+	}
+
+	t.Run("When a beetle is surrounded by five pieces it may climb out", func(t *testing.T) {
+		g := surrounded(t, hive.Beetle)
+		if err := g.Move(hive.Origin, hive.NewCoordinate(0, 1, -1, 1)); err != nil {
+			t.Errorf("Unexpected error %#v while the beetle was climbing", err)
+		}
+	})
+
+	t.Run("When a grasshopper is surrounded by five pieces it may jump out", func(t *testing.T) {
+		g := surrounded(t, hive.Grasshopper)
+		if err := g.Move(hive.Origin, hive.NewCoordinate(0, 2, -2, 0)); err != nil {
+			t.Errorf("Unexpected error %#v while the grasshopper was jumping", err)
+		}
+	})
+
+	t.Run("When a spider is surrounded by five pieces it is pinned", func(t *testing.T) {
+		g := surrounded(t, hive.Spider)
+		if err := g.Move(hive.Origin, hive.NewCoordinate(-1, 2, -1, 0)); !errors.Is(err, ErrRulePiecePinned) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRulePiecePinned, err)
+		}
+	})
+
 	// TODO When attempting to move a piece that is paralyzed an error is returned
 	t.Run("When attempting to move a piece that is paralyzed an error is returned", func(t *testing.T) {
 		t.Skip("Not yet implemented")
@@ -294,9 +344,25 @@ func TestGame_Move(t *testing.T) {
 		t.Skip("Not yet implemented")
 	})
 
-	// TODO When attempting to move a piece not following the piece's pathing rules an error is returned
 	t.Run("When attempting to move a piece not following the pieces pathing rules an error is returned", func(t *testing.T) {
-		t.Skip("Not yet implemented")
+		g := New(nil)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := g.Place(hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, -1, 1, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+		}
+
+		// the queen moves a single cell and has to slide along the black queen to get there
+		if err := g.Move(hive.Origin, hive.NewCoordinate(2, -2, 0, 0)); !errors.Is(err, ErrRuleMovementDistanceTooGreat) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleMovementDistanceTooGreat, err)
+		}
+		if err := g.Move(hive.Origin, hive.NewCoordinate(1, 0, -1, 0)); !errors.Is(err, ErrRuleBugCannotReach) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleBugCannotReach, err)
+		}
+		if err := g.Move(hive.Origin, hive.NewCoordinate(1, -1, 0, 0)); err != nil {
+			t.Errorf("Unexpected error %#v while the queen was moving", err)
+		}
 	})
 }
 
@@ -324,3 +390,180 @@ func TestGame_History(t *testing.T) {
 		t.Errorf("Expected there to be 2 actions instead received %d", len(actions))
 	}
 }
+
+func TestGame_Clone(t *testing.T) {
+	g := New([]Feature{PillBugPieceFeature})
+	if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin); err != nil {
+		t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+	}
+
+	c := g.Clone()
+	if c.Hash() != g.Hash() {
+		t.Error("Expected the clone to be in the same position as the original")
+	}
+
+	if err := c.Place(hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, -1, 1, 0)); err != nil {
+		t.Fatalf("Unexpected error %#v while black was placing a piece on the clone", err)
+	}
+
+	if len(g.History()) != 1 {
+		t.Errorf("Expected the original to have 1 action instead it has %d", len(g.History()))
+	}
+	if g.Turn() != hive.BlackColor {
+		t.Error("Acting on the clone changed whose turn it is on the original")
+	}
+	if !queenInHand(g.black) {
+		t.Error("Acting on the clone took a piece from the original players inventory")
+	}
+	if _, ok := g.board.Cell(hive.NewCoordinate(0, -1, 1, 0)); ok {
+		t.Error("Acting on the clone placed a piece on the original board")
+	}
+	if !c.featureEnabled(PillBugPieceFeature) {
+		t.Error("Expected the clone to keep the features of the original")
+	}
+}
+
+func BenchmarkGame_Clone(b *testing.B) {
+	g := New(nil)
+	for i := 0; i < 10; i++ {
+		if err := g.Play(g.LegalActions()[0]); err != nil {
+			b.Fatalf("Unexpected error %#v", err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = g.Clone()
+	}
+}
+
+// pillBugGame returns a game where white has a Pill Bug at the origin touching the black queen and it is whites turn.
+func pillBugGame(t *testing.T) *Game {
+	g := New([]Feature{PillBugPieceFeature})
+	steps := []struct {
+		p hive.Piece
+		c hive.Coordinate
+	}{
+		{hive.NewPiece(hive.WhiteColor, hive.PillBug, hive.PieceA), hive.Origin},
+		{hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, -1, 1, 0)},
+		{hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, 1, -1, 0)},
+		{hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA), hive.NewCoordinate(1, -2, 1, 0)},
+		{hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), hive.NewCoordinate(-1, 1, 0, 0)},
+	}
+	for _, s := range steps {
+		if err := g.Place(s.p, s.c); err != nil {
+			t.Fatalf("Unexpected error %#v while placing %s", err, s.p)
+		}
+	}
+
+	// the black ant joins the pill bug so that throwing the black queen doesn't split the hive
+	if err := g.Move(hive.NewCoordinate(1, -2, 1, 0), hive.NewCoordinate(-1, 0, 1, 0)); err != nil {
+		t.Fatalf("Unexpected error %#v while moving the black ant", err)
+	}
+	return g
+}
+
+func TestGame_Throw(t *testing.T) {
+	south := hive.NewCoordinate(0, -1, 1, 0)
+	southeast := hive.NewCoordinate(1, -1, 0, 0)
+
+	t.Run("When a pill bug throws an opponents piece the piece is moved and paralyzed", func(t *testing.T) {
+		g := pillBugGame(t)
+		if err := g.Throw(south, southeast); err != nil {
+			t.Fatalf("Unexpected error %#v while throwing a piece", err)
+		}
+		if p, ok := g.board.Cell(southeast); !ok || !p.IsQueen() {
+			t.Error("Expected the thrown queen to be at the destination")
+		}
+		if c, _ := g.Queen(hive.BlackColor); c != southeast {
+			t.Errorf("Expected the black queen to be tracked at %s instead it is at %s", southeast, c)
+		}
+
+		if err := g.Move(southeast, south); !errors.Is(err, ErrRulePieceParalyzed) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRulePieceParalyzed, err)
+		}
+	})
+
+	t.Run("When the paralyzed pieces owner has acted the piece is freed", func(t *testing.T) {
+		g := pillBugGame(t)
+		if err := g.Throw(south, southeast); err != nil {
+			t.Fatalf("Unexpected error %#v while throwing a piece", err)
+		}
+		if err := g.Place(hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceB), hive.NewCoordinate(-1, -1, 2, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+		}
+		if g.pieceIsParalyzed(southeast) {
+			t.Error("Expected the thrown piece to be freed after its owner acted")
+		}
+	})
+
+	t.Run("When the pill bug feature is disabled an error is returned", func(t *testing.T) {
+		g := pillBugGame(t)
+		g.features[PillBugPieceFeature] = false
+		if err := g.Throw(south, southeast); !errors.Is(err, ErrRuleNoPillBugToThrow) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleNoPillBugToThrow, err)
+		}
+	})
+
+	t.Run("When the destination is not touching the pill bug an error is returned", func(t *testing.T) {
+		g := pillBugGame(t)
+		if err := g.Throw(south, hive.NewCoordinate(0, -2, 2, 0)); !errors.Is(err, ErrRuleNoPillBugToThrow) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleNoPillBugToThrow, err)
+		}
+	})
+
+	t.Run("When the piece was the last to move an error is returned", func(t *testing.T) {
+		g := pillBugGame(t)
+		g.history[len(g.history)-1] = hive.NewAction(hive.Moved, hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA),
+			hive.NewCoordinate(1, -1, 0, 0), south)
+		if err := g.Throw(south, southeast); !errors.Is(err, ErrRuleMayNotThrowLastMovedPiece) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleMayNotThrowLastMovedPiece, err)
+		}
+	})
+
+	t.Run("When listing the legal actions the throw is included", func(t *testing.T) {
+		g := pillBugGame(t)
+		expected := hive.NewAction(hive.Thrown, hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), south, southeast)
+		for _, a := range g.LegalActions() {
+			if a == expected {
+				return
+			}
+		}
+		t.Errorf("Expected %s to be a legal action", expected)
+	})
+}
+
+func TestGame_MarshalJSON(t *testing.T) {
+	t.Run("When a game is encoded and decoded the game is unchanged", func(t *testing.T) {
+		g := pillBugGame(t)
+		data, err := json.Marshal(g)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while encoding the game", err)
+		}
+
+		decoded := New(nil)
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("Unexpected error %#v while decoding %s", err, data)
+		}
+		if decoded.Hash() != g.Hash() || len(decoded.History()) != len(g.History()) {
+			t.Error("Expected the decoded game to match the encoded game")
+		}
+		if !decoded.featureEnabled(PillBugPieceFeature) {
+			t.Error("Expected the decoded game to have the pill bug feature enabled")
+		}
+	})
+
+	t.Run("When decoding a game with an illegal history the rule error is returned", func(t *testing.T) {
+		data := `{"features":[],"history":[{"act":"Placed","piece":{"color":"Black","bug":"Queen","piece":"Piece A"},"dst":{"x":0,"y":0,"z":0,"h":0}}]}`
+		if err := json.Unmarshal([]byte(data), New(nil)); !errors.Is(err, ErrRuleNotPlayersTurn) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleNotPlayersTurn, err)
+		}
+	})
+
+	t.Run("When decoding a game with an unknown feature an error is returned", func(t *testing.T) {
+		if err := json.Unmarshal([]byte(`{"features":["Dragonfly"]}`), New(nil)); !errors.Is(err, ErrUnknownFeature) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrUnknownFeature, err)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	. "github.com/theshadow/hive"
+)
+
+// Listener is notified after each successful change to the state of a Game. Applications such as user interfaces,
+// loggers, and spectator broadcasts can use a Listener to react to the game instead of polling it.
+//
+// The listener methods are called synchronously, in the order they're declared below, on the goroutine that acted on
+// the game. They receive the game so that they may query it but they must not act on it.
+//
+// Embed NopListener to only implement the methods you're interested in.
+type Listener interface {
+	// OnPlaced is called after a piece is placed on the board.
+	OnPlaced(g *Game, a Action)
+
+	// OnMoved is called after a piece is moved.
+	OnMoved(g *Game, a Action)
+
+	// OnThrown is called after a piece is thrown by a Pill Bug.
+	OnThrown(g *Game, a Action)
+
+	// OnPass is called after a player passes.
+	OnPass(g *Game, a Action)
+
+	// OnParalyzed is called after a piece is paralyzed, the coordinate is where the piece is now.
+	OnParalyzed(g *Game, p Piece, c Coordinate)
+
+	// OnTurnChanged is called after every action with the color of the player whose turn it now is along with the
+	// turn number.
+	OnTurnChanged(g *Game, turn uint8, turns uint)
+
+	// OnGameOver is called once, after the action that ended the game. That may be an action that doesn't touch the
+	// board, a resignation for example, which is only reported here. See Game.Result for how the game ended.
+	OnGameOver(g *Game, w Winner)
+}
+
+// NopListener implements Listener by doing nothing.
+type NopListener struct{}
+
+func (NopListener) OnPlaced(*Game, Action)               {}
+func (NopListener) OnMoved(*Game, Action)                {}
+func (NopListener) OnThrown(*Game, Action)               {}
+func (NopListener) OnPass(*Game, Action)                 {}
+func (NopListener) OnParalyzed(*Game, Piece, Coordinate) {}
+func (NopListener) OnTurnChanged(*Game, uint8, uint)     {}
+func (NopListener) OnGameOver(*Game, Winner)             {}
+
+// AddListener registers the listener with the game. Listeners aren't carried over to a Clone.
+func (g *Game) AddListener(l Listener) {
+	g.listeners = append(g.listeners, l)
+}
+
+// RemoveListener unregisters the listener from the game. Listeners are compared with ==, so a listener that should be
+// removable must be comparable, a pointer for example.
+func (g *Game) RemoveListener(l Listener) {
+	for i, existing := range g.listeners {
+		if existing == l {
+			g.listeners = append(g.listeners[:i:i], g.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// notify tells the listeners about the last action in the history. It should be called once the action has been
+// fully applied.
+func (g *Game) notify() {
+	if len(g.listeners) == 0 {
+		return
+	}
+
+	a := g.history[len(g.history)-1]
+	if a.Concludes() {
+		g.notifyOver()
+		return
+	}
+
+	for _, l := range g.listeners {
+		switch a.Act() {
+		case Placed:
+			l.OnPlaced(g, a)
+		case Moved:
+			l.OnMoved(g, a)
+		case Thrown:
+			l.OnThrown(g, a)
+		case Passed:
+			l.OnPass(g, a)
+		}
+	}
+
+	if a.WasThrown() && g.pieceIsParalyzed(a.Dst()) {
+		for _, l := range g.listeners {
+			l.OnParalyzed(g, a.Piece(), a.Dst())
+		}
+	}
+
+	for _, l := range g.listeners {
+		l.OnTurnChanged(g, g.turn, g.turns)
+	}
+
+	g.notifyOver()
+}
+
+// notifyOver tells the listeners the game is over, the first time it's called once the game is over.
+func (g *Game) notifyOver() {
+	if g.overNotified || !g.Over() {
+		return
+	}
+	g.overNotified = true
+
+	// the game is over so there is always a winner
+	w, _ := g.Winner()
+	for _, l := range g.listeners {
+		l.OnGameOver(g, w)
+	}
+}
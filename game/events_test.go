@@ -0,0 +1,166 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/theshadow/hive"
+)
+
+// recorder is a listener that records the name of every event it receives.
+type recorder struct {
+	NopListener
+	events []string
+}
+
+func (r *recorder) OnPlaced(*Game, hive.Action) { r.events = append(r.events, "placed") }
+func (r *recorder) OnMoved(*Game, hive.Action)  { r.events = append(r.events, "moved") }
+func (r *recorder) OnThrown(*Game, hive.Action) { r.events = append(r.events, "thrown") }
+func (r *recorder) OnPass(*Game, hive.Action)   { r.events = append(r.events, "pass") }
+func (r *recorder) OnParalyzed(*Game, hive.Piece, hive.Coordinate) {
+	r.events = append(r.events, "paralyzed")
+}
+func (r *recorder) OnTurnChanged(*Game, uint8, uint) { r.events = append(r.events, "turn") }
+
+func TestGame_AddListener(t *testing.T) {
+	t.Run("When a piece is placed the listener is notified", func(t *testing.T) {
+		g := New(nil)
+		r := &recorder{}
+		g.AddListener(r)
+
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		assertEvents(t, r.events, "placed", "turn")
+	})
+
+	t.Run("When a piece is moved the listener is notified", func(t *testing.T) {
+		g := New(nil)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := g.Place(hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, 1, -1, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+		}
+		r := &recorder{}
+		g.AddListener(r)
+
+		if err := g.Move(hive.Origin, hive.NewCoordinate(1, 0, -1, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while white was moving a piece", err)
+		}
+		assertEvents(t, r.events, "moved", "turn")
+	})
+
+	t.Run("When a player passes the listener is notified", func(t *testing.T) {
+		variant := StandardRules()
+		variant.TurnOrder = []TurnRule{PlayersTurn}
+		g := New(nil)
+		if err := g.ApplyRules(variant); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the rules", err)
+		}
+		r := &recorder{}
+		g.AddListener(r)
+
+		if err := g.Pass(); err != nil {
+			t.Fatalf("Unexpected error %#v while white was passing", err)
+		}
+		assertEvents(t, r.events, "pass", "turn")
+	})
+
+	t.Run("When an action fails the listener is not notified", func(t *testing.T) {
+		g := New(nil)
+		r := &recorder{}
+		g.AddListener(r)
+
+		if err := g.Place(hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.Origin); err == nil {
+			t.Fatal("Expected an error when black placed a piece on whites turn")
+		}
+		assertEvents(t, r.events)
+	})
+
+	t.Run("When a piece is thrown the listener is told it was paralyzed", func(t *testing.T) {
+		g := pillBugGame(t)
+		r := &recorder{}
+		g.AddListener(r)
+
+		if err := g.Throw(hive.NewCoordinate(0, -1, 1, 0), hive.NewCoordinate(1, -1, 0, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while throwing a piece", err)
+		}
+		assertEvents(t, r.events, "thrown", "paralyzed", "turn")
+	})
+
+	t.Run("When a listener is removed it is no longer notified", func(t *testing.T) {
+		g := New(nil)
+		r := &recorder{}
+		g.AddListener(r)
+		g.RemoveListener(r)
+
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		assertEvents(t, r.events)
+	})
+}
+
+func assertEvents(t *testing.T, actual []string, expected ...string) {
+	t.Helper()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected the events %v instead received %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected the events %v instead received %v", expected, actual)
+		}
+	}
+}
+
+// overCounter counts the number of times it was told the game is over.
+type overCounter struct {
+	NopListener
+	count int
+}
+
+func (o *overCounter) OnGameOver(*Game, Winner) { o.count++ }
+
+func TestGame_AddListener_GameOver(t *testing.T) {
+	t.Log("When the game ends the listener is told exactly once")
+	g := New(nil)
+	o := &overCounter{}
+	g.AddListener(o)
+
+	// the pieces gather around the white queen, black surrounds it with the last move
+	ant := func(color, n uint8) hive.Piece { return hive.NewPiece(color, hive.Ant, n) }
+	actions := []hive.Action{
+		hive.NewAction(hive.Placed, hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin, hive.Origin),
+		hive.NewAction(hive.Placed, hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.Origin,
+			hive.NewCoordinate(0, 1, -1, 0)),
+		hive.NewAction(hive.Placed, ant(hive.WhiteColor, hive.PieceA), hive.Origin, hive.NewCoordinate(0, -1, 1, 0)),
+		hive.NewAction(hive.Placed, ant(hive.BlackColor, hive.PieceA), hive.Origin, hive.NewCoordinate(0, 2, -2, 0)),
+		hive.NewAction(hive.Placed, ant(hive.WhiteColor, hive.PieceB), hive.Origin, hive.NewCoordinate(0, -2, 2, 0)),
+		hive.NewAction(hive.Placed, ant(hive.BlackColor, hive.PieceB), hive.Origin, hive.NewCoordinate(1, 1, -2, 0)),
+		hive.NewAction(hive.Placed, ant(hive.WhiteColor, hive.PieceC), hive.Origin, hive.NewCoordinate(-1, -1, 2, 0)),
+		hive.NewAction(hive.Placed, ant(hive.BlackColor, hive.PieceC), hive.Origin, hive.NewCoordinate(-1, 2, -1, 0)),
+		hive.NewAction(hive.Moved, ant(hive.WhiteColor, hive.PieceC), hive.NewCoordinate(-1, -1, 2, 0),
+			hive.NewCoordinate(-1, 0, 1, 0)),
+		hive.NewAction(hive.Moved, ant(hive.BlackColor, hive.PieceA), hive.NewCoordinate(0, 2, -2, 0),
+			hive.NewCoordinate(1, 0, -1, 0)),
+		hive.NewAction(hive.Moved, ant(hive.WhiteColor, hive.PieceB), hive.NewCoordinate(0, -2, 2, 0),
+			hive.NewCoordinate(1, -1, 0, 0)),
+		hive.NewAction(hive.Moved, ant(hive.BlackColor, hive.PieceC), hive.NewCoordinate(-1, 2, -1, 0),
+			hive.NewCoordinate(-1, 1, 0, 0)),
+	}
+	for _, a := range actions {
+		if g.Over() {
+			t.Fatalf("Expected the game to go on until the queen is surrounded, it ended before %s", a)
+		}
+		if err := g.Play(a); err != nil {
+			t.Fatalf("Unexpected error %#v while playing %s", err, a)
+		}
+	}
+
+	if w, err := g.Winner(); err != nil || w != BlackPlayer {
+		t.Errorf("Expected black to win instead received %d and the error %#v", w, err)
+	}
+	if o.count != 1 {
+		t.Errorf("Expected the listener to be told the game is over once instead it was told %d times", o.count)
+	}
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	. "github.com/theshadow/hive"
+)
+
+// Explanation describes whether a movement is legal and, when it isn't, every rule that stands in the way.
+type Explanation struct {
+	// Src and Dst are the coordinates of the movement that was explained.
+	Src, Dst Coordinate
+
+	// Piece is the piece at the source, it's the ZeroPiece when there is no piece to move.
+	Piece Piece
+
+	// Legal is true when the engine would accept the movement.
+	Legal bool
+
+	// Violations holds a RuleError for each rule the movement violates, in the order Move checks them. The first
+	// violation is the error Move would return.
+	Violations []*RuleError
+
+	// Err is set when the movement couldn't be checked against the rules at all, for example when there is no piece
+	// at the source.
+	Err error
+}
+
+// Rules returns the identifiers of the rules that block the movement.
+func (e Explanation) Rules() []RuleID {
+	var ids []RuleID
+	for _, v := range e.Violations {
+		ids = append(ids, v.Rule)
+	}
+	return ids
+}
+
+// Violates returns true when the movement is blocked by the rule.
+func (e Explanation) Violates(id RuleID) bool {
+	for _, v := range e.Violations {
+		if v.Rule == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Explain checks moving the piece at (src) to (dst) without changing the state of the game. Unlike Move, which stops
+// at the first rule that is broken, Explain keeps going and reports every broken rule. This is meant for teaching, a
+// player asking why a piece can't move should hear that it's both pinned and paralyzed rather than only one of them.
+//
+// A game that is over, or whose player has run out of time, is reported first, the same as Move refuses it.
+func (g *Game) Explain(src, dst Coordinate) Explanation {
+	piece, violations, err := g.moveViolations(src, dst, true)
+	if t := g.timer; t != nil && !t.stopped && g.flagged() {
+		violations = append([]*RuleError{newRuleError(ErrRuleOutOfTime, Moved, piece, src, dst)}, violations...)
+	} else if g.Over() {
+		violations = append([]*RuleError{newRuleError(ErrGameOver, Moved, piece, src, dst)}, violations...)
+	}
+	return Explanation{
+		Src:        src,
+		Dst:        dst,
+		Piece:      piece,
+		Legal:      err == nil && len(violations) == 0,
+		Violations: violations,
+		Err:        err,
+	}
+}
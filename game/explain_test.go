@@ -0,0 +1,115 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+)
+
+func TestGame_Explain(t *testing.T) {
+	south := hive.NewCoordinate(0, -1, 1, 0)
+	southeast := hive.NewCoordinate(1, -1, 0, 0)
+	northeast := hive.NewCoordinate(1, 0, -1, 0)
+
+	t.Run("When explaining a legal move it is legal and there are no violations", func(t *testing.T) {
+		g := pillBugGame(t)
+		e := g.Explain(hive.NewCoordinate(-1, 1, 0, 0), northeast)
+		if !e.Legal || len(e.Violations) != 0 || e.Err != nil {
+			t.Errorf("Expected the move to be legal instead received %#v", e)
+		}
+		if !e.Piece.IsAnt() {
+			t.Errorf("Expected the explanation to be for the white ant instead it is for %s", e.Piece)
+		}
+	})
+
+	t.Run("When explaining a move that breaks several rules every rule is reported", func(t *testing.T) {
+		g := pillBugGame(t)
+		if err := g.Throw(south, southeast); err != nil {
+			t.Fatalf("Unexpected error %#v while throwing a piece", err)
+		}
+
+		e := g.Explain(hive.Origin, hive.NewCoordinate(0, -1, 1, 0))
+		if e.Legal {
+			t.Fatal("Expected the move to be illegal")
+		}
+		expected := []RuleID{RuleNotPlayersTurn, RulePiecePinned, RuleMayNotSplitHive, RuleBugCannotReach}
+		actual := e.Rules()
+		if len(actual) != len(expected) {
+			t.Fatalf("Expected the rules %v instead received %v", expected, actual)
+		}
+		for i := range expected {
+			if actual[i] != expected[i] {
+				t.Errorf("Expected the rules %v instead received %v", expected, actual)
+			}
+		}
+	})
+
+	t.Run("When explaining an illegal move the first violation is the error Move returns", func(t *testing.T) {
+		g := pillBugGame(t)
+		if err := g.Throw(south, southeast); err != nil {
+			t.Fatalf("Unexpected error %#v while throwing a piece", err)
+		}
+
+		e := g.Explain(southeast, south)
+		if !e.Violates(RulePieceParalyzed) {
+			t.Errorf("Expected the paralyzed rule to be violated instead received %v", e.Rules())
+		}
+		if err := g.Move(southeast, south); !errors.Is(err, e.Violations[0].Err) {
+			t.Errorf("Expected an error of type %#v instead received %#v", e.Violations[0].Err, err)
+		}
+	})
+
+	t.Run("When explaining a move the game is not changed", func(t *testing.T) {
+		g := pillBugGame(t)
+		before := g.Hash()
+		g.Explain(hive.NewCoordinate(-1, 1, 0, 0), northeast)
+		if g.Hash() != before || len(g.History()) != 6 {
+			t.Error("Expected the game to be unchanged after explaining a move")
+		}
+	})
+
+	t.Run("When explaining a move in a finished game the game being over is reported", func(t *testing.T) {
+		g := pillBugGame(t)
+		if err := g.Resign(hive.BlackColor); err != nil {
+			t.Fatalf("Unexpected error %#v while black was resigning", err)
+		}
+
+		e := g.Explain(hive.NewCoordinate(-1, 1, 0, 0), northeast)
+		if e.Legal || !e.Violates(RuleGameOver) {
+			t.Errorf("Expected the game over rule to be violated instead received %v", e.Rules())
+		}
+		if err := g.Move(hive.NewCoordinate(-1, 1, 0, 0), northeast); !errors.Is(err, e.Violations[0].Err) {
+			t.Errorf("Expected an error of type %#v instead received %#v", e.Violations[0].Err, err)
+		}
+	})
+
+	t.Run("When explaining a move after the player ran out of time the timeout is reported", func(t *testing.T) {
+		g := pillBugGame(t)
+		clock := NewManualClock(time.Now())
+		if err := g.StartClock(SuddenDeath(time.Minute), clock); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		clock.Advance(2 * time.Minute)
+
+		e := g.Explain(hive.NewCoordinate(-1, 1, 0, 0), northeast)
+		if e.Legal || e.Rules()[0] != RuleOutOfTime {
+			t.Errorf("Expected the out of time rule to be violated first instead received %v", e.Rules())
+		}
+		if len(g.History()) != 6 {
+			t.Error("Expected the game to be unchanged after explaining a move")
+		}
+		if err := g.Move(hive.NewCoordinate(-1, 1, 0, 0), northeast); !errors.Is(err, e.Violations[0].Err) {
+			t.Errorf("Expected an error of type %#v instead received %#v", e.Violations[0].Err, err)
+		}
+	})
+
+	t.Run("When explaining a move from an empty cell an error is set", func(t *testing.T) {
+		g := pillBugGame(t)
+		e := g.Explain(northeast, southeast)
+		if e.Legal || !errors.Is(e.Err, hive.ErrInvalidCoordinate) {
+			t.Errorf("Expected an error of type %#v instead received %#v", hive.ErrInvalidCoordinate, e.Err)
+		}
+	})
+}
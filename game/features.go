@@ -1,5 +1,7 @@
 package game
 
+import "fmt"
+
 type Feature uint64
 
 const (
@@ -25,3 +27,31 @@ func copyFeatureMap() (features map[Feature]bool) {
 	}
 	return features
 }
+
+// String returns the name of the feature, the name is stable and may be used in configuration or over the wire.
+func (f Feature) String() string {
+	if label, ok := featureLabels[f]; ok {
+		return label
+	}
+	return featureLabels[NoFeature]
+}
+
+// ParseFeature returns the feature with the name, see Feature.String.
+func ParseFeature(name string) (Feature, error) {
+	for f, label := range featureLabels {
+		if f != NoFeature && label == name {
+			return f, nil
+		}
+	}
+	return NoFeature, fmt.Errorf("%w: %q", ErrUnknownFeature, name)
+}
+
+var featureLabels = map[Feature]string{
+	NoFeature:                   "NoFeature",
+	LadybugPieceFeature:         "Ladybug",
+	PillBugPieceFeature:         "PillBug",
+	MosquitoPieceFeature:        "Mosquito",
+	TournamentQueensRuleFeature: "TournamentQueens",
+}
+
+var ErrUnknownFeature = fmt.Errorf("an unknown feature was encountered")
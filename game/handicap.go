@@ -0,0 +1,113 @@
+package game
+
+import (
+	"fmt"
+
+	. "github.com/theshadow/hive"
+)
+
+// Handicap evens a game between players of different strength. Each player has their own Odds, the stronger player
+// usually gives up pieces while the weaker player is given extra placements or more time to place their queen. See
+// ApplyHandicap.
+type Handicap struct {
+	White Odds `json:"white"`
+	Black Odds `json:"black"`
+}
+
+// Odds are the terms of a handicap for one of the players.
+type Odds struct {
+	// Removed are the pieces taken out of the players inventory before the game starts.
+	Removed Inventory `json:"removed"`
+
+	// Placements is the number of extra placements the player makes, each of their first placements is followed by
+	// another action of theirs until the extra placements are used up.
+	Placements int `json:"placements,omitempty"`
+
+	// QueenTurn is the turn by which the player must have placed their queen, zero keeps the deadline at FourthTurn.
+	// The deadline may only be put back, never brought forward.
+	QueenTurn uint `json:"queenTurn,omitempty"`
+}
+
+// Of returns the odds of the player of the color.
+func (h Handicap) Of(color uint8) Odds {
+	if color == WhiteColor {
+		return h.White
+	}
+	return h.Black
+}
+
+// IsZero returns true when the handicap doesn't change the game.
+func (h Handicap) IsZero() bool {
+	return h == Handicap{}
+}
+
+// ApplyHandicap sets the game up with the handicap, it's only allowed before the first action. The removed pieces are
+// taken out of the inventories the game was made with, see New, and a handicap applied earlier is replaced.
+func (g *Game) ApplyHandicap(h Handicap) error {
+	if len(g.history) > 0 {
+		return ErrGameStarted
+	}
+
+	var stocks [2]Stock
+	for i, color := range []uint8{BlackColor, WhiteColor} {
+		odds := h.Of(color)
+		inv := g.Inventory(color)
+		if odds.Placements < 0 || odds.Placements >= inv.Size() {
+			return fmt.Errorf("%w: %s may not make %d extra placements", ErrInvalidHandicap, colorName(color),
+				odds.Placements)
+		}
+		if odds.QueenTurn != 0 && odds.QueenTurn < FourthTurn {
+			return fmt.Errorf("%w: the queen of %s may not be due before turn %d", ErrInvalidHandicap,
+				colorName(color), FourthTurn)
+		}
+		for _, bug := range DefaultBugs.Bugs() {
+			n := inv.Count(bug) - odds.Removed.Count(bug)
+			if n < 0 {
+				return fmt.Errorf("%w: %s doesn't have the pieces to remove", ErrInvalidHandicap, colorName(color))
+			}
+			inv, _ = inv.With(bug, n)
+		}
+		stocks[i] = inv.Stock()
+	}
+
+	g.black, g.white = stocks[0], stocks[1]
+	g.handicap = h
+	g.extraPlacements = [2]int{h.Black.Placements, h.White.Placements}
+	return nil
+}
+
+// Handicap returns the handicap the game was set up with, see ApplyHandicap.
+func (g *Game) Handicap() Handicap {
+	return g.handicap
+}
+
+// Initial returns a new game set up as the game was before its first action, with the same features, inventories,
+// handicap, and rules. The history, the clock, and the listeners aren't carried over.
+func (g *Game) Initial() *Game {
+	initial := New(g.Features(), g.Inventory(WhiteColor), g.Inventory(BlackColor))
+	// the handicap was applied to the same inventories already, it can't be refused
+	_ = initial.ApplyHandicap(g.handicap)
+	initial.rules = g.rules
+	return initial
+}
+
+// queenTurn returns the turn by which the player of the color must have placed their queen.
+func (g *Game) queenTurn(color uint8) uint {
+	if turn := g.handicap.Of(color).QueenTurn; turn != 0 {
+		return turn
+	}
+	return FourthTurn
+}
+
+// takeExtraPlacement uses up one of the extra placements of the player whose turn it is, it returns false when they
+// have none left.
+func (g *Game) takeExtraPlacement() bool {
+	if i := g.turn - 1; i < 2 && g.extraPlacements[i] > 0 {
+		g.extraPlacements[i]--
+		return true
+	}
+	return false
+}
+
+var ErrGameStarted = fmt.Errorf("the game has already started")
+var ErrInvalidHandicap = fmt.Errorf("the handicap is invalid")
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/theshadow/hive"
+)
+
+func TestGame_ApplyHandicap(t *testing.T) {
+	oneAnt, _ := hive.NewInventory(map[uint8]int{hive.Ant: 1})
+
+	t.Run("When pieces are removed the player starts without them", func(t *testing.T) {
+		g := New(nil)
+		if err := g.ApplyHandicap(Handicap{White: Odds{Removed: oneAnt}}); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the handicap", err)
+		}
+		if n := len(g.Hand(hive.WhiteColor)); n != 10 {
+			t.Errorf("Expected white to have %d pieces instead received %d", 10, n)
+		}
+		if n := len(g.Hand(hive.BlackColor)); n != 11 {
+			t.Errorf("Expected black to have %d pieces instead received %d", 11, n)
+		}
+		if g.Inventory(hive.WhiteColor) != hive.StandardInventory {
+			t.Errorf("Expected the inventory to count the removed pieces instead received %s", g.Inventory(hive.WhiteColor))
+		}
+	})
+
+	t.Run("When a player has an extra placement they place again before the turn passes", func(t *testing.T) {
+		g := New(nil)
+		if err := g.ApplyHandicap(Handicap{Black: Odds{Placements: 1}}); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the handicap", err)
+		}
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Spider, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing", err)
+		}
+		if err := g.Place(hive.NewPiece(hive.BlackColor, hive.Spider, hive.PieceA), hive.NewCoordinate(0, 1, -1, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing", err)
+		}
+		if g.Turn() != hive.BlackColor || g.Turns() != FirstTurn {
+			t.Fatalf("Expected black to keep the first turn instead received %d on turn %d", g.Turn(), g.Turns())
+		}
+
+		// only the first placement of a player may touch their opponent
+		ant := hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA)
+		if err := g.Place(ant, hive.NewCoordinate(1, 0, -1, 0)); !errors.Is(err, ErrRuleMayNotPlaceTouchingOpponentsPiece) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleMayNotPlaceTouchingOpponentsPiece, err)
+		}
+		if err := g.Place(ant, hive.NewCoordinate(0, 2, -2, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while black was making their extra placement", err)
+		}
+		if g.Turn() != hive.WhiteColor || g.Turns() != FirstTurn+1 {
+			t.Errorf("Expected white to play the second turn instead received %d on turn %d", g.Turn(), g.Turns())
+		}
+	})
+
+	t.Run("When the queen deadline is put back the player may place another bug on the fourth turn", func(t *testing.T) {
+		g := New(nil)
+		if err := g.ApplyHandicap(Handicap{White: Odds{QueenTurn: FourthTurn + 1}}); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the handicap", err)
+		}
+		// both players hold back their queens for as long as they may
+		play := func() {
+			for _, a := range g.LegalActions() {
+				if !a.Piece().IsQueen() || a.Piece().Color() == hive.BlackColor && g.Turns() == FourthTurn {
+					if err := g.Play(a); err != nil {
+						t.Fatalf("Unexpected error %#v while playing %s", err, a)
+					}
+					return
+				}
+			}
+			t.Fatalf("Expected a placement that isn't a queen on turn %d", g.Turns())
+		}
+		for g.Turns() < FourthTurn+1 {
+			play()
+		}
+		for _, a := range g.LegalActions() {
+			if !a.Piece().IsQueen() {
+				t.Fatalf("Expected white to have to place their queen on turn %d instead %s is legal", g.Turns(), a)
+			}
+		}
+	})
+
+	t.Run("When a handicap can't be met an error is returned", func(t *testing.T) {
+		queen, _ := hive.NewInventory(map[uint8]int{hive.Queen: 1})
+		for _, h := range []Handicap{
+			{White: Odds{Removed: oneAnt}},
+			{Black: Odds{QueenTurn: FourthTurn - 1}},
+			{Black: Odds{Placements: -1}},
+		} {
+			if err := New(nil, queen).ApplyHandicap(h); !errors.Is(err, ErrInvalidHandicap) {
+				t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidHandicap, err)
+			}
+		}
+
+		g := New(nil)
+		_ = g.Place(hive.NewPiece(hive.WhiteColor, hive.Spider, hive.PieceA), hive.Origin)
+		if err := g.ApplyHandicap(Handicap{Black: Odds{Placements: 1}}); !errors.Is(err, ErrGameStarted) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrGameStarted, err)
+		}
+	})
+
+	t.Run("When a handicap game is encoded and replayed the handicap is kept", func(t *testing.T) {
+		g := New(nil)
+		h := Handicap{White: Odds{Removed: oneAnt}, Black: Odds{Placements: 1, QueenTurn: 6}}
+		if err := g.ApplyHandicap(h); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the handicap", err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := g.Play(g.LegalActions()[0]); err != nil {
+				t.Fatalf("Unexpected error %#v while playing", err)
+			}
+		}
+
+		data, err := json.Marshal(g)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while encoding the game", err)
+		}
+		var decoded Game
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unexpected error %#v while decoding the game", err)
+		}
+		r, err := g.Replay()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while replaying the game", err)
+		}
+		for _, c := range []*Game{&decoded, r.Game(), g.Clone()} {
+			if c.Handicap() != h || c.Hash() != g.Hash() || c.Turn() != hive.WhiteColor {
+				t.Errorf("Expected the handicap %v to be kept instead received %v", h, c.Handicap())
+			}
+		}
+	})
+}
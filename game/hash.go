@@ -0,0 +1,69 @@
+package game
+
+import (
+	. "github.com/theshadow/hive"
+)
+
+// Hash returns a 64-bit key that identifies the current position. Two games that reached the same arrangement of
+// pieces, with the same player to act, the same pieces in hand, the same paralyzed pieces, and the same handicap left
+// to play out, will return the same key regardless of the order of the actions that lead there.
+//
+// The key is built Zobrist style, each (piece, coordinate) pair is mixed into a pseudo-random value and all of the
+// values are XOR'd together. Because the coordinate space is far too large for a pre-computed table we derive the
+// random value on demand with a mixing function instead.
+//
+// The piece number (A, B, C) is masked out of the key, as far as the rules are concerned two ants are the same ant and
+// swapping them doesn't change the position.
+func (g *Game) Hash() uint64 {
+	var h uint64
+	for _, c := range g.board.Pieces() {
+		h ^= mix64(uint64(c.Piece&^PieceMask)<<32 | uint64(c.Coordinate))
+	}
+
+	for c, ttf := range g.paralyzedPieces {
+		h ^= mix64(paralyzedKey ^ uint64(c)<<8 ^ uint64(ttf))
+	}
+
+	// the pieces in hand and the handicap decide what may still be placed, and when
+	for i, color := range []uint8{BlackColor, WhiteColor} {
+		player := g.black
+		if color == WhiteColor {
+			player = g.white
+		}
+		for _, bug := range DefaultBugs.Bugs() {
+			if n := player.Remaining(bug); n > 0 {
+				h ^= mix64(inHandKey ^ uint64(color)<<16 ^ uint64(bug)<<8 ^ uint64(n))
+			}
+		}
+		if n := g.extraPlacements[i]; n > 0 {
+			h ^= mix64(extraPlacementsKey ^ uint64(color)<<16 ^ uint64(n))
+		}
+		if turn := g.queenTurn(color); turn != FourthTurn {
+			h ^= mix64(queenTurnKey ^ uint64(color)<<16 ^ uint64(turn))
+		}
+	}
+
+	if g.turn == BlackColor {
+		h ^= blackToActKey
+	}
+
+	return h
+}
+
+// mix64 is the finalizer from SplitMix64, it's cheap and spreads a single bit change across the whole word which is
+// all we need from it.
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+const (
+	blackToActKey = 0x5bd1e9955bd1e995
+	paralyzedKey  = 0xc2b2ae3d27d4eb4f
+
+	inHandKey          = 0x165667b19e3779f9
+	extraPlacementsKey = 0x27d4eb2f165667c5
+	queenTurnKey       = 0x85ebca77c2b2ae63
+)
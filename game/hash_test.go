@@ -0,0 +1,75 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/theshadow/hive"
+)
+
+func TestGame_Hash(t *testing.T) {
+	t.Run("When identical bugs are placed in a different order the hashes match", func(t *testing.T) {
+		north := hive.NewCoordinate(0, 1, -1, 0)
+		northeast := hive.NewCoordinate(1, 0, -1, 0)
+
+		play := func(first, second hive.Coordinate) *Game {
+			g := New(nil)
+			steps := []struct {
+				p hive.Piece
+				c hive.Coordinate
+			}{
+				{hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin},
+				{hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, -1, 1, 0)},
+				{hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), first},
+				{hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA), hive.NewCoordinate(0, -2, 2, 0)},
+				{hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceB), second},
+				{hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceB), hive.NewCoordinate(1, -2, 1, 0)},
+			}
+			for _, s := range steps {
+				if err := g.Place(s.p, s.c); err != nil {
+					t.Fatalf("Unexpected error %#v while placing %s", err, s.p)
+				}
+			}
+			return g
+		}
+
+		a := play(north, northeast)
+		b := play(northeast, north)
+		if a.Hash() != b.Hash() {
+			t.Errorf("Expected the hashes to match, %x != %x", a.Hash(), b.Hash())
+		}
+	})
+
+	t.Run("When a piece is placed the hash changes", func(t *testing.T) {
+		g := New(nil)
+		before := g.Hash()
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while placing a piece", err)
+		}
+		if g.Hash() == before {
+			t.Error("Expected the hash to change after a piece was placed")
+		}
+	})
+
+	t.Run("When the pieces in hand or the handicap differ the hashes differ", func(t *testing.T) {
+		fewerAnts, _ := hive.StandardInventory.With(hive.Ant, 2)
+		games := map[string]*Game{
+			"standard":    New(nil),
+			"fewer ants":  New(nil, fewerAnts),
+			"handicapped": New(nil),
+		}
+		if err := games["handicapped"].ApplyHandicap(Handicap{Black: Odds{Placements: 1}}); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the handicap", err)
+		}
+
+		seen := make(map[uint64]string)
+		for name, g := range games {
+			if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin); err != nil {
+				t.Fatalf("Unexpected error %#v while placing in the %s game", err, name)
+			}
+			if other, ok := seen[g.Hash()]; ok {
+				t.Errorf("Expected the %s and %s games to hash differently", name, other)
+			}
+			seen[g.Hash()] = name
+		}
+	})
+}
@@ -0,0 +1,18 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package i18n renders the rule errors of the game package as full sentences in the language
+of the player.
+
+Each language is described by a message catalog, a JSON file embedded into the package, that
+contains a sentence for every rule along with the names of the bugs. The bugs are keyed by the
+labels the hive package uses for them, see Piece.BugS.
+
+Adding a Language
+
+Drop a new catalog into the catalogs directory named after the language tag, for example
+fr.json, and add the tag to the list of languages. The tests verify that every catalog has a
+sentence for every rule and a name for every bug.
+*/
+package i18n
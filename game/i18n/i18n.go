@@ -0,0 +1,173 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// Language is a BCP 47 language tag such as "en" or "es".
+type Language string
+
+const (
+	English Language = "en"
+	Spanish Language = "es"
+	German  Language = "de"
+
+	// Default is used when a requested language isn't available.
+	Default = English
+)
+
+// Languages returns the languages that have a catalog.
+func Languages() []Language {
+	return []Language{English, Spanish, German}
+}
+
+// Catalog holds the messages for a single language.
+type Catalog struct {
+	// Language is the tag of the language of the catalog.
+	Language Language `json:"-"`
+
+	// Name is the name of the language, in the language.
+	Name string `json:"language"`
+
+	// Piece is used in place of a piece when the error doesn't identify one.
+	Piece string `json:"piece"`
+
+	// And is the conjunction used to join a list of pieces.
+	And string `json:"and"`
+
+	// Unknown is used for errors that aren't rule violations.
+	Unknown string `json:"unknown"`
+
+	// Bugs maps the label of a bug to its name and the phrase used to refer to the piece of each color.
+	Bugs map[string]map[string]string `json:"bugs"`
+
+	// Rules maps the identifier of a rule to the sentence that explains it.
+	Rules map[game.RuleID]string `json:"rules"`
+}
+
+// Lookup returns the catalog for the language tag. Regional tags fall back to their base language, "es-MX" uses the
+// Spanish catalog, and any language without a catalog falls back to the Default language.
+func Lookup(tag string) *Catalog {
+	load()
+
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if c, ok := catalogs[Language(tag)]; ok {
+		return c
+	}
+	if i := strings.Index(tag, "-"); i > 0 {
+		if c, ok := catalogs[Language(tag[:i])]; ok {
+			return c
+		}
+	}
+	return catalogs[Default]
+}
+
+// Explain renders the error as a sentence in the language, see Lookup for how the language is chosen.
+func Explain(lang Language, err error) string {
+	return Lookup(string(lang)).Explain(err)
+}
+
+// Explain renders the error as a sentence. Rule errors are explained using the context they carry, the sentinel rule
+// errors are explained without naming the piece, and anything else is explained with the catalogs Unknown message.
+func (c *Catalog) Explain(err error) string {
+	id := game.RuleOf(err)
+	msg, ok := c.Rules[id]
+	if !ok {
+		return c.Unknown
+	}
+
+	piece, bug := c.Piece, c.Piece
+	var conflicts []string
+
+	var re *game.RuleError
+	if errors.As(err, &re) {
+		if re.Piece != hive.ZeroPiece {
+			piece = c.PieceName(re.Piece)
+			bug = c.BugName(re.Piece)
+		}
+		for _, p := range re.Conflicts {
+			conflicts = append(conflicts, c.PieceName(p))
+		}
+	}
+
+	msg = strings.NewReplacer(
+		"{piece}", piece,
+		"{bug}", bug,
+		"{conflicts}", c.join(conflicts),
+	).Replace(msg)
+
+	return capitalize(msg)
+}
+
+// PieceName returns the phrase used to refer to the piece, for example "the white Grasshopper".
+func (c *Catalog) PieceName(p hive.Piece) string {
+	if name, ok := c.Bugs[p.BugS()][p.ColorS()]; ok {
+		return name
+	}
+	return c.Piece
+}
+
+// BugName returns the name of the bug of the piece, for example "Grasshopper".
+func (c *Catalog) BugName(p hive.Piece) string {
+	if name, ok := c.Bugs[p.BugS()]["name"]; ok {
+		return name
+	}
+	return p.BugS()
+}
+
+// join combines the phrases into a list, "a, b and c".
+func (c *Catalog) join(phrases []string) string {
+	switch len(phrases) {
+	case 0:
+		return c.Piece
+	case 1:
+		return phrases[0]
+	}
+	last := len(phrases) - 1
+	return strings.Join(phrases[:last], ", ") + " " + c.And + " " + phrases[last]
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// load parses the embedded catalogs once. The catalogs are part of the source so a catalog that can't be parsed is a
+// programming error.
+func load() {
+	once.Do(func() {
+		catalogs = make(map[Language]*Catalog)
+		for _, lang := range Languages() {
+			data, err := files.ReadFile(fmt.Sprintf("catalogs/%s.json", lang))
+			if err != nil {
+				panic(fmt.Sprintf("i18n: missing catalog for %s: %s", lang, err))
+			}
+			c := &Catalog{Language: lang}
+			if err := json.Unmarshal(data, c); err != nil {
+				panic(fmt.Sprintf("i18n: malformed catalog for %s: %s", lang, err))
+			}
+			catalogs[lang] = c
+		}
+	})
+}
+
+//go:embed catalogs/*.json
+var files embed.FS
+
+var (
+	once     sync.Once
+	catalogs map[Language]*Catalog
+)
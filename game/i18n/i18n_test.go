@@ -0,0 +1,96 @@
+package i18n
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+func TestCatalogs(t *testing.T) {
+	for _, lang := range Languages() {
+		c := Lookup(string(lang))
+		if c.Language != lang {
+			t.Fatalf("Expected the catalog for %s instead received %s", lang, c.Language)
+		}
+
+		t.Run("When the catalog for "+string(lang)+" is loaded it explains every rule", func(t *testing.T) {
+			for _, id := range game.Rules() {
+				if msg := c.Rules[id]; msg == "" {
+					t.Errorf("The rule %s is missing", id)
+				}
+			}
+		})
+
+		t.Run("When the catalog for "+string(lang)+" is loaded it names every bug", func(t *testing.T) {
+			for bug := hive.Queen; bug <= hive.PillBug; bug++ {
+				for _, color := range []uint8{hive.WhiteColor, hive.BlackColor} {
+					p := hive.NewPiece(color, bug, hive.PieceA)
+					if c.PieceName(p) == c.Piece {
+						t.Errorf("The %s %s is missing", p.ColorS(), p.BugS())
+					}
+				}
+				if _, ok := c.Bugs[hive.NewWhitePiece(bug, 0).BugS()]["name"]; !ok {
+					t.Errorf("The name of the %s is missing", hive.NewWhitePiece(bug, 0).BugS())
+				}
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Run("When looking up a regional tag the base language is used", func(t *testing.T) {
+		if c := Lookup("es-MX"); c.Language != Spanish {
+			t.Errorf("Expected %s instead received %s", Spanish, c.Language)
+		}
+	})
+
+	t.Run("When looking up a language without a catalog the default language is used", func(t *testing.T) {
+		if c := Lookup("xx"); c.Language != Default {
+			t.Errorf("Expected %s instead received %s", Default, c.Language)
+		}
+	})
+}
+
+func TestCatalog_Explain(t *testing.T) {
+	g := game.New(nil)
+	wq := hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA)
+	bq := hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA)
+	if err := g.Place(wq, hive.Origin); err != nil {
+		t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+	}
+	if err := g.Place(bq, hive.NewCoordinate(1, -1, 0, 0)); err != nil {
+		t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+	}
+	touching := g.Place(hive.NewPiece(hive.WhiteColor, hive.Grasshopper, hive.PieceA), hive.NewCoordinate(1, 0, -1, 0))
+	if touching == nil {
+		t.Fatal("Expected an error when placing a piece touching an opponents piece")
+	}
+
+	t.Run("When explaining a rule error the sentence names the pieces", func(t *testing.T) {
+		expected := map[Language]string{
+			English: "The white Grasshopper can't be placed there because it would touch the black Queen.",
+			Spanish: "El saltamontes blanco no se puede colocar ahí porque quedaría en contacto con la reina negra.",
+			German:  "Der weiße Grashüpfer darf dort nicht eingesetzt werden, weil dort folgende gegnerische Figuren angrenzen: die schwarze Bienenkönigin.",
+		}
+		for lang, sentence := range expected {
+			if actual := Explain(lang, touching); actual != sentence {
+				t.Errorf("Expected %q instead received %q", sentence, actual)
+			}
+		}
+	})
+
+	t.Run("When explaining a sentinel the sentence refers to a generic piece", func(t *testing.T) {
+		if actual := Explain(English, game.ErrRulePiecePinned); !strings.HasPrefix(actual, "This piece") {
+			t.Errorf("Unexpected explanation %q", actual)
+		}
+	})
+
+	t.Run("When explaining an error that isn't a rule violation the unknown message is used", func(t *testing.T) {
+		if actual := Explain(German, errors.New("boom")); actual != Lookup("de").Unknown {
+			t.Errorf("Unexpected explanation %q", actual)
+		}
+	})
+}
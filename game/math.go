@@ -1,8 +1,6 @@
 package game
 
 import (
-	"math"
-
 	. "github.com/theshadow/hive"
 )
 
@@ -10,8 +8,7 @@ import (
 // It does not validate if either coordinate is a cell with a valid piece
 // as it's mostly here for path algorithms
 func distance(a, b Coordinate) int {
-	return int(math.Round((math.Abs(float64(a.X()-b.X())) + math.Abs(float64(a.X()-b.X())) +
-		math.Abs(float64(a.X()-b.X()))) / 2))
+	return Distance(a, b)
 }
 
 func neighbors(c Coordinate) []Coordinate {
@@ -21,16 +18,3 @@ func neighbors(c Coordinate) []Coordinate {
 	}
 	return neighbors
 }
-
-func heuristic(a, b Coordinate) int {
-	return int(math.Round(math.Abs(float64(a.X()-b.X())) +
-		math.Abs(float64(a.Y()-b.Y()))))
-}
-
-const (
-	beetleMaxDistance  = 1
-	ladybugMaxDistance = 3
-	pillBugMaxDistance = 1
-	queenMaxDistance   = 1
-	spiderMaxDistance  = 3
-)
@@ -0,0 +1,290 @@
+package game
+
+import (
+	"sort"
+
+	. "github.com/theshadow/hive"
+)
+
+// LegalActions returns every action the current player may perform. When the player has nothing they can place or
+// move the only action returned is a pass. If the game is over no actions are returned.
+//
+// Candidate placements are every empty cell that touches the hive, candidate movements are every empty cell that
+// touches the hive and for climbing bugs the top of the neighboring stacks, and candidate throws are every pair of
+// cells around a Pill Bug. Each candidate is then run through the same validation that Place, Move, and Throw use so
+// the list only contains actions the engine will accept.
+//
+// The actions are returned in a stable order, placements, then movements, then throws, so that two calls against the
+// same position will always agree.
+func (g *Game) LegalActions() []Action {
+	if g.Over() {
+		return nil
+	}
+
+	actions := g.actions()
+	if len(actions) == 0 {
+		actions = append(actions, NewAction(Passed, ZeroPiece, 0, 0))
+	}
+
+	return actions
+}
+
+// Turn returns the color of the player whose turn it is, WhiteColor or BlackColor.
+func (g *Game) Turn() uint8 {
+	return g.turn
+}
+
+// Features returns the features that are enabled for this game.
+func (g *Game) Features() []Feature {
+	var features []Feature
+	for f, enabled := range g.features {
+		if enabled {
+			features = append(features, f)
+		}
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
+
+// Queen returns the coordinate of the queen for the player of the specified color. It returns false when that player
+// hasn't placed their queen yet.
+func (g *Game) Queen(color uint8) (Coordinate, bool) {
+	player := g.black
+	if color == WhiteColor {
+		player = g.white
+	}
+	placed := player.Total(Queen) > 0 && !queenInHand(player)
+	if color == WhiteColor {
+		return g.whiteQueen, placed
+	}
+	return g.blackQueen, placed
+}
+
+// Turns returns the number of the current turn, a turn is over once both players have acted. The first turn is 1.
+func (g *Game) Turns() uint {
+	return g.turns
+}
+
+// Cell returns the piece at the coordinate, it returns false when the cell is empty.
+func (g *Game) Cell(c Coordinate) (Piece, bool) {
+	return g.board.Cell(c)
+}
+
+// Locate returns the coordinate of the piece, it returns false when the piece isn't on the board.
+func (g *Game) Locate(p Piece) (Coordinate, bool) {
+	for _, cl := range g.board.Pieces() {
+		if cl.Piece == p {
+			return cl.Coordinate, true
+		}
+	}
+	return Origin, false
+}
+
+// Pieces returns every piece on the board keyed by its coordinate, each piece of a stack is at its own height.
+func (g *Game) Pieces() map[Coordinate]Piece {
+	pieces := make(map[Coordinate]Piece, len(g.board.Pieces()))
+	for _, cl := range g.board.Pieces() {
+		pieces[cl.Coordinate] = cl.Piece
+	}
+	return pieces
+}
+
+// Hand returns the pieces the player of the color has yet to place, in the order the bugs are placed by
+// LegalActions. The bugs of features that aren't enabled aren't included.
+func (g *Game) Hand(color uint8) []Piece {
+	player := g.black
+	if color == WhiteColor {
+		player = g.white
+	}
+
+	var hand []Piece
+	for _, bug := range placementOrder() {
+		if !g.bugEnabled(bug) {
+			continue
+		}
+		remaining, total := inventory(player, bug)
+		for n := total - remaining + 1; n <= total; n++ {
+			hand = append(hand, NewPiece(color, bug, uint8(n)))
+		}
+	}
+	return hand
+}
+
+// Inventory returns the pieces the player of the color was given for the game, see New. The pieces a handicap removed
+// are counted, see Hand for the pieces the player has left.
+func (g *Game) Inventory(color uint8) Inventory {
+	player := g.black
+	if color == WhiteColor {
+		player = g.white
+	}
+
+	var inv Inventory
+	removed := g.handicap.Of(color).Removed
+	for _, bug := range DefaultBugs.Bugs() {
+		// the stock was made from a valid inventory less the removed pieces so the counts are valid
+		inv, _ = inv.With(bug, player.Total(bug)+removed.Count(bug))
+	}
+	return inv
+}
+
+// Neighbors returns the pieces surrounding the coordinate, see Board.Neighbors.
+func (g *Game) Neighbors(c Coordinate) [7]Piece {
+	return g.board.Neighbors(c)
+}
+
+// actions returns the placements, movements, and throws available to the current player, it never includes a pass.
+func (g *Game) actions() []Action {
+	actions := g.placements()
+	actions = append(actions, g.movements()...)
+	return append(actions, g.throws()...)
+}
+
+func (g *Game) placements() []Action {
+	var pieces []Piece
+	player := g.currentPlayer()
+	for _, bug := range placementOrder() {
+		if !g.bugEnabled(bug) {
+			continue
+		}
+		if remaining, total := inventory(player, bug); remaining > 0 {
+			pieces = append(pieces, NewPiece(g.turn, bug, uint8(total-remaining+1)))
+		}
+	}
+
+	if len(pieces) == 0 {
+		return nil
+	}
+
+	var cells []Coordinate
+	if len(g.board.Pieces()) == 0 {
+		cells = []Coordinate{Origin}
+	} else {
+		cells = g.board.Surface()
+	}
+
+	var actions []Action
+	for _, p := range pieces {
+		for _, c := range cells {
+			if err := g.validatePlace(p, c); err == nil {
+				actions = append(actions, NewAction(Placed, p, 0, c))
+			}
+		}
+	}
+
+	return actions
+}
+
+func (g *Game) movements() []Action {
+	if queenInHand(g.currentPlayer()) {
+		return nil
+	}
+
+	var actions []Action
+	for _, cl := range g.board.Pieces() {
+		src := cl.Coordinate
+		if cl.Piece.Color() != g.turn {
+			continue
+		}
+
+		// only the top of a stack may move
+		if _, covered := g.board.Cell(src.Add(NeighborsMatrix[Above])); covered {
+			continue
+		}
+
+		def, ok := DefaultBugs.Lookup(cl.Piece.Bug())
+		if !ok {
+			continue
+		}
+		cells := def.Movement.Destinations(g.board, src)
+		sortCoordinates(cells)
+
+		for _, dst := range cells {
+			if _, err := g.validateMove(src, dst); err == nil {
+				actions = append(actions, NewAction(Moved, cl.Piece, src, dst))
+			}
+		}
+	}
+
+	return actions
+}
+
+func (g *Game) throws() []Action {
+	if !g.featureEnabled(PillBugPieceFeature) || queenInHand(g.currentPlayer()) {
+		return nil
+	}
+
+	seen := make(map[[2]Coordinate]bool)
+	var actions []Action
+	for _, cl := range g.board.Pieces() {
+		if cl.Piece.Color() != g.turn || !g.hasPillBugAbility(cl.Piece, cl.Coordinate) {
+			continue
+		}
+
+		around := neighbors(cl.Coordinate)[:Above]
+		for _, a := range around {
+			for _, b := range around {
+				if seen[[2]Coordinate{a, b}] {
+					continue
+				}
+				seen[[2]Coordinate{a, b}] = true
+				if piece, err := g.validateThrow(a, b); err == nil {
+					actions = append(actions, NewAction(Thrown, piece, a, b))
+				}
+			}
+		}
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].Src() != actions[j].Src() {
+			return actions[i].Src() < actions[j].Src()
+		}
+		return actions[i].Dst() < actions[j].Dst()
+	})
+	return actions
+}
+
+// bugEnabled returns false for the expansion bugs when their feature isn't enabled. A registered bug is played
+// whenever it's in an inventory.
+func (g *Game) bugEnabled(bug uint8) bool {
+	switch bug {
+	case Ladybug:
+		return g.featureEnabled(LadybugPieceFeature)
+	case Mosquito:
+		return g.featureEnabled(MosquitoPieceFeature)
+	case PillBug:
+		return g.featureEnabled(PillBugPieceFeature)
+	}
+	return true
+}
+
+// inventory returns the number of pieces of the bug the player has remaining and the number they started with.
+func inventory(p Stock, bug uint8) (remaining, total int) {
+	return p.Remaining(bug), p.Total(bug)
+}
+
+func ground(c Coordinate) Coordinate {
+	return NewCoordinate(c.X(), c.Y(), c.Z(), 0)
+}
+
+func sortCoordinates(cells []Coordinate) {
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// placementOrder returns the bugs in the order they're placed by LegalActions, the bugs of the game followed by any
+// registered with DefaultBugs.
+func placementOrder() []uint8 {
+	order := []uint8{Queen, Ant, Grasshopper, Beetle, Spider, Mosquito, Ladybug, PillBug}
+	for _, bug := range DefaultBugs.Bugs() {
+		if bug > PillBug {
+			order = append(order, bug)
+		}
+	}
+	return order
+}
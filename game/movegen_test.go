@@ -0,0 +1,253 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/theshadow/hive"
+)
+
+func TestGame_LegalActions(t *testing.T) {
+	t.Run("When the game starts white may place each of their bugs at the origin", func(t *testing.T) {
+		g := New(nil)
+		actions := g.LegalActions()
+		if len(actions) != 5 {
+			t.Errorf("Expected %d actions instead received %d", 5, len(actions))
+		}
+		for _, a := range actions {
+			if !a.WasPlaced() || a.Dst() != hive.Origin {
+				t.Errorf("Unexpected action %s", a)
+			}
+		}
+	})
+
+	t.Run("When expansion features are enabled their bugs may be placed", func(t *testing.T) {
+		g := New([]Feature{LadybugPieceFeature, MosquitoPieceFeature, PillBugPieceFeature})
+		if n := len(g.LegalActions()); n != 8 {
+			t.Errorf("Expected %d actions instead received %d", 8, n)
+		}
+	})
+
+	t.Run("When an expansion feature isn't enabled its bug may not be placed", func(t *testing.T) {
+		for _, bug := range []uint8{hive.Ladybug, hive.Mosquito, hive.PillBug} {
+			g := New(nil)
+			p := hive.NewPiece(hive.WhiteColor, bug, hive.PieceA)
+			if err := g.Place(p, hive.Origin); !errors.Is(err, ErrRuleBugNotEnabled) {
+				t.Errorf("Expected an error of type %#v for %s instead received %#v", ErrRuleBugNotEnabled, p, err)
+			}
+		}
+	})
+
+	t.Run("When black places their first piece it may touch any side of whites piece", func(t *testing.T) {
+		g := New(nil)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Spider, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if n := len(g.LegalActions()); n != 30 {
+			t.Errorf("Expected %d actions instead received %d", 30, n)
+		}
+	})
+
+	t.Run("When playing every legal action the engine accepts it", func(t *testing.T) {
+		g := New(nil)
+		for ply := 0; ply < 12; ply++ {
+			actions := g.LegalActions()
+			if len(actions) == 0 {
+				break
+			}
+			for _, a := range actions {
+				c := New(nil)
+				for _, h := range g.History() {
+					if err := c.Play(h); err != nil {
+						t.Fatalf("Unexpected error %#v while replaying %s", err, h)
+					}
+				}
+				if err := c.Play(a); err != nil {
+					t.Fatalf("Legal action %s was rejected with %#v", a, err)
+				}
+			}
+			if err := g.Play(actions[len(actions)/2]); err != nil {
+				t.Fatalf("Unexpected error %#v", err)
+			}
+		}
+	})
+}
+
+func TestGame_Pass(t *testing.T) {
+	t.Run("When the player has an action available passing returns an error", func(t *testing.T) {
+		g := New(nil)
+		if err := g.Pass(); !errors.Is(err, ErrRuleMayNotPass) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleMayNotPass, err)
+		}
+	})
+}
+
+func TestGame_Play(t *testing.T) {
+	t.Run("When moving a piece the history records a movement", func(t *testing.T) {
+		g := New(nil)
+		steps := []hive.Action{
+			hive.NewAction(hive.Placed, hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), 0, hive.Origin),
+			hive.NewAction(hive.Placed, hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), 0,
+				hive.NewCoordinate(0, -1, 1, 0)),
+			hive.NewAction(hive.Moved, hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin,
+				hive.NewCoordinate(1, -1, 0, 0)),
+		}
+		for _, a := range steps {
+			if err := g.Play(a); err != nil {
+				t.Fatalf("Unexpected error %#v while playing %s", err, a)
+			}
+		}
+
+		history := g.History()
+		if !history[2].WasMoved() {
+			t.Errorf("Expected the last action to be a movement instead received %s", history[2])
+		}
+	})
+}
+
+func TestGame_Hand(t *testing.T) {
+	t.Run("When a piece is placed it leaves the hand and appears on the board", func(t *testing.T) {
+		g := New([]Feature{MosquitoPieceFeature})
+		if n := len(g.Hand(hive.WhiteColor)); n != 12 {
+			t.Errorf("Expected 12 pieces in hand instead received %d", n)
+		}
+
+		ant := hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA)
+		if err := g.Place(ant, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while placing the ant", err)
+		}
+		hand := g.Hand(hive.WhiteColor)
+		if len(hand) != 11 || hand[1] != hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceB) {
+			t.Errorf("Expected the second ant to be next in hand instead received %v", hand)
+		}
+		if n := len(g.Hand(hive.BlackColor)); n != 12 {
+			t.Errorf("Expected black to still have 12 pieces instead received %d", n)
+		}
+		if pieces := g.Pieces(); len(pieces) != 1 || pieces[hive.Origin] != ant {
+			t.Errorf("Expected the ant on the board instead received %v", pieces)
+		}
+	})
+}
+
+func TestGame_Inventory(t *testing.T) {
+	queenAndAnts, _ := hive.NewInventory(map[uint8]int{hive.Queen: 1, hive.Ant: 2})
+	ants, _ := hive.NewInventory(map[uint8]int{hive.Ant: 2})
+
+	t.Run("When a game is made with an inventory the players only have its pieces", func(t *testing.T) {
+		g := New(nil, queenAndAnts)
+		if n := len(g.LegalActions()); n != 2 {
+			t.Errorf("Expected %d actions instead received %d", 2, n)
+		}
+		for _, color := range []uint8{hive.WhiteColor, hive.BlackColor} {
+			if g.Inventory(color) != queenAndAnts || len(g.Hand(color)) != 3 {
+				t.Errorf("Expected %s instead received %s", queenAndAnts, g.Inventory(color))
+			}
+		}
+		if g.Inventory(hive.WhiteColor) == hive.StandardInventory || New(nil).Inventory(hive.BlackColor) != hive.StandardInventory {
+			t.Error("Expected only a game without inventories to have the standard set")
+		}
+	})
+
+	t.Run("When a piece isn't in the inventory it may not be placed", func(t *testing.T) {
+		g := New(nil, queenAndAnts)
+		for _, p := range []hive.Piece{
+			hive.NewPiece(hive.WhiteColor, hive.Spider, hive.PieceA),
+			hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceC),
+		} {
+			if err := g.Place(p, hive.Origin); !errors.Is(err, hive.ErrNoPieceAvailable) {
+				t.Errorf("Expected an error of type %#v instead received %#v", hive.ErrNoPieceAvailable, err)
+			}
+		}
+	})
+
+	t.Run("When the players have different inventories each plays their own", func(t *testing.T) {
+		g := New(nil, ants, queenAndAnts)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing", err)
+		}
+		if err := g.Place(hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, 1, -1, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing", err)
+		}
+		// white has no queen to place, so their pieces may move straight away
+		if _, placed := g.Queen(hive.WhiteColor); placed {
+			t.Error("Expected white to have no queen on the board")
+		}
+		moved := false
+		for _, a := range g.LegalActions() {
+			moved = moved || a.WasMoved()
+		}
+		if !moved {
+			t.Error("Expected white to be able to move without a queen")
+		}
+
+		c := g.Clone()
+		if c.Inventory(hive.WhiteColor) != ants || len(c.Hand(hive.WhiteColor)) != 1 {
+			t.Errorf("Expected the clone to keep the inventory instead received %s", c.Inventory(hive.WhiteColor))
+		}
+		r, err := g.Replay()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while replaying", err)
+		}
+		if r.Game().Inventory(hive.BlackColor) != queenAndAnts {
+			t.Error("Expected the replay to keep the inventories")
+		}
+	})
+}
+
+func TestGame_RegisteredBug(t *testing.T) {
+	// the hopper only ever lands two steps away
+	const hopper = hive.PillBug + 1
+	err := hive.RegisterBug(hopper, hive.BugDefinition{
+		Label:   "Hopper",
+		Letter:  'H',
+		Pieces:  1,
+		Profile: hive.Jumper,
+		Movement: hive.MovementFunc(func(b *hive.Board, src hive.Coordinate) []hive.Coordinate {
+			var cells []hive.Coordinate
+			for _, c := range b.Surface(src) {
+				if hive.Distance(src, c) == 2 {
+					cells = append(cells, c)
+				}
+			}
+			return cells
+		}),
+	})
+	if err != nil && !errors.Is(err, hive.ErrBugRegistered) {
+		t.Fatalf("Unexpected error %#v while registering the bug", err)
+	}
+
+	inv, _ := hive.NewInventory(map[uint8]int{hive.Queen: 1, hopper: 1})
+	g := New(nil, inv)
+	whiteHopper := hive.NewPiece(hive.WhiteColor, hopper, hive.PieceA)
+	src := hive.NewCoordinate(0, -1, 1, 0)
+	for _, a := range []hive.Action{
+		hive.NewAction(hive.Placed, hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), 0, hive.Origin),
+		hive.NewAction(hive.Placed, hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), 0, hive.NewCoordinate(0, 1, -1, 0)),
+		hive.NewAction(hive.Placed, whiteHopper, 0, src),
+		hive.NewAction(hive.Placed, hive.NewPiece(hive.BlackColor, hopper, hive.PieceA), 0, hive.NewCoordinate(0, 2, -2, 0)),
+	} {
+		if err := g.Play(a); err != nil {
+			t.Fatalf("Unexpected error %#v while playing %s", err, a)
+		}
+	}
+
+	t.Run("When a registered bug moves only its destinations are legal", func(t *testing.T) {
+		for _, a := range g.LegalActions() {
+			if a.Piece() == whiteHopper && hive.Distance(a.Src(), a.Dst()) != 2 {
+				t.Errorf("Expected the hopper to land two steps away instead received %s", a)
+			}
+		}
+		if err := g.Move(src, hive.NewCoordinate(1, -1, 0, 0)); !errors.Is(err, ErrRuleBugCannotReach) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleBugCannotReach, err)
+		}
+		if err := g.Clone().Move(src, hive.NewCoordinate(1, 0, -1, 0)); err != nil {
+			t.Errorf("Unexpected error %#v while the hopper was moving", err)
+		}
+	})
+
+	t.Run("When a registered bug is in an inventory it's in the hand of the player", func(t *testing.T) {
+		if hand := New(nil, inv).Hand(hive.BlackColor); len(hand) != 2 || hand[1].BugS() != "Hopper" {
+			t.Errorf("Expected a queen and a hopper instead received %v", hand)
+		}
+	})
+}
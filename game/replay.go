@@ -0,0 +1,173 @@
+package game
+
+import (
+	"fmt"
+
+	. "github.com/theshadow/hive"
+)
+
+// Replay gives random access to the positions of a game, from the empty board at ply 0 up to the position after the
+// last action of the history. Every position may be read with the accessors, or copied out with Game.
+//
+// The history is replayed once when the Replay is made and a copy of the game is kept every replayInterval plies, so
+// a Seek only ever replays the actions since the closest checkpoint.
+type Replay struct {
+	history []Action
+
+	// checkpoints[i] is the game after i*replayInterval actions.
+	checkpoints []*Game
+
+	// current is the game after ply actions.
+	current *Game
+	ply     int
+}
+
+// NewReplay replays the history on a new game with the features and the inventories, see New. It returns the error of
+// the first action the game refuses, along with its ply.
+func NewReplay(features []Feature, history []Action, inventories ...Inventory) (*Replay, error) {
+	return newReplay(New(features, inventories...), history)
+}
+
+// Replay returns a replay of the history of the game, from a game set up as it was, see Initial. A clock isn't part of
+// the replay, the positions are those of an untimed game.
+func (g *Game) Replay() (*Replay, error) {
+	return newReplay(g.Initial(), g.History())
+}
+
+func newReplay(start *Game, history []Action) (*Replay, error) {
+	r := &Replay{current: start}
+	r.checkpoints = []*Game{start.Clone()}
+	for _, a := range history {
+		if err := r.Append(a); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Append plays the action after the last action of the history and moves to the new position.
+func (r *Replay) Append(a Action) error {
+	if err := r.Seek(len(r.history)); err != nil {
+		return err
+	}
+	if err := r.current.Play(a); err != nil {
+		return fmt.Errorf("ply %d: %w", r.ply+1, err)
+	}
+	r.history = append(r.history, a)
+	r.ply++
+	if r.ply%replayInterval == 0 {
+		r.checkpoints = append(r.checkpoints, r.current.Clone())
+	}
+	return nil
+}
+
+// Truncate forgets the actions of the history after the first n, the replay stays at the same ply unless it was past
+// the new end.
+func (r *Replay) Truncate(n int) error {
+	if n < 0 || n > len(r.history) {
+		return fmt.Errorf("%w: %d of %d", ErrPlyOutOfRange, n, len(r.history))
+	}
+	if r.ply > n {
+		if err := r.Seek(n); err != nil {
+			return err
+		}
+	}
+	r.history = r.history[:n:n]
+	r.checkpoints = r.checkpoints[:n/replayInterval+1]
+	return nil
+}
+
+// Len returns the number of actions of the history, the last ply.
+func (r *Replay) Len() int {
+	return len(r.history)
+}
+
+// Ply returns the number of actions played to reach the current position.
+func (r *Replay) Ply() int {
+	return r.ply
+}
+
+// Seek moves to the position after the first n actions of the history. It returns ErrPlyOutOfRange when n is
+// negative or beyond the last ply.
+func (r *Replay) Seek(n int) error {
+	if n < 0 || n > len(r.history) {
+		return fmt.Errorf("%w: %d of %d", ErrPlyOutOfRange, n, len(r.history))
+	}
+	if n == r.ply {
+		return nil
+	}
+
+	// moving forward within the checkpoint is cheaper than starting over from it
+	checkpoint := n / replayInterval
+	if n < r.ply || r.ply < checkpoint*replayInterval {
+		r.current = r.checkpoints[checkpoint].Clone()
+		r.ply = checkpoint * replayInterval
+	}
+	for ; r.ply < n; r.ply++ {
+		// the actions were all played once already, they can't be refused
+		if err := r.current.Play(r.history[r.ply]); err != nil {
+			panic(fmt.Sprintf("game: replaying ply %d: %s", r.ply+1, err))
+		}
+	}
+	return nil
+}
+
+// Next moves forward a ply, it returns false at the last ply.
+func (r *Replay) Next() bool {
+	return r.ply < len(r.history) && r.Seek(r.ply+1) == nil
+}
+
+// Prev moves back a ply, it returns false at the start of the game.
+func (r *Replay) Prev() bool {
+	return r.ply > 0 && r.Seek(r.ply-1) == nil
+}
+
+// Action returns the action that led to the current position, it returns false at the start of the game.
+func (r *Replay) Action() (Action, bool) {
+	if r.ply == 0 {
+		return Action{}, false
+	}
+	return r.history[r.ply-1], true
+}
+
+// History returns every action of the replay, not only those played to reach the current position.
+func (r *Replay) History() []Action {
+	history := make([]Action, len(r.history))
+	copy(history, r.history)
+	return history
+}
+
+// Game returns a copy of the game at the current position, acting on it doesn't change the replay.
+func (r *Replay) Game() *Game {
+	return r.current.Clone()
+}
+
+// Features returns the features of the replayed game.
+func (r *Replay) Features() []Feature {
+	return r.current.Features()
+}
+
+// Turn returns the color of the player whose turn it is at the current position.
+func (r *Replay) Turn() uint8 {
+	return r.current.Turn()
+}
+
+// Pieces returns the pieces on the board at the current position, see Game.Pieces.
+func (r *Replay) Pieces() map[Coordinate]Piece {
+	return r.current.Pieces()
+}
+
+// Hand returns the pieces the player of the color has yet to place at the current position, see Game.Hand.
+func (r *Replay) Hand(color uint8) []Piece {
+	return r.current.Hand(color)
+}
+
+// Result returns the result at the current position, ErrGameNotOver when the game isn't over yet.
+func (r *Replay) Result() (Result, error) {
+	return r.current.Result()
+}
+
+// replayInterval is the number of plies between the checkpoints of a Replay.
+const replayInterval = 32
+
+var ErrPlyOutOfRange = fmt.Errorf("the ply is outside of the history")
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/theshadow/hive"
+)
+
+func TestReplay(t *testing.T) {
+	// played plays a game long enough to span a few checkpoints and keeps the hash of every position
+	played := func(t *testing.T) (*Game, []uint64) {
+		t.Helper()
+		g := New(nil)
+		hashes := []uint64{g.Hash()}
+		for ply := 0; ply < 3*replayInterval+5 && !g.Over(); ply++ {
+			actions := g.LegalActions()
+			if err := g.Play(actions[ply%len(actions)]); err != nil {
+				t.Fatalf("Unexpected error %#v while playing ply %d", err, ply+1)
+			}
+			hashes = append(hashes, g.Hash())
+		}
+		return g, hashes
+	}
+
+	t.Run("When seeking to any ply the position is the one reached after that many actions", func(t *testing.T) {
+		g, hashes := played(t)
+		r, err := g.Replay()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while making the replay", err)
+		}
+		if r.Len() != len(hashes)-1 || r.Ply() != r.Len() {
+			t.Fatalf("Expected the replay to be at ply %d instead received %d of %d", len(hashes)-1, r.Ply(), r.Len())
+		}
+		for _, n := range []int{0, 40, 3, replayInterval, 2*replayInterval + 1, r.Len(), replayInterval - 1, 1} {
+			if err := r.Seek(n); err != nil {
+				t.Fatalf("Unexpected error %#v while seeking to ply %d", err, n)
+			}
+			if r.Ply() != n {
+				t.Errorf("Expected ply %d instead received %d", n, r.Ply())
+			}
+			if r.Game().Hash() != hashes[n] {
+				t.Errorf("Expected the position of ply %d", n)
+			}
+		}
+	})
+
+	t.Run("When stepping forward and back each position is visited in turn", func(t *testing.T) {
+		g, hashes := played(t)
+		r, _ := g.Replay()
+		for r.Prev() {
+			if r.Game().Hash() != hashes[r.Ply()] {
+				t.Errorf("Expected the position of ply %d while stepping back", r.Ply())
+			}
+		}
+		if r.Ply() != 0 {
+			t.Errorf("Expected to stop at ply 0 instead received %d", r.Ply())
+		}
+		if _, ok := r.Action(); ok {
+			t.Error("Expected no action before the first ply")
+		}
+		for r.Next() {
+			if r.Game().Hash() != hashes[r.Ply()] {
+				t.Errorf("Expected the position of ply %d while stepping forward", r.Ply())
+			}
+			if a, _ := r.Action(); a != g.History()[r.Ply()-1] {
+				t.Errorf("Expected action %s at ply %d instead received %s", g.History()[r.Ply()-1], r.Ply(), a)
+			}
+		}
+		if r.Ply() != r.Len() {
+			t.Errorf("Expected to stop at ply %d instead received %d", r.Len(), r.Ply())
+		}
+	})
+
+	t.Run("When the history is truncated the later actions are forgotten", func(t *testing.T) {
+		g, hashes := played(t)
+		r, _ := g.Replay()
+		if err := r.Truncate(replayInterval + 1); err != nil {
+			t.Fatalf("Unexpected error %#v while truncating", err)
+		}
+		if r.Len() != replayInterval+1 || r.Ply() != r.Len() || r.Game().Hash() != hashes[r.Len()] {
+			t.Errorf("Expected to be at the end of ply %d instead received %d of %d", replayInterval+1, r.Ply(), r.Len())
+		}
+		if err := r.Append(g.History()[replayInterval+1]); err != nil {
+			t.Fatalf("Unexpected error %#v while appending", err)
+		}
+		if err := r.Seek(0); err != nil || r.Seek(r.Len()) != nil || r.Game().Hash() != hashes[r.Len()] {
+			t.Errorf("Expected the appended action to be replayed")
+		}
+	})
+
+	t.Run("When seeking outside of the history an error is returned", func(t *testing.T) {
+		r, _ := NewReplay(nil, nil)
+		for _, n := range []int{-1, 1} {
+			if err := r.Seek(n); !errors.Is(err, ErrPlyOutOfRange) {
+				t.Errorf("Expected ErrPlyOutOfRange seeking to %d instead received %#v", n, err)
+			}
+		}
+	})
+
+	t.Run("When the history has an illegal action the replay isn't made", func(t *testing.T) {
+		spider := hive.NewPiece(hive.WhiteColor, hive.Spider, hive.PieceA)
+		history := []hive.Action{
+			hive.NewAction(hive.Placed, spider, hive.Origin, hive.Origin),
+			hive.NewAction(hive.Placed, spider, hive.Origin, hive.Origin),
+		}
+		if _, err := NewReplay(nil, history); RuleOf(err) == NoRule {
+			t.Errorf("Expected a rule error instead received %#v", err)
+		}
+	})
+
+	t.Run("When reading an earlier position the hands and the result are those of that ply", func(t *testing.T) {
+		g := New(nil)
+		spider := hive.NewPiece(hive.WhiteColor, hive.Spider, hive.PieceA)
+		if err := g.Place(spider, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while placing", err)
+		}
+		if err := g.Resign(hive.BlackColor); err != nil {
+			t.Fatalf("Unexpected error %#v while resigning", err)
+		}
+		r, err := g.Replay()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while making the replay", err)
+		}
+		if res, err := r.Result(); err != nil || res.Reason != Resignation {
+			t.Errorf("Expected a resignation at the last ply instead received %s, %#v", res, err)
+		}
+		_ = r.Seek(0)
+		if _, err := r.Result(); !errors.Is(err, ErrGameNotOver) {
+			t.Errorf("Expected ErrGameNotOver at the first ply instead received %#v", err)
+		}
+		if len(r.Pieces()) != 0 || len(r.Hand(hive.WhiteColor)) != 11 {
+			t.Errorf("Expected an empty board and a full hand instead received %d pieces and %d in hand",
+				len(r.Pieces()), len(r.Hand(hive.WhiteColor)))
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+
+	. "github.com/theshadow/hive"
+)
+
+// Reason describes how a game ended.
+type Reason uint8
+
+const (
+	NoReason Reason = iota
+
+	// Suffocation is the end of a game on the board, a queen was surrounded. When both queens are surrounded by the
+	// same action the game is a tie.
+	Suffocation
+
+	// Resignation is a player conceding the game, see Resign.
+	Resignation
+
+	// Timeout is a player running out of time, see StartClock.
+	Timeout
+
+	// Agreement is a draw the players agreed to, see AgreeDraw.
+	Agreement
+
+	// Repetition is a draw because the same position came up for the third time.
+	Repetition
+
+	// Adjudication is a result declared from outside of the game, a server deciding an abandoned game for example,
+	// see Adjudicate.
+	Adjudication
+)
+
+var reasonLabels = []string{
+	"None",
+	"Suffocation",
+	"Resignation",
+	"Timeout",
+	"Agreement",
+	"Repetition",
+	"Adjudication",
+}
+
+func (r Reason) String() string {
+	if int(r) < len(reasonLabels) {
+		return reasonLabels[r]
+	}
+	return reasonLabels[NoReason]
+}
+
+// Result is how a game ended, the winner along with the reason. A drawn game has a Tie winner.
+type Result struct {
+	Winner Winner
+	Reason Reason
+}
+
+func (r Result) String() string {
+	if r.Winner == Tie {
+		return fmt.Sprintf("Draw by %s", r.Reason)
+	}
+	return fmt.Sprintf("%s wins by %s", r.Winner, r.Reason)
+}
+
+// MarshalJSON encodes the result as {"winner": "White", "reason": "Resignation"}.
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonResult{Winner: r.Winner.String(), Reason: r.Reason.String()})
+}
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var v jsonResult
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var res Result
+	w, err := ParseWinner(v.Winner)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidEncoding, err)
+	}
+	res.Winner = w
+	for i, label := range reasonLabels {
+		if i != int(NoReason) && label == v.Reason {
+			res.Reason = Reason(i)
+		}
+	}
+	if res.Reason == NoReason {
+		return fmt.Errorf("%w: unknown reason %q", ErrInvalidEncoding, v.Reason)
+	}
+
+	*r = res
+	return nil
+}
+
+type jsonResult struct {
+	Winner string `json:"winner"`
+	Reason string `json:"reason"`
+}
+
+// Result returns how the game ended, if the game is not over it returns ErrGameNotOver.
+func (g *Game) Result() (Result, error) {
+	r, over := g.outcome()
+	if !over {
+		return Result{}, ErrGameNotOver
+	}
+	return r, nil
+}
+
+// Resign ends the game with the player of the color conceding it. A player may resign at any time, it doesn't have to
+// be their turn. The resignation is recorded in the history.
+func (g *Game) Resign(color uint8) error {
+	opponent, err := opponentOf(color)
+	if err != nil {
+		return err
+	}
+	if g.Over() {
+		return ErrGameOver
+	}
+
+	g.conclude(Resigned, color, Result{Winner: opponent, Reason: Resignation})
+	return nil
+}
+
+// AgreeDraw ends the game in a draw the players agreed to. The engine doesn't track offers, it's up to the application
+// to make sure both players agreed before calling it. The draw is recorded in the history.
+func (g *Game) AgreeDraw() error {
+	if g.Over() {
+		return ErrGameOver
+	}
+
+	g.conclude(Drawn, NoColor, Result{Winner: Tie, Reason: Agreement})
+	return nil
+}
+
+// Adjudicate ends the game with the winner declared from outside of the game, Tie for a draw. Servers use it to decide
+// games that can't be finished, an abandoned game for example. The adjudication is recorded in the history.
+func (g *Game) Adjudicate(w Winner) error {
+	color := NoColor
+	switch w {
+	case WhitePlayer:
+		color = WhiteColor
+	case BlackPlayer:
+		color = BlackColor
+	case Tie:
+	default:
+		return ErrUnknownWinner
+	}
+	if g.Over() {
+		return ErrGameOver
+	}
+
+	g.conclude(Adjudicated, color, Result{Winner: w, Reason: Adjudication})
+	return nil
+}
+
+// ClaimTimeout ends a timed game once the player whose turn it is has run out of time, recording the loss in the
+// history. The engine notices a fallen flag on its own when the player tries to act, a server calls ClaimTimeout when
+// the player doesn't. It returns ErrTimeRemaining when the player still has time or the game isn't timed.
+func (g *Game) ClaimTimeout() error {
+	if g.concluded() {
+		return ErrGameOver
+	}
+	if g.timer == nil || !g.flagged() {
+		return ErrTimeRemaining
+	}
+
+	g.timeout()
+	return nil
+}
+
+// concluded returns true when the game was ended by one of the actions that don't touch the board.
+func (g *Game) concluded() bool {
+	return g.result.Reason != NoReason
+}
+
+// conclude ends the game without touching the board. The action is recorded with the color, which may be NoColor, in
+// place of a piece.
+func (g *Game) conclude(act uint8, color uint8, r Result) {
+	g.stopClock()
+	if t := g.timer; t != nil {
+		t.played = append(t.played, t.clock.Now())
+	}
+	g.result = r
+	g.history = append(g.history, NewAction(act, NewPiece(color, NoBug, NoPiece), 0, 0))
+	g.notify()
+}
+
+// timeout records the loss on time of the player whose turn it is.
+func (g *Game) timeout() {
+	opponent, _ := opponentOf(g.turn)
+	g.conclude(TimedOut, g.turn, Result{Winner: opponent, Reason: Timeout})
+}
+
+// playConclusion replays one of the actions that don't touch the board. A replayed timeout is only checked against the
+// clock when the game is timed, a game rebuilt from its history isn't.
+func (g *Game) playConclusion(a Action) error {
+	color := a.Piece().Color()
+	switch a.Act() {
+	case Resigned:
+		return g.Resign(color)
+	case Drawn:
+		return g.AgreeDraw()
+	case Adjudicated:
+		return g.Adjudicate(winnerOf(color))
+	case TimedOut:
+		if color != g.turn {
+			return newRuleError(ErrRuleNotPlayersTurn, TimedOut, a.Piece(), Origin, Origin)
+		}
+		if g.timer != nil {
+			return g.ClaimTimeout()
+		}
+		if g.Over() {
+			return ErrGameOver
+		}
+		g.timeout()
+		return nil
+	}
+	return ErrUnknownAction
+}
+
+// outcome works out the result of the game, it returns false while the game is still being played.
+func (g *Game) outcome() (Result, bool) {
+	if g.concluded() {
+		return g.result, true
+	}
+
+	for _, rule := range g.RuleSet().VictoryRules() {
+		if r, over := rule(g); over {
+			return r, true
+		}
+	}
+	if g.flagged() {
+		opponent, _ := opponentOf(g.turn)
+		return Result{Winner: opponent, Reason: Timeout}, true
+	}
+	return Result{}, false
+}
+
+// trackRepetition records the position after an action and notes when it has come up for the third time.
+func (g *Game) trackRepetition() {
+	h := g.Hash()
+	seen := 1
+	for _, p := range g.positions {
+		if p == h {
+			seen++
+		}
+	}
+	g.positions = append(g.positions, h)
+	if seen >= repetitionLimit {
+		g.repeated = true
+	}
+}
+
+// winnerOf returns the player of the color as a Winner, Tie for NoColor.
+func winnerOf(color uint8) Winner {
+	switch color {
+	case WhiteColor:
+		return WhitePlayer
+	case BlackColor:
+		return BlackPlayer
+	}
+	return Tie
+}
+
+// opponentOf returns the opposing player of the color as a Winner.
+func opponentOf(color uint8) (Winner, error) {
+	switch color {
+	case WhiteColor:
+		return BlackPlayer, nil
+	case BlackColor:
+		return WhitePlayer, nil
+	}
+	return Tie, ErrUnknownColor
+}
+
+// repetitionLimit is the number of times a position may come up before the game is drawn.
+const repetitionLimit = 3
+
+var ErrGameOver = fmt.Errorf("the game is over and no more actions may be performed")
+var ErrUnknownColor = fmt.Errorf("an unknown color was encountered")
+var ErrUnknownWinner = fmt.Errorf("an unknown winner was encountered")
+var ErrTimeRemaining = fmt.Errorf("the player whose turn it is still has time on their clock")
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+)
+
+func TestGame_Result(t *testing.T) {
+	whiteAnt := hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA)
+	blackAnt := hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA)
+	south := hive.NewCoordinate(0, -1, 1, 0)
+
+	opening := func(t *testing.T) *Game {
+		t.Helper()
+		g := New(nil)
+		if err := g.Place(whiteAnt, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := g.Place(blackAnt, south); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+		}
+		return g
+	}
+
+	t.Run("When a player resigns their opponent wins and the resignation is recorded", func(t *testing.T) {
+		g := opening(t)
+		if err := g.Resign(hive.BlackColor); err != nil {
+			t.Fatalf("Unexpected error %#v while black was resigning", err)
+		}
+
+		if r, err := g.Result(); err != nil || r != (Result{Winner: WhitePlayer, Reason: Resignation}) {
+			t.Errorf("Expected white to win by resignation instead received %s, %v", r, err)
+		}
+		history := g.History()
+		if last := history[len(history)-1]; !last.WasResigned() || last.Piece().Color() != hive.BlackColor {
+			t.Errorf("Expected the resignation of black to be the last action instead received %s", last)
+		}
+	})
+
+	t.Run("When the game is over no more actions may be performed", func(t *testing.T) {
+		g := opening(t)
+		if err := g.AgreeDraw(); err != nil {
+			t.Fatalf("Unexpected error %#v while agreeing to a draw", err)
+		}
+
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.NewCoordinate(0, 1, -1, 0)); !errors.Is(err, ErrGameOver) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrGameOver, err)
+		}
+		if err := g.Resign(hive.WhiteColor); !errors.Is(err, ErrGameOver) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrGameOver, err)
+		}
+		if r, _ := g.Result(); r != (Result{Winner: Tie, Reason: Agreement}) {
+			t.Errorf("Expected a draw by agreement instead received %s", r)
+		}
+	})
+
+	t.Run("When a game is adjudicated the declared winner wins", func(t *testing.T) {
+		g := opening(t)
+		if err := g.Adjudicate(BlackPlayer); err != nil {
+			t.Fatalf("Unexpected error %#v while adjudicating the game", err)
+		}
+		if w, err := g.Winner(); err != nil || w != BlackPlayer {
+			t.Errorf("Expected black to win instead received %s, %v", w, err)
+		}
+		if r, _ := g.Result(); r.Reason != Adjudication {
+			t.Errorf("Expected the reason %s instead received %s", Adjudication, r.Reason)
+		}
+	})
+
+	t.Run("When the same position comes up for the third time the game is drawn", func(t *testing.T) {
+		g := New(nil)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := g.Place(hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), south); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+		}
+
+		// the queens walk around each other a step at a time, every move keeps them touching
+		southeast := hive.NewCoordinate(1, -1, 0, 0)
+		moves := [][2]hive.Coordinate{
+			{hive.Origin, southeast}, {south, hive.Origin}, {southeast, south},
+			{hive.Origin, southeast}, {south, hive.Origin}, {southeast, south},
+		}
+		for i := 0; i < 2; i++ {
+			for _, m := range moves {
+				if g.Over() {
+					t.Fatalf("Expected the game to be running before the position repeated for the third time")
+				}
+				if err := g.Move(m[0], m[1]); err != nil {
+					t.Fatalf("Unexpected error %#v while moving from %s to %s", err, m[0], m[1])
+				}
+			}
+		}
+
+		if r, err := g.Result(); err != nil || r != (Result{Winner: Tie, Reason: Repetition}) {
+			t.Errorf("Expected a draw by repetition instead received %s, %v", r, err)
+		}
+	})
+
+	t.Run("When a player acts after their time ran out the timeout is recorded", func(t *testing.T) {
+		clock := NewManualClock(time.Unix(0, 0))
+		g := New(nil)
+		if err := g.StartClock(SuddenDeath(time.Minute), clock); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		if err := g.ClaimTimeout(); !errors.Is(err, ErrTimeRemaining) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrTimeRemaining, err)
+		}
+
+		clock.Advance(time.Minute)
+		if err := g.Place(whiteAnt, hive.Origin); !errors.Is(err, ErrRuleOutOfTime) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleOutOfTime, err)
+		}
+		if history := g.History(); len(history) != 1 || !history[0].WasTimedOut() {
+			t.Errorf("Expected the timeout to be recorded instead received %v", history)
+		}
+		if r, _ := g.Result(); r != (Result{Winner: BlackPlayer, Reason: Timeout}) {
+			t.Errorf("Expected black to win on time instead received %s", r)
+		}
+	})
+
+	t.Run("When a concluded game is encoded and decoded the result is kept", func(t *testing.T) {
+		g := opening(t)
+		if err := g.Resign(hive.WhiteColor); err != nil {
+			t.Fatalf("Unexpected error %#v while white was resigning", err)
+		}
+
+		data, err := json.Marshal(g)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while encoding the game", err)
+		}
+		var v struct {
+			Result Result `json:"result"`
+		}
+		if err := json.Unmarshal(data, &v); err != nil || v.Result != (Result{Winner: BlackPlayer, Reason: Resignation}) {
+			t.Errorf("Expected the encoded result to be a resignation by white instead received %s, %v", v.Result, err)
+		}
+
+		decoded := New(nil)
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("Unexpected error %#v while decoding the game", err)
+		}
+		if r, err := decoded.Result(); err != nil || r != (Result{Winner: BlackPlayer, Reason: Resignation}) {
+			t.Errorf("Expected the decoded game to be resigned by white instead received %s, %v", r, err)
+		}
+	})
+
+	t.Run("When resigning for an unknown color an error is returned", func(t *testing.T) {
+		if err := opening(t).Resign(hive.NoColor); !errors.Is(err, ErrUnknownColor) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrUnknownColor, err)
+		}
+	})
+}
@@ -1,6 +1,12 @@
 package game
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	. "github.com/theshadow/hive"
+)
 
 var (
 	ErrRuleFirstPieceMustBeAtOrigin          = fmt.Errorf("the first piece to be placed must be placed at origin")
@@ -15,4 +21,159 @@ var (
 	ErrRuleMustPlaceQueenToMove              = fmt.Errorf("the players queen must be placed before a placed piece may move")
 	ErrRulePieceAlreadyParalyzed             = fmt.Errorf("the piece is already paralyzed and may not be stunned again this turn")
 	ErrRuleMovementDistanceTooGreat          = fmt.Errorf("the distance for the movement is too great for this piece")
+	ErrRuleMayNotPass                        = fmt.Errorf("a player may only pass when they have no other action available")
+	ErrRuleNoPillBugToThrow                  = fmt.Errorf("there is no pill bug able to throw the piece")
+	ErrRuleMayNotThrowStackedPiece           = fmt.Errorf("a piece that is part of a stack may not be thrown")
+	ErrRuleMayNotThrowLastMovedPiece         = fmt.Errorf("the piece moved on the previous turn may not be thrown")
+	ErrRuleMayNotSplitHive                   = fmt.Errorf("a piece may not move if it would split the hive in two")
+	ErrRuleOutOfTime                         = fmt.Errorf("the player has run out of time")
+	ErrRuleBugCannotReach                    = fmt.Errorf("the piece can't reach the destination the way its bug moves")
+	ErrRuleBugNotEnabled                     = fmt.Errorf("the bug is only played when its expansion is enabled")
+)
+
+// RuleID is a stable, machine readable, identifier for a rule of the game. Unlike the messages of the rule errors the
+// identifiers will never change, so they are safe to log, store, and switch on in a client.
+type RuleID string
+
+const (
+	NoRule                               RuleID = ""
+	RuleFirstPieceMustBeAtOrigin         RuleID = "first-piece-must-be-at-origin"
+	RuleMayNotPlaceAPieceOnAPiece        RuleID = "may-not-place-a-piece-on-a-piece"
+	RuleMustPlacePieceOnSurface          RuleID = "must-place-piece-on-surface"
+	RulePieceParalyzed                   RuleID = "piece-paralyzed"
+	RulePiecePinned                      RuleID = "piece-pinned"
+	RuleMayNotPlaceTouchingOpponentPiece RuleID = "may-not-place-touching-opponents-piece"
+	RuleMayNotPlaceQueenOnFirstTurn      RuleID = "may-not-place-queen-on-first-turn"
+	RuleNotPlayersTurn                   RuleID = "not-players-turn"
+	RuleMustPlaceQueen                   RuleID = "must-place-queen"
+	RuleMustPlaceQueenToMove             RuleID = "must-place-queen-to-move"
+	RulePieceAlreadyParalyzed            RuleID = "piece-already-paralyzed"
+	RuleMovementDistanceTooGreat         RuleID = "movement-distance-too-great"
+	RuleMayNotPass                       RuleID = "may-not-pass"
+	RuleNoPillBugToThrow                 RuleID = "no-pill-bug-to-throw"
+	RuleMayNotThrowStackedPiece          RuleID = "may-not-throw-stacked-piece"
+	RuleMayNotThrowLastMovedPiece        RuleID = "may-not-throw-last-moved-piece"
+	RuleMayNotSplitHive                  RuleID = "may-not-split-hive"
+	RuleOutOfTime                        RuleID = "out-of-time"
+	RuleNoPieceAvailable                 RuleID = "no-piece-available"
+	RuleBugCannotReach                   RuleID = "bug-cannot-reach"
+	RuleBugNotEnabled                    RuleID = "bug-not-enabled"
+	RuleGameOver                         RuleID = "game-over"
 )
+
+// RuleError is returned when an action violates a rule of the game. It carries the context of the violation so that
+// a client can explain to the player why their action was rejected.
+//
+// A RuleError wraps one of the rule sentinel errors so errors.Is continues to work against the sentinels:
+//
+//	if errors.Is(err, ErrRulePiecePinned) {
+//	    var re *RuleError
+//	    errors.As(err, &re)
+//	    fmt.Println(re.Formation)
+//	}
+type RuleError struct {
+	// Rule is the stable identifier of the rule that was violated.
+	Rule RuleID
+
+	// Act is the type of action that was attempted, Placed, Moved, Thrown, or Passed. Src is only meaningful for
+	// movements and throws.
+	Act uint8
+
+	// Piece is the piece that was acted on, it's the ZeroPiece when there wasn't one.
+	Piece Piece
+	Src   Coordinate
+	Dst   Coordinate
+
+	// Conflicts are the pieces responsible for the violation, for example the opponents pieces that a placement
+	// would touch or the piece that already occupies the destination.
+	Conflicts []Piece
+
+	// Formation is the formation of the neighbors that pinned the piece, it's only set for a pinned piece.
+	Formation Formation
+
+	// Err is the sentinel error for the rule.
+	Err error
+}
+
+func (e *RuleError) Error() string {
+	switch e.Act {
+	case Passed:
+		return e.Err.Error()
+	case Placed:
+		return fmt.Sprintf("%s (rule: %s, piece: %s, dst: %s)", e.Err, e.Rule, e.Piece, e.Dst)
+	}
+	return fmt.Sprintf("%s (rule: %s, piece: %s, src: %s, dst: %s)", e.Err, e.Rule, e.Piece, e.Src, e.Dst)
+}
+func (e *RuleError) Unwrap() error { return e.Err }
+
+// RuleOf returns the identifier of the rule for a rule error, or one of the rule sentinels. NoRule is returned for any
+// other error.
+func RuleOf(err error) RuleID {
+	var re *RuleError
+	if errors.As(err, &re) {
+		return re.Rule
+	}
+	for sentinel, id := range ruleIDs {
+		if errors.Is(err, sentinel) {
+			return id
+		}
+	}
+	return NoRule
+}
+
+// Rules returns the identifiers of every rule that may be violated, in alphabetical order.
+func Rules() []RuleID {
+	rules := make([]RuleID, 0, len(ruleIDs))
+	for _, id := range ruleIDs {
+		rules = append(rules, id)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i] < rules[j] })
+	return rules
+}
+
+// newRuleError wraps the sentinel with the context of the action.
+func newRuleError(err error, act uint8, p Piece, src, dst Coordinate) *RuleError {
+	return &RuleError{
+		Rule:  ruleIDs[err],
+		Act:   act,
+		Piece: p,
+		Src:   src,
+		Dst:   dst,
+		Err:   err,
+	}
+}
+
+// withConflicts records the non-empty pieces as the conflicts of the error.
+func (e *RuleError) withConflicts(pieces ...Piece) *RuleError {
+	for _, p := range pieces {
+		if p != ZeroPiece {
+			e.Conflicts = append(e.Conflicts, p)
+		}
+	}
+	return e
+}
+
+var ruleIDs = map[error]RuleID{
+	ErrRuleFirstPieceMustBeAtOrigin:          RuleFirstPieceMustBeAtOrigin,
+	ErrRuleMayNotPlaceAPieceOnAPiece:         RuleMayNotPlaceAPieceOnAPiece,
+	ErrRuleMustPlacePieceOnSurface:           RuleMustPlacePieceOnSurface,
+	ErrRulePieceParalyzed:                    RulePieceParalyzed,
+	ErrRulePiecePinned:                       RulePiecePinned,
+	ErrRuleMayNotPlaceTouchingOpponentsPiece: RuleMayNotPlaceTouchingOpponentPiece,
+	ErrRuleMayNotPlaceQueenOnFirstTurn:       RuleMayNotPlaceQueenOnFirstTurn,
+	ErrRuleNotPlayersTurn:                    RuleNotPlayersTurn,
+	ErrRuleMustPlaceQueen:                    RuleMustPlaceQueen,
+	ErrRuleMustPlaceQueenToMove:              RuleMustPlaceQueenToMove,
+	ErrRulePieceAlreadyParalyzed:             RulePieceAlreadyParalyzed,
+	ErrRuleMovementDistanceTooGreat:          RuleMovementDistanceTooGreat,
+	ErrRuleMayNotPass:                        RuleMayNotPass,
+	ErrRuleNoPillBugToThrow:                  RuleNoPillBugToThrow,
+	ErrRuleMayNotThrowStackedPiece:           RuleMayNotThrowStackedPiece,
+	ErrRuleMayNotThrowLastMovedPiece:         RuleMayNotThrowLastMovedPiece,
+	ErrRuleMayNotSplitHive:                   RuleMayNotSplitHive,
+	ErrRuleOutOfTime:                         RuleOutOfTime,
+	ErrNoPieceAvailable:                      RuleNoPieceAvailable,
+	ErrRuleBugCannotReach:                    RuleBugCannotReach,
+	ErrRuleBugNotEnabled:                     RuleBugNotEnabled,
+	ErrGameOver:                              RuleGameOver,
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/theshadow/hive"
+)
+
+func TestRuleError(t *testing.T) {
+	t.Run("When placing a piece touching an opponents piece the error carries the conflicting pieces", func(t *testing.T) {
+		g := New(nil)
+		wq := hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA)
+		bq := hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA)
+		if err := g.Place(wq, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := g.Place(bq, hive.NewCoordinate(1, -1, 0, 0)); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+		}
+
+		wa := hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA)
+		dst := hive.NewCoordinate(1, 0, -1, 0)
+		err := g.Place(wa, dst)
+		if !errors.Is(err, ErrRuleMayNotPlaceTouchingOpponentsPiece) {
+			t.Fatalf("Expected an error of type %#v instead received %#v", ErrRuleMayNotPlaceTouchingOpponentsPiece, err)
+		}
+
+		var re *RuleError
+		if !errors.As(err, &re) {
+			t.Fatalf("Expected a *RuleError instead received %#v", err)
+		}
+		if re.Rule != RuleMayNotPlaceTouchingOpponentPiece || re.Act != hive.Placed || re.Piece != wa || re.Dst != dst {
+			t.Errorf("Unexpected context %#v", re)
+		}
+		if len(re.Conflicts) != 1 || re.Conflicts[0] != bq {
+			t.Errorf("Expected the black queen to be the conflict instead received %v", re.Conflicts)
+		}
+	})
+
+	t.Run("When moving a pinned piece the error carries the formation", func(t *testing.T) {
+		g := New(nil)
+		g.turns = 3
+		wq := hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA)
+		_ = g.board.Place(wq, hive.Origin)
+		_ = g.board.Place(hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA), hive.NewCoordinate(0, 0, 0, 1))
+		_ = g.white.Take(hive.Queen)
+
+		err := g.Move(hive.Origin, hive.NewCoordinate(0, 1, -1, 0))
+		var re *RuleError
+		if !errors.As(err, &re) || re.Rule != RulePiecePinned {
+			t.Fatalf("Expected a pinned rule error instead received %#v", err)
+		}
+		if re.Formation[hive.Above] == hive.ZeroPiece || re.Src != hive.Origin {
+			t.Errorf("Unexpected context %#v", re)
+		}
+	})
+}
+
+func TestRuleOf(t *testing.T) {
+	t.Run("When given a sentinel the rule is returned", func(t *testing.T) {
+		if id := RuleOf(ErrRulePiecePinned); id != RulePiecePinned {
+			t.Errorf("Expected %s instead received %s", RulePiecePinned, id)
+		}
+	})
+
+	t.Run("When given a wrapped rule error the rule is returned", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", newRuleError(ErrRuleMustPlaceQueen, hive.Placed, hive.ZeroPiece, 0, 0))
+		if id := RuleOf(err); id != RuleMustPlaceQueen {
+			t.Errorf("Expected %s instead received %s", RuleMustPlaceQueen, id)
+		}
+	})
+
+	t.Run("When given an error that isn't a rule violation no rule is returned", func(t *testing.T) {
+		if id := RuleOf(ErrGameNotOver); id != NoRule {
+			t.Errorf("Expected no rule instead received %s", id)
+		}
+	})
+
+	t.Run("When every rule sentinel has an identifier they are all unique", func(t *testing.T) {
+		seen := make(map[RuleID]bool)
+		for _, id := range ruleIDs {
+			if id == NoRule || seen[id] {
+				t.Errorf("The rule identifier %q is empty or used twice", id)
+			}
+			seen[id] = true
+		}
+	})
+}
@@ -0,0 +1,303 @@
+package game
+
+import (
+	"errors"
+
+	. "github.com/theshadow/hive"
+)
+
+// RuleSet is the rules a game is played by. Every action is run through the validators of the rule set before it
+// changes the game, and the victory rules decide when a game on the board is over. A game is played by the standard
+// rules, see StandardRules, unless another rule set is applied with ApplyRules.
+//
+// The validators are checked in order and the first to return an error refuses the action. A validator may return a
+// *RuleError to describe the violation, any other error is wrapped in one that carries the context of the action.
+type RuleSet interface {
+	// TurnRules decide if the player may act at all, they're checked before the rules for the action.
+	TurnRules() []TurnRule
+
+	// PlacementRules decide where a piece may be placed.
+	PlacementRules() []PlacementRule
+
+	// MovementRules decide where a piece on the board may move to.
+	MovementRules() []MovementRule
+
+	// VictoryRules decide when a game is over, the first to report a result ends the game.
+	VictoryRules() []VictoryRule
+}
+
+// TurnRule validates that the action, a placement, movement, throw, or pass, may be taken by the player whose turn it
+// is.
+type TurnRule func(g *Game, a Action) error
+
+// PlacementRule validates placing the piece at the coordinate.
+type PlacementRule func(g *Game, p Piece, c Coordinate) error
+
+// MovementRule validates moving the piece at (a) to (b).
+type MovementRule func(g *Game, p Piece, a, b Coordinate) error
+
+// VictoryRule returns the result of the game once it's decided, it returns false while the game goes on.
+type VictoryRule func(g *Game) (Result, bool)
+
+// Variant is a RuleSet made of lists of validators. A house variant usually starts from StandardRules and adds,
+// replaces, or leaves out some of the rules:
+//
+//	variant := StandardRules()
+//	variant.Placement = append(variant.Placement, noPlacementNextToQueens)
+//	err := g.ApplyRules(variant)
+type Variant struct {
+	TurnOrder []TurnRule
+	Placement []PlacementRule
+	Movement  []MovementRule
+	Victory   []VictoryRule
+}
+
+func (v Variant) TurnRules() []TurnRule           { return v.TurnOrder }
+func (v Variant) PlacementRules() []PlacementRule { return v.Placement }
+func (v Variant) MovementRules() []MovementRule   { return v.Movement }
+func (v Variant) VictoryRules() []VictoryRule     { return v.Victory }
+
+// StandardRules returns the rules of the game, each call returns new lists that may be changed freely. Rules that
+// belong to a feature, such as the tournament queen rule and paralysis, are only checked while the feature is enabled.
+func StandardRules() Variant {
+	return Variant{
+		TurnOrder: []TurnRule{PlayersTurn, PassOnlyWhenStuck},
+		Placement: []PlacementRule{
+			FirstPieceAtOrigin,
+			BugEnabled,
+			PieceInHand,
+			QueenByDeadline,
+			PlaceOnSurface,
+			TournamentQueens,
+			PlaceAwayFromOpponents,
+			PlaceOnEmptyCell,
+		},
+		Movement: []MovementRule{
+			QueenBeforeMoving,
+			NotPinned,
+			NotParalyzed,
+			OneHive,
+			BugMovement,
+			MoveToEmptyCell,
+		},
+		Victory: []VictoryRule{QueenSurrounded, ThreefoldRepetition},
+	}
+}
+
+// ApplyRules sets the rules the game is played by, it's only allowed before the first action. Nil restores the
+// standard rules.
+func (g *Game) ApplyRules(rs RuleSet) error {
+	if len(g.history) > 0 {
+		return ErrGameStarted
+	}
+	g.rules = rs
+	return nil
+}
+
+// RuleSet returns the rules the game is played by, see ApplyRules.
+func (g *Game) RuleSet() RuleSet {
+	if g.rules == nil {
+		return standardRules
+	}
+	return g.rules
+}
+
+// violation returns the error of a validator as a *RuleError, wrapping it with the context of the action when the
+// validator didn't.
+func violation(err error, a Action) *RuleError {
+	var re *RuleError
+	if errors.As(err, &re) {
+		return re
+	}
+	return newRuleError(err, a.Act(), a.Piece(), a.Src(), a.Dst())
+}
+
+// PlayersTurn refuses placing or moving a piece of the player whose turn it isn't. A throw may carry a piece of either
+// player.
+func PlayersTurn(g *Game, a Action) error {
+	if (a.WasPlaced() || a.WasMoved()) && a.Piece().Color() != g.turn {
+		return ErrRuleNotPlayersTurn
+	}
+	return nil
+}
+
+// PassOnlyWhenStuck refuses a pass while the player has something they may place, move, or throw.
+func PassOnlyWhenStuck(g *Game, a Action) error {
+	if a.WasPassed() && len(g.actions()) > 0 {
+		return ErrRuleMayNotPass
+	}
+	return nil
+}
+
+// FirstPieceAtOrigin requires the first piece of the game to be placed at the origin.
+func FirstPieceAtOrigin(g *Game, p Piece, c Coordinate) error {
+	if g.turns == FirstTurn && g.turn == WhiteColor && c != Origin && !placedAny(g.currentPlayer()) {
+		return ErrRuleFirstPieceMustBeAtOrigin
+	}
+	return nil
+}
+
+// BugEnabled refuses placing a Ladybug, Mosquito, or Pill Bug unless the feature of its expansion is enabled.
+func BugEnabled(g *Game, p Piece, c Coordinate) error {
+	if !g.bugEnabled(p.Bug()) {
+		return ErrRuleBugNotEnabled
+	}
+	return nil
+}
+
+// PieceInHand requires the player to have the piece left to place. The pieces of a bug are placed in order, the
+// second ant only once the first is on the board.
+func PieceInHand(g *Game, p Piece, c Coordinate) error {
+	if player := g.currentPlayer(); player.Remaining(p.Bug()) == 0 || int(p.Piece()) > player.Total(p.Bug()) {
+		return ErrNoPieceAvailable
+	}
+	return nil
+}
+
+// QueenByDeadline requires the player to place their queen on the fourth turn if they haven't yet, a handicap may put
+// the deadline back.
+func QueenByDeadline(g *Game, p Piece, c Coordinate) error {
+	if g.turns == g.queenTurn(g.turn) && queenInHand(g.currentPlayer()) && !p.IsQueen() {
+		return ErrRuleMustPlaceQueen
+	}
+	return nil
+}
+
+// PlaceOnSurface refuses a placement above the board that doesn't rest on a piece.
+func PlaceOnSurface(g *Game, p Piece, c Coordinate) error {
+	var h int8
+	if c.H() > 0 {
+		h--
+	}
+	cc := NewCoordinate(c.X(), c.Y(), c.Z(), h)
+	if _, existing := g.board.Cell(cc); !existing && c.H() > 0 {
+		return ErrRuleMustPlacePieceOnSurface
+	}
+	return nil
+}
+
+// TournamentQueens refuses a queen on the first turn when the TournamentQueensRuleFeature is enabled.
+func TournamentQueens(g *Game, p Piece, c Coordinate) error {
+	if g.turns == FirstTurn && g.featureEnabled(TournamentQueensRuleFeature) && p.IsQueen() {
+		return ErrRuleMayNotPlaceQueenOnFirstTurn
+	}
+	return nil
+}
+
+// PlaceAwayFromOpponents refuses a placement touching a piece of the opponent, except for the first piece of each
+// player. The extra placements of a handicap on the first turn aren't exempt.
+func PlaceAwayFromOpponents(g *Game, p Piece, c Coordinate) error {
+	if g.turns == FirstTurn && !placedAny(g.currentPlayer()) {
+		return nil
+	}
+	if neighbors := g.board.Neighbors(c); contactWithOpponentsPiece(p, neighbors) {
+		return newRuleError(ErrRuleMayNotPlaceTouchingOpponentsPiece, Placed, p, Origin, c).
+			withConflicts(opponentsPieces(p, neighbors)...)
+	}
+	return nil
+}
+
+// PlaceOnEmptyCell refuses a placement on another piece.
+func PlaceOnEmptyCell(g *Game, p Piece, c Coordinate) error {
+	if occupant, ok := g.board.Cell(c); ok {
+		return newRuleError(ErrRuleMayNotPlaceAPieceOnAPiece, Placed, p, Origin, c).withConflicts(occupant)
+	}
+	return nil
+}
+
+// QueenBeforeMoving refuses every movement until the player has placed their queen.
+func QueenBeforeMoving(g *Game, p Piece, a, b Coordinate) error {
+	if queenInHand(g.currentPlayer()) {
+		return ErrRuleMustPlaceQueenToMove
+	}
+	return nil
+}
+
+// NotPinned refuses moving a piece its neighbors have pinned in place, the formation is carried by the *RuleError. A
+// climber or a jumper, see ProfileAt, gets out over its neighbors unless a piece is on top of it.
+func NotPinned(g *Game, p Piece, a, b Coordinate) error {
+	neighbors := g.board.Neighbors(a)
+	if profile := ProfileAt(g.board, a); neighbors[Above] == ZeroPiece && (profile.IsClimber() || profile.IsJumper()) {
+		return nil
+	}
+	if Formation(neighbors).IsPinned() {
+		re := newRuleError(ErrRulePiecePinned, Moved, p, a, b).withConflicts(neighbors[:]...)
+		re.Formation = neighbors
+		return re
+	}
+	return nil
+}
+
+// NotParalyzed refuses moving a piece a Pill Bug paralyzed when the PillBugPieceFeature is enabled.
+func NotParalyzed(g *Game, p Piece, a, b Coordinate) error {
+	if g.featureEnabled(PillBugPieceFeature) && g.pieceIsParalyzed(a) {
+		return ErrRulePieceParalyzed
+	}
+	return nil
+}
+
+// OneHive refuses lifting a piece that leaves the hive in two parts, not even while it's in transit.
+func OneHive(g *Game, p Piece, a, b Coordinate) error {
+	if g.splitsHive(a) {
+		return ErrRuleMayNotSplitHive
+	}
+	return nil
+}
+
+// BugMovement requires (b) to be one of the destinations of the Movement of the bug, see BugRegistry. A destination
+// beyond the Reach of the bug is refused as too far without asking the Movement.
+func BugMovement(g *Game, p Piece, a, b Coordinate) error {
+	def, ok := DefaultBugs.Lookup(p.Bug())
+	if !ok {
+		return ErrRuleBugCannotReach
+	}
+	if def.Reach > 0 && Distance(a, b) > def.Reach {
+		return ErrRuleMovementDistanceTooGreat
+	}
+	for _, c := range def.Movement.Destinations(g.board, a) {
+		if c == b {
+			return nil
+		}
+	}
+	return ErrRuleBugCannotReach
+}
+
+// MoveToEmptyCell refuses moving on to another piece. The board would refuse the move anyway, the rule reports it
+// without touching the board.
+func MoveToEmptyCell(g *Game, p Piece, a, b Coordinate) error {
+	if occupant, ok := g.board.Cell(b); ok {
+		return newRuleError(ErrRuleMayNotPlaceAPieceOnAPiece, Moved, p, a, b).withConflicts(occupant)
+	}
+	return nil
+}
+
+// QueenSurrounded ends the game once a queen is surrounded, the opponent of its player wins. When both queens are
+// surrounded by the same action the game is a tie.
+func QueenSurrounded(g *Game) (Result, bool) {
+	white, black := g.suffocating(WhiteColor), g.suffocating(BlackColor)
+	switch {
+	case white && black:
+		return Result{Winner: Tie, Reason: Suffocation}, true
+	case white:
+		return Result{Winner: BlackPlayer, Reason: Suffocation}, true
+	case black:
+		return Result{Winner: WhitePlayer, Reason: Suffocation}, true
+	}
+	return Result{}, false
+}
+
+// ThreefoldRepetition draws the game once the same position comes up for the third time.
+func ThreefoldRepetition(g *Game) (Result, bool) {
+	if g.repeated {
+		return Result{Winner: Tie, Reason: Repetition}, true
+	}
+	return Result{}, false
+}
+
+// standardRules is the rule set of a game no other rules were applied to. It's set by init as the standard rules
+// refer back to the game.
+var standardRules RuleSet
+
+func init() {
+	standardRules = StandardRules()
+}
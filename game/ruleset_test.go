@@ -0,0 +1,149 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/theshadow/hive"
+)
+
+func TestGame_ApplyRules(t *testing.T) {
+	whiteSpider := hive.NewPiece(hive.WhiteColor, hive.Spider, hive.PieceA)
+	blackSpider := hive.NewPiece(hive.BlackColor, hive.Spider, hive.PieceA)
+	whiteAnt := hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA)
+	beside := hive.NewCoordinate(0, 1, -1, 0)
+	touchingBlack := hive.NewCoordinate(0, 2, -2, 0)
+
+	// opening places both spiders so that the next white placement may touch the black spider
+	opening := func(t *testing.T, g *Game) {
+		if err := g.Place(whiteSpider, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing", err)
+		}
+		if err := g.Place(blackSpider, beside); err != nil {
+			t.Fatalf("Unexpected error %#v while black was placing", err)
+		}
+	}
+
+	t.Run("When no rules are applied the game is played by the standard rules", func(t *testing.T) {
+		g := New(nil)
+		opening(t, g)
+		if err := g.Place(whiteAnt, touchingBlack); !errors.Is(err, ErrRuleMayNotPlaceTouchingOpponentsPiece) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrRuleMayNotPlaceTouchingOpponentsPiece, err)
+		}
+	})
+
+	t.Run("When a rule is left out the game no longer checks it", func(t *testing.T) {
+		variant := StandardRules()
+		variant.Placement = []PlacementRule{FirstPieceAtOrigin, PieceInHand, QueenByDeadline, PlaceOnEmptyCell}
+		g := New(nil)
+		if err := g.ApplyRules(variant); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the rules", err)
+		}
+		opening(t, g)
+		if err := g.Place(whiteAnt, touchingBlack); err != nil {
+			t.Errorf("Expected the placement to be allowed instead received %#v", err)
+		}
+	})
+
+	t.Run("When a rule is added its violation is returned as a rule error", func(t *testing.T) {
+		errNoAnts := fmt.Errorf("ants may not be placed")
+		variant := StandardRules()
+		variant.Placement = append(variant.Placement, func(g *Game, p hive.Piece, c hive.Coordinate) error {
+			if p.IsAnt() {
+				return errNoAnts
+			}
+			return nil
+		})
+		g := New(nil)
+		if err := g.ApplyRules(variant); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the rules", err)
+		}
+
+		err := g.Place(whiteAnt, hive.Origin)
+		var re *RuleError
+		if !errors.Is(err, errNoAnts) || !errors.As(err, &re) {
+			t.Fatalf("Expected a rule error wrapping %#v instead received %#v", errNoAnts, err)
+		}
+		if re.Act != hive.Placed || re.Piece != whiteAnt || re.Dst != hive.Origin || re.Rule != NoRule {
+			t.Errorf("Expected the context of the placement instead received %#v", re)
+		}
+		for _, a := range g.LegalActions() {
+			if a.Piece().IsAnt() {
+				t.Errorf("Expected the legal actions to follow the rules instead received %s", a)
+			}
+		}
+	})
+
+	t.Run("When a victory rule reports a result the game is over", func(t *testing.T) {
+		variant := StandardRules()
+		variant.Victory = append(variant.Victory, func(g *Game) (Result, bool) {
+			if len(g.History()) >= 2 {
+				return Result{Winner: BlackPlayer, Reason: Adjudication}, true
+			}
+			return Result{}, false
+		})
+		g := New(nil)
+		if err := g.ApplyRules(variant); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the rules", err)
+		}
+		opening(t, g)
+
+		r, err := g.Result()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while reading the result", err)
+		}
+		if r.Winner != BlackPlayer || r.Reason != Adjudication {
+			t.Errorf("Expected black to win instead received %s", r)
+		}
+		if err := g.Place(whiteAnt, touchingBlack); !errors.Is(err, ErrGameOver) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrGameOver, err)
+		}
+	})
+
+	t.Run("When the pass rule is left out a player may pass at any time", func(t *testing.T) {
+		variant := StandardRules()
+		variant.TurnOrder = []TurnRule{PlayersTurn}
+		g := New(nil)
+		if err := g.ApplyRules(variant); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the rules", err)
+		}
+		if err := g.Pass(); err != nil {
+			t.Errorf("Expected the pass to be allowed instead received %#v", err)
+		}
+		if g.Turn() != hive.BlackColor {
+			t.Errorf("Expected the turn to pass to black instead received %d", g.Turn())
+		}
+	})
+
+	t.Run("When the game has started the rules may not be changed", func(t *testing.T) {
+		g := New(nil)
+		opening(t, g)
+		if err := g.ApplyRules(StandardRules()); !errors.Is(err, ErrGameStarted) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrGameStarted, err)
+		}
+	})
+
+	t.Run("When a game is replayed it keeps its rules", func(t *testing.T) {
+		variant := StandardRules()
+		variant.Placement = []PlacementRule{FirstPieceAtOrigin, PieceInHand, QueenByDeadline, PlaceOnEmptyCell}
+		g := New(nil)
+		if err := g.ApplyRules(variant); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the rules", err)
+		}
+		opening(t, g)
+		if err := g.Place(whiteAnt, touchingBlack); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing", err)
+		}
+
+		if _, err := g.Replay(); err != nil {
+			t.Errorf("Expected the history to replay instead received %#v", err)
+		}
+		initial := g.Initial()
+		for _, a := range g.History() {
+			if err := initial.Play(a); err != nil {
+				t.Errorf("Expected the initial game to keep the rules instead received %#v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,243 @@
+package game
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/theshadow/hive"
+)
+
+// Session wraps a Game so that it may be shared between goroutines. A server will typically have the players, the
+// spectators, and the timers all touching the same game, the Session serializes their access.
+//
+// The Session offers the same action and query interface as the Game along with two additions. Snapshot returns an
+// independent copy of the game for any read that needs more than a single call to be consistent, and Subscribe
+// returns a channel of the changes made to the game.
+type Session struct {
+	mu   sync.Mutex
+	game *Game
+
+	subscribers map[chan Update]struct{}
+}
+
+// Update describes a single change to the state of a session's game.
+type Update struct {
+	// Sequence is the number of actions performed in the game after this update, the first action is 1.
+	Sequence int
+
+	// Action is the action that caused the update.
+	Action Action
+
+	// Turn is the color of the player whose turn it is after the action.
+	Turn uint8
+
+	// Over is true when the action ended the game.
+	Over bool
+
+	// Result is how the game ended when the action ended it, nil otherwise.
+	Result *Result
+
+	// Clock is a reading of the clocks right after the action, nil when the game isn't timed.
+	Clock *ClockState
+}
+
+// NewSession creates a session around the game. The game should no longer be used directly once it has been handed
+// to the session.
+func NewSession(g *Game) *Session {
+	return &Session{
+		game:        g,
+		subscribers: make(map[chan Update]struct{}),
+	}
+}
+
+// Place see Game.Place
+func (s *Session) Place(p Piece, c Coordinate) error {
+	return s.act(func(g *Game) error { return g.Place(p, c) })
+}
+
+// Move see Game.Move
+func (s *Session) Move(a, b Coordinate) error {
+	return s.act(func(g *Game) error { return g.Move(a, b) })
+}
+
+// Throw see Game.Throw
+func (s *Session) Throw(a, b Coordinate) error {
+	return s.act(func(g *Game) error { return g.Throw(a, b) })
+}
+
+// Pass see Game.Pass
+func (s *Session) Pass() error {
+	return s.act(func(g *Game) error { return g.Pass() })
+}
+
+// Play see Game.Play
+func (s *Session) Play(a Action) error {
+	return s.act(func(g *Game) error { return g.Play(a) })
+}
+
+// Resign see Game.Resign
+func (s *Session) Resign(color uint8) error {
+	return s.act(func(g *Game) error { return g.Resign(color) })
+}
+
+// AgreeDraw see Game.AgreeDraw
+func (s *Session) AgreeDraw() error {
+	return s.act(func(g *Game) error { return g.AgreeDraw() })
+}
+
+// Adjudicate see Game.Adjudicate
+func (s *Session) Adjudicate(w Winner) error {
+	return s.act(func(g *Game) error { return g.Adjudicate(w) })
+}
+
+// ClaimTimeout see Game.ClaimTimeout
+func (s *Session) ClaimTimeout() error {
+	return s.act(func(g *Game) error { return g.ClaimTimeout() })
+}
+
+// Result see Game.Result
+func (s *Session) Result() (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Result()
+}
+
+// Clock see Game.Clock
+func (s *Session) Clock() (ClockState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Clock()
+}
+
+// PlayedAt see Game.PlayedAt
+func (s *Session) PlayedAt(i int) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.PlayedAt(i)
+}
+
+// Winner see Game.Winner
+func (s *Session) Winner() (Winner, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Winner()
+}
+
+// Over see Game.Over
+func (s *Session) Over() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Over()
+}
+
+// Turn see Game.Turn
+func (s *Session) Turn() uint8 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Turn()
+}
+
+// History see Game.History
+func (s *Session) History() []Action {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.History()
+}
+
+// LegalActions see Game.LegalActions
+func (s *Session) LegalActions() []Action {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.LegalActions()
+}
+
+// Explain see Game.Explain
+func (s *Session) Explain(src, dst Coordinate) Explanation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Explain(src, dst)
+}
+
+// Snapshot returns an independent copy of the game as it is right now. The copy may be read, or even acted on,
+// without affecting the session.
+func (s *Session) Snapshot() *Game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Clone()
+}
+
+// Subscribe returns a channel that receives an Update after every successful action along with a function that
+// cancels the subscription.
+//
+// Updates are delivered in order. A subscriber that lets its buffer fill up is considered gone, its channel is closed
+// and it will need to subscribe again and use a Snapshot to catch up. This keeps a slow subscriber from stalling the
+// game for everybody else.
+func (s *Session) Subscribe(buffer int) (<-chan Update, func()) {
+	ch := make(chan Update, buffer)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.unsubscribe(ch)
+	}
+}
+
+// Close cancels all of the subscriptions.
+func (s *Session) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		s.unsubscribe(ch)
+	}
+}
+
+// act performs the action against the game while holding the lock and notifies the subscribers of every action it
+// added to the history. That's usually one action on success and none on failure, but an action refused because the
+// player ran out of time still records the timeout.
+func (s *Session) act(fn func(g *Game) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := len(s.game.history)
+	err := fn(s.game)
+	if len(s.game.history) > before {
+		s.publish()
+	}
+	return err
+}
+
+// publish sends an update for the last action in the history to the subscribers, it expects the lock to be held.
+func (s *Session) publish() {
+	u := Update{
+		Sequence: len(s.game.history),
+		Action:   s.game.history[len(s.game.history)-1],
+		Turn:     s.game.turn,
+		Over:     s.game.Over(),
+	}
+	if r, err := s.game.Result(); err == nil {
+		u.Result = &r
+	}
+	if cs, ok := s.game.Clock(); ok {
+		u.Clock = &cs
+	}
+	for ch := range s.subscribers {
+		select {
+		case ch <- u:
+		default:
+			s.unsubscribe(ch)
+		}
+	}
+}
+
+// unsubscribe expects the lock to be held.
+func (s *Session) unsubscribe(ch chan Update) {
+	if _, ok := s.subscribers[ch]; !ok {
+		return
+	}
+	delete(s.subscribers, ch)
+	close(ch)
+}
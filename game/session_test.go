@@ -0,0 +1,136 @@
+package game
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/theshadow/hive"
+)
+
+func TestSession_Subscribe(t *testing.T) {
+	t.Run("When an action succeeds subscribers receive an update", func(t *testing.T) {
+		s := NewSession(New(nil))
+		updates, cancel := s.Subscribe(4)
+		defer cancel()
+
+		p := hive.NewPiece(hive.WhiteColor, hive.Queen, hive.PieceA)
+		if err := s.Place(p, hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+
+		u := <-updates
+		if u.Sequence != 1 || u.Action.Piece() != p || u.Turn != hive.BlackColor || u.Over {
+			t.Errorf("Unexpected update %#v", u)
+		}
+	})
+
+	t.Run("When an action fails subscribers do not receive an update", func(t *testing.T) {
+		s := NewSession(New(nil))
+		updates, cancel := s.Subscribe(4)
+		defer cancel()
+
+		if err := s.Place(hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.Origin); err == nil {
+			t.Fatal("Expected an error when black placed a piece on whites turn")
+		}
+
+		select {
+		case u := <-updates:
+			t.Errorf("Unexpected update %#v", u)
+		default:
+		}
+	})
+
+	t.Run("When a subscriber falls behind its channel is closed", func(t *testing.T) {
+		s := NewSession(New(nil))
+		updates, cancel := s.Subscribe(1)
+		defer cancel()
+
+		for i := 0; i < 2; i++ {
+			if err := s.Play(s.LegalActions()[0]); err != nil {
+				t.Fatalf("Unexpected error %#v", err)
+			}
+		}
+
+		<-updates
+		if _, ok := <-updates; ok {
+			t.Error("Expected the channel of the slow subscriber to be closed")
+		}
+	})
+}
+
+func TestSession_Concurrency(t *testing.T) {
+	const plies = 40
+
+	s := NewSession(New(nil))
+	updates, cancel := s.Subscribe(plies)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	// Each player only acts on their own turn. They race each other, and the spectators, for the lock.
+	for _, color := range []uint8{hive.WhiteColor, hive.BlackColor} {
+		wg.Add(1)
+		go func(color uint8) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				// only the player whose turn it is may change the state, so the snapshot stays accurate until we act.
+				snapshot := s.Snapshot()
+				if len(snapshot.History()) >= plies {
+					return
+				}
+				if snapshot.Turn() != color {
+					continue
+				}
+				actions := snapshot.LegalActions()
+				if len(actions) == 0 {
+					return
+				}
+				_ = s.Play(actions[len(actions)-1])
+			}
+		}(color)
+	}
+
+	// Spectators continuously read the state
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				snapshot := s.Snapshot()
+				if len(snapshot.History()) > plies {
+					t.Error("Snapshot has more actions than were played")
+				}
+				_, _ = s.Winner()
+				_ = s.History()
+			}
+		}()
+	}
+
+	expected := 1
+	for expected <= plies {
+		u, ok := <-updates
+		if !ok {
+			break
+		}
+		if u.Sequence != expected {
+			t.Errorf("Expected update %d instead received %d", expected, u.Sequence)
+		}
+		expected++
+		if u.Over {
+			break
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,143 @@
+package hive
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Inventory is a set of pieces a player starts a game with, the number of each bug. The standard set is
+// StandardInventory, any other is a house rule, a handicap, or a teaching setup such as a queen and two ants. No bug may
+// have more pieces than the standard set has, three ants and grasshoppers, two beetles and spiders, and one of every
+// other bug, see MaxPieces. Bugs registered by an application are limited by their definition, see BugRegistry.
+//
+// The zero value is an empty inventory.
+type Inventory struct {
+	counts [MaxBug + 1]uint8
+}
+
+// Stock is what a player has left of their inventory during a game. *Player is the compact stock of the standard set,
+// any other inventory is tracked by the stock returned from Inventory.Stock.
+type Stock interface {
+	// Remaining returns the number of pieces of the bug that haven't been taken yet.
+	Remaining(bug uint8) int
+
+	// Total returns the number of pieces of the bug the stock started with.
+	Total(bug uint8) int
+
+	// Take takes a piece of the bug, it returns ErrNoPieceAvailable when none are left.
+	Take(bug uint8) error
+
+	// Copy returns an independent copy of the stock.
+	Copy() Stock
+}
+
+// NewInventory returns the inventory with the number of pieces of each bug, bugs that aren't keys have no pieces.
+func NewInventory(counts map[uint8]int) (Inventory, error) {
+	var inv Inventory
+	for bug, n := range counts {
+		var err error
+		if inv, err = inv.With(bug, n); err != nil {
+			return Inventory{}, err
+		}
+	}
+	return inv, nil
+}
+
+// With returns a copy of the inventory with the number of pieces of the bug changed to n.
+func (inv Inventory) With(bug uint8, n int) (Inventory, error) {
+	def, ok := DefaultBugs.Lookup(bug)
+	if !ok {
+		return inv, fmt.Errorf("%w: the bug %d is unknown", ErrInvalidInventory, bug)
+	}
+	if n < 0 || n > def.Pieces {
+		return inv, fmt.Errorf("%w: %d pieces of %s, the most is %d", ErrInvalidInventory, n, def.Label, def.Pieces)
+	}
+	inv.counts[bug] = uint8(n)
+	return inv, nil
+}
+
+// Count returns the number of pieces of the bug.
+func (inv Inventory) Count(bug uint8) int {
+	if bug > MaxBug {
+		return 0
+	}
+	return int(inv.counts[bug])
+}
+
+// Size returns the number of pieces of every bug.
+func (inv Inventory) Size() int {
+	size := 0
+	for _, n := range inv.counts {
+		size += int(n)
+	}
+	return size
+}
+
+// Stock returns a full stock of the inventory. The standard set is stocked by a *Player.
+func (inv Inventory) Stock() Stock {
+	if inv == StandardInventory {
+		return NewPlayer()
+	}
+	return &inventoryStock{total: inv, remaining: inv}
+}
+
+// String lists the number of each bug, "Queen: 1, Ant: 2" for example.
+func (inv Inventory) String() string {
+	var parts []string
+	for _, bug := range DefaultBugs.Bugs() {
+		if n := inv.counts[bug]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", bugLabel(bug), n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MaxPieces returns the most pieces of the bug an inventory may have, the Pieces of its definition. A bug that isn't
+// registered may have none.
+func MaxPieces(bug uint8) int {
+	if def, ok := DefaultBugs.Lookup(bug); ok {
+		return def.Pieces
+	}
+	return 0
+}
+
+// inventoryStock is the stock of an inventory other than the standard set.
+type inventoryStock struct {
+	total, remaining Inventory
+}
+
+func (s *inventoryStock) Remaining(bug uint8) int {
+	return s.remaining.Count(bug)
+}
+
+func (s *inventoryStock) Total(bug uint8) int {
+	return s.total.Count(bug)
+}
+
+func (s *inventoryStock) Take(bug uint8) error {
+	if s.remaining.Count(bug) == 0 {
+		return ErrNoPieceAvailable
+	}
+	s.remaining.counts[bug]--
+	return nil
+}
+
+func (s *inventoryStock) Copy() Stock {
+	c := *s
+	return &c
+}
+
+// StandardInventory is the set of the game, a queen, three ants, three grasshoppers, two beetles, two spiders, and one
+// of each expansion bug. Expansion bugs are only played when their feature is enabled.
+var StandardInventory = Inventory{counts: [MaxBug + 1]uint8{
+	Queen:       1,
+	Beetle:      2,
+	Grasshopper: 3,
+	Spider:      2,
+	Ant:         3,
+	Mosquito:    1,
+	Ladybug:     1,
+	PillBug:     1,
+}}
+
+var ErrInvalidInventory = fmt.Errorf("the inventory is invalid")
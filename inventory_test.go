@@ -0,0 +1,60 @@
+package hive
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInventory_With(t *testing.T) {
+	t.Run("When a bug has more pieces than the set allows an error is returned", func(t *testing.T) {
+		for bug, n := range map[uint8]int{Queen: 2, Ant: 4, Beetle: 3, Spider: 3, PillBug: 2, NoBug: 1} {
+			if _, err := (Inventory{}).With(bug, n); !errors.Is(err, ErrInvalidInventory) {
+				t.Errorf("Expected an error of type %#v for %d of bug %d instead received %#v", ErrInvalidInventory, n,
+					bug, err)
+			}
+		}
+	})
+
+	t.Run("When an inventory is made its counts are those given", func(t *testing.T) {
+		inv, err := NewInventory(map[uint8]int{Queen: 1, Ant: 2})
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while making the inventory", err)
+		}
+		if inv.Count(Queen) != 1 || inv.Count(Ant) != 2 || inv.Count(Beetle) != 0 || inv.Size() != 3 {
+			t.Errorf("Expected a queen and two ants instead received %s", inv)
+		}
+	})
+}
+
+func TestInventory_Stock(t *testing.T) {
+	t.Run("When the standard set is stocked it's stocked by a player", func(t *testing.T) {
+		if _, ok := StandardInventory.Stock().(*Player); !ok {
+			t.Error("Expected the standard set to be stocked by a *Player")
+		}
+		p := NewPlayer()
+		for bug := Queen; bug <= PillBug; bug++ {
+			if p.Remaining(bug) != StandardInventory.Count(bug) || p.Total(bug) != StandardInventory.Count(bug) {
+				t.Errorf("Expected a new player to have %d of bug %d instead received %d", StandardInventory.Count(bug),
+					bug, p.Remaining(bug))
+			}
+		}
+	})
+
+	t.Run("When pieces are taken from a stock its copies keep theirs", func(t *testing.T) {
+		inv, _ := NewInventory(map[uint8]int{Ant: 2})
+		s := inv.Stock()
+		c := s.Copy()
+		if err := s.Take(Ant); err != nil {
+			t.Fatalf("Unexpected error %#v while taking an ant", err)
+		}
+		if err := s.Take(Ant); err != nil {
+			t.Fatalf("Unexpected error %#v while taking an ant", err)
+		}
+		if err := s.Take(Ant); !errors.Is(err, ErrNoPieceAvailable) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrNoPieceAvailable, err)
+		}
+		if c.Remaining(Ant) != 2 || s.Total(Ant) != 2 {
+			t.Errorf("Expected the copy to keep %d ants instead received %d", 2, c.Remaining(Ant))
+		}
+	})
+}
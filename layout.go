@@ -0,0 +1,145 @@
+package hive
+
+import "math"
+
+// Axial is the coordinate of a cell as two axes, q and r. It's the cube coordinate with the redundant axis left out,
+// q is X and r is Z, so a step North is a step of r down by one. The height isn't part of it.
+type Axial struct {
+	Q, R int
+}
+
+// Axial returns the axial coordinate of the cell.
+func (c Coordinate) Axial() Axial {
+	return Axial{Q: int(c.X()), R: int(c.Z())}
+}
+
+// Coordinate returns the coordinate of the cell on the ground. The axes must fit the board, see Coordinate.
+func (a Axial) Coordinate() Coordinate {
+	return NewCoordinate(int8(a.Q), int8(-a.Q-a.R), int8(a.R), 0)
+}
+
+// Parity is which columns of an offset coordinate are shoved down half a cell, the odd or the even ones.
+type Parity uint8
+
+const (
+	OddQ Parity = iota
+	EvenQ
+)
+
+// Offset is the coordinate of a cell as a column and a row of a flat-top grid, the way a grid of hexagons is stored in
+// a rectangular array. Every other column is shoved down half a cell, which ones is the Parity.
+type Offset struct {
+	Col, Row int
+}
+
+// Offset returns the offset coordinate of the cell in the grid of the parity.
+func (c Coordinate) Offset(parity Parity) Offset {
+	q, r := int(c.X()), int(c.Z())
+	return Offset{Col: q, Row: r + (q+shove(q, parity))/2}
+}
+
+// Coordinate returns the coordinate of the cell on the ground in the grid of the parity.
+func (o Offset) Coordinate(parity Parity) Coordinate {
+	return Axial{Q: o.Col, R: o.Row - (o.Col+shove(o.Col, parity))/2}.Coordinate()
+}
+
+// shove returns what takes the column to the even number its row is counted from, down for odd-q and up for even-q.
+func shove(col int, parity Parity) int {
+	if parity == OddQ {
+		return -(col & 1)
+	}
+	return col & 1
+}
+
+// Doubled is the coordinate of a cell as a column and a row of a flat-top grid where the rows are counted in half
+// cells, a cell's row always has the parity of its column. It's handy for drawing on a grid of text.
+type Doubled struct {
+	Col, Row int
+}
+
+// Doubled returns the doubled coordinate of the cell.
+func (c Coordinate) Doubled() Doubled {
+	q, r := int(c.X()), int(c.Z())
+	return Doubled{Col: q, Row: 2*r + q}
+}
+
+// Coordinate returns the coordinate of the cell on the ground, the row and the column must have the same parity.
+func (d Doubled) Coordinate() Coordinate {
+	return Axial{Q: d.Col, R: (d.Row - d.Col) / 2}.Coordinate()
+}
+
+// Point is a position in pixel space, y grows down the screen.
+type Point struct {
+	X, Y float64
+}
+
+// Orientation is which way the hexagons of a Layout are turned. The board is flat-top, its North is straight up, a
+// pointy-top layout turns it a twelfth of a turn counter-clockwise as it's drawn, with y pointing down.
+type Orientation uint8
+
+const (
+	FlatTop Orientation = iota
+	PointyTop
+)
+
+// Layout places the cells of the board in pixel space. Size is the distance from the center of a hexagon to its
+// corners along each axis, they're the same for regular hexagons, and Origin is the center of the origin cell.
+type Layout struct {
+	Orientation Orientation
+	Size        Point
+	Origin      Point
+}
+
+// Center returns the center of the cell, the height of a stack isn't drawn.
+func (l Layout) Center(c Coordinate) Point {
+	q, r := float64(c.X()), float64(c.Z())
+	var x, y float64
+	if l.Orientation == PointyTop {
+		x, y = math.Sqrt(3)*q+math.Sqrt(3)/2*r, 3.0/2*r
+	} else {
+		x, y = 3.0/2*q, math.Sqrt(3)/2*q+math.Sqrt(3)*r
+	}
+	return Point{X: l.Origin.X + x*l.Size.X, Y: l.Origin.Y + y*l.Size.Y}
+}
+
+// Corners returns the corners of the hexagon of the cell clockwise, starting from the one to the right of the center
+// for a flat-top layout and the one to the lower right for a pointy-top layout.
+func (l Layout) Corners(c Coordinate) [6]Point {
+	center := l.Center(c)
+	start := 0.0
+	if l.Orientation == PointyTop {
+		start = 0.5
+	}
+	var corners [6]Point
+	for i := range corners {
+		angle := 2 * math.Pi * (start + float64(i)) / 6
+		corners[i] = Point{X: center.X + l.Size.X*math.Cos(angle), Y: center.Y + l.Size.Y*math.Sin(angle)}
+	}
+	return corners
+}
+
+// Coordinate returns the ground coordinate of the cell the point falls in.
+func (l Layout) Coordinate(p Point) Coordinate {
+	x, y := (p.X-l.Origin.X)/l.Size.X, (p.Y-l.Origin.Y)/l.Size.Y
+	var q, r float64
+	if l.Orientation == PointyTop {
+		q, r = math.Sqrt(3)/3*x-1.0/3*y, 2.0/3*y
+	} else {
+		q, r = 2.0/3*x, -1.0/3*x+math.Sqrt(3)/3*y
+	}
+	return roundCube(q, r, -q-r)
+}
+
+// roundCube returns the cell nearest to the fractional cube coordinate. Each axis is rounded and the one that moved the
+// furthest is put back so that the axes still sum to zero.
+func roundCube(q, r, s float64) Coordinate {
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+	switch {
+	case dq > dr && dq > ds:
+		rq = -rr - rs
+	case dr > ds:
+		rr = -rq - rs
+	}
+	return Axial{Q: int(rq), R: int(rr)}.Coordinate()
+}
@@ -0,0 +1,124 @@
+package hive
+
+import (
+	"math"
+	"testing"
+)
+
+// layoutCells returns every cell on the ground within a few steps of the origin.
+func layoutCells() []Coordinate {
+	var cells []Coordinate
+	for q := -4; q <= 4; q++ {
+		for r := -4; r <= 4; r++ {
+			cells = append(cells, Axial{Q: q, R: r}.Coordinate())
+		}
+	}
+	return cells
+}
+
+func TestCoordinate_Axial(t *testing.T) {
+	t.Run("When a coordinate is converted to axial and back it's unchanged", func(t *testing.T) {
+		for _, c := range layoutCells() {
+			if actual := c.Axial().Coordinate(); actual != c {
+				t.Errorf("Expected %s instead received %s", c, actual)
+			}
+		}
+	})
+
+	t.Run("When a cell is North of another its row is one less", func(t *testing.T) {
+		if a := NeighborsMatrix[North].Axial(); a != (Axial{Q: 0, R: -1}) {
+			t.Errorf("Expected the axial coordinate %v instead received %v", Axial{Q: 0, R: -1}, a)
+		}
+	})
+}
+
+func TestCoordinate_Offset(t *testing.T) {
+	t.Run("When a coordinate is converted to offset and back it's unchanged", func(t *testing.T) {
+		for _, parity := range []Parity{OddQ, EvenQ} {
+			for _, c := range layoutCells() {
+				if actual := c.Offset(parity).Coordinate(parity); actual != c {
+					t.Errorf("Expected %s instead received %s with the parity %d", c, actual, parity)
+				}
+			}
+		}
+	})
+
+	t.Run("When a column is shoved down its cells are a row further down", func(t *testing.T) {
+		southeast := NeighborsMatrix[Southeast]
+		if o := southeast.Offset(OddQ); o != (Offset{Col: 1, Row: 0}) {
+			t.Errorf("Expected the odd-q offset %v instead received %v", Offset{Col: 1, Row: 0}, o)
+		}
+		if o := southeast.Offset(EvenQ); o != (Offset{Col: 1, Row: 1}) {
+			t.Errorf("Expected the even-q offset %v instead received %v", Offset{Col: 1, Row: 1}, o)
+		}
+	})
+}
+
+func TestCoordinate_Doubled(t *testing.T) {
+	t.Run("When a coordinate is converted to doubled and back it's unchanged", func(t *testing.T) {
+		for _, c := range layoutCells() {
+			d := c.Doubled()
+			if (d.Row-d.Col)%2 != 0 {
+				t.Errorf("Expected the row and column of %v to have the same parity", d)
+			}
+			if actual := d.Coordinate(); actual != c {
+				t.Errorf("Expected %s instead received %s", c, actual)
+			}
+		}
+	})
+}
+
+func TestLayout(t *testing.T) {
+	layouts := map[string]Layout{
+		"flat-top":   {Orientation: FlatTop, Size: Point{X: 10, Y: 10}, Origin: Point{X: 100, Y: 50}},
+		"pointy-top": {Orientation: PointyTop, Size: Point{X: 12, Y: 8}, Origin: Point{X: -3, Y: 7}},
+	}
+
+	t.Run("When the center of a cell is converted back it's the same cell", func(t *testing.T) {
+		for name, l := range layouts {
+			for _, c := range layoutCells() {
+				if actual := l.Coordinate(l.Center(c)); actual != c {
+					t.Errorf("Expected %s instead received %s in the %s layout", c, actual, name)
+				}
+			}
+		}
+	})
+
+	t.Run("When a point is just inside a corner it rounds to the cell of the hexagon", func(t *testing.T) {
+		for name, l := range layouts {
+			c := NewCoordinate(1, -2, 1, 0)
+			center := l.Center(c)
+			for _, corner := range l.Corners(c) {
+				// a tenth of the way from the corner back to the center
+				p := Point{X: corner.X + (center.X-corner.X)/10, Y: corner.Y + (center.Y-corner.Y)/10}
+				if actual := l.Coordinate(p); actual != c {
+					t.Errorf("Expected %s instead received %s near a corner in the %s layout", c, actual, name)
+				}
+			}
+		}
+	})
+
+	t.Run("When the board is flat-top North is straight up", func(t *testing.T) {
+		l := layouts["flat-top"]
+		origin, north := l.Center(Origin), l.Center(NeighborsMatrix[North])
+		if origin.X != north.X || north.Y >= origin.Y {
+			t.Errorf("Expected %v to be straight above %v", north, origin)
+		}
+		if d := math.Hypot(north.X-origin.X, north.Y-origin.Y); math.Abs(d-10*math.Sqrt(3)) > 1e-9 {
+			t.Errorf("Expected neighbors to be %f apart instead received %f", 10*math.Sqrt(3), d)
+		}
+	})
+
+	t.Run("When the corners of a hexagon are drawn they're all a size away from the center", func(t *testing.T) {
+		l := layouts["flat-top"]
+		center := l.Center(Origin)
+		for i, corner := range l.Corners(Origin) {
+			if d := math.Hypot(corner.X-center.X, corner.Y-center.Y); math.Abs(d-10) > 1e-9 {
+				t.Errorf("Expected corner %d to be %d from the center instead received %f", i, 10, d)
+			}
+		}
+		if first := l.Corners(Origin)[0]; first.X != center.X+10 || first.Y != center.Y {
+			t.Errorf("Expected the first corner to the right of the center instead received %v", first)
+		}
+	})
+}
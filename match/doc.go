@@ -0,0 +1,27 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package match plays matches between two players to measure how much stronger one is than the other, the first
+player is usually a new version of an engine and the second the version it's meant to replace.
+
+Players
+
+A Player is an agent of the agent package with a name to identify it in the game records, Named gives an agent a
+name.
+
+Matches
+
+Run plays the games of a match one after the other. The players alternate colors and each opening of the suite is
+played twice, once with each player as white, so that neither player benefits from a lopsided opening. The games are
+driven by an agent.Referee, a player that fails to return a legal action forfeits the game and a game that drags on
+past the turn limit is drawn. Both are adjudicated and the record of the game explains why with a Termination tag.
+
+Statistics
+
+A Summary counts the wins, losses, and draws of the first player and estimates the Elo difference between the
+players along with the margin of its 95% confidence interval. An SPRT, a sequential probability ratio test, stops a
+match as soon as the results are convincing, which saves playing hundreds of games to confirm an obvious
+improvement.
+*/
+package match
@@ -0,0 +1,230 @@
+package match
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/theshadow/hive/agent"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/record"
+	"github.com/theshadow/hive/uhp"
+)
+
+// Config describes a match.
+type Config struct {
+	// Games is the number of games to play, the match may stop earlier when an SPRT is set.
+	Games int
+
+	// Features are enabled for every game.
+	Features []game.Feature
+
+	// Openings are played before the players take over. Each opening is played twice in a row, once with each player
+	// as white, and the openings are used in turn. Without openings every game starts from an empty board.
+	Openings []Opening
+
+	// Control is the time control of the games, a zero Base plays untimed games.
+	Control game.TimeControl
+
+	// MaxTurns ends a game in an adjudicated draw once it reaches the turn, zero uses DefaultMaxTurns.
+	MaxTurns int
+
+	// SPRT stops the match early once the results are convincing, see SPRT.
+	SPRT *SPRT
+
+	// Event is recorded in the game records.
+	Event string
+
+	// OnGame is called after each game.
+	OnGame func(Game)
+}
+
+// Opening is a sequence of moves in the notation of the uhp package.
+type Opening struct {
+	Name  string
+	Moves []string
+}
+
+// Game is a game of a match.
+type Game struct {
+	// Round is the number of the game in the match, the first game is 1.
+	Round int
+
+	// First is true when the first player played white.
+	First bool
+
+	// Score is the score of the first player, 1 for a win, 0.5 for a draw, and 0 for a loss.
+	Score float64
+
+	// Termination explains a result that was adjudicated, see agent.Verdict.
+	Termination string
+
+	// Summary counts the results of the match so far, this game included.
+	Summary Summary
+
+	Game   *game.Game
+	Record *record.Record
+}
+
+// Result is the outcome of a match.
+type Result struct {
+	Summary
+
+	// Decision is the outcome of the SPRT, Continue when no SPRT was set or when it didn't reach a decision.
+	Decision Decision
+}
+
+// Run plays the match between the two players, the results are from the perspective of the first player. The match
+// stops early when the context is done, the results of the games that were finished are returned along with the
+// error of the context.
+func Run(ctx context.Context, first, second Player, cfg Config) (Result, error) {
+	var res Result
+	if cfg.SPRT != nil {
+		if err := cfg.SPRT.validate(); err != nil {
+			return res, err
+		}
+	}
+
+	for round := 1; round <= cfg.Games; round++ {
+		// colors alternate so that each opening is played once from each side
+		white, black, firstIsWhite := first, second, round%2 == 1
+		if !firstIsWhite {
+			white, black = second, first
+		}
+		var opening Opening
+		if len(cfg.Openings) > 0 {
+			opening = cfg.Openings[(round-1)/2%len(cfg.Openings)]
+		}
+
+		g, termination, err := play(ctx, white, black, opening, cfg)
+		if err != nil {
+			return res, err
+		}
+
+		result, _ := g.Result()
+		score := 0.5
+		if result.Winner != game.Tie {
+			score = 0
+			if (result.Winner == game.WhitePlayer) == firstIsWhite {
+				score = 1
+			}
+		}
+		res.add(score)
+
+		if cfg.OnGame != nil {
+			rec, err := record.FromGame(g, tags(round, white, black, opening, termination, cfg)...)
+			if err != nil {
+				return res, err
+			}
+			cfg.OnGame(Game{
+				Round:       round,
+				First:       firstIsWhite,
+				Score:       score,
+				Termination: termination,
+				Summary:     res.Summary,
+				Game:        g,
+				Record:      rec,
+			})
+		}
+
+		if cfg.SPRT != nil {
+			if res.Decision = cfg.SPRT.Test(res.Summary); res.Decision != Continue {
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// play plays a single game, the referee adjudicates a forfeit or a game that drags on past the turn limit.
+func play(ctx context.Context, white, black Player, opening Opening, cfg Config) (*game.Game, string, error) {
+	g := game.New(cfg.Features)
+	if err := uhp.PlayMoves(g, opening.Moves); err != nil {
+		return nil, "", fmt.Errorf("opening %s: %w", opening.Name, err)
+	}
+	if cfg.Control.Base > 0 {
+		if err := g.StartClock(cfg.Control, game.SystemClock{}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	r := &agent.Referee{White: white, Black: black, MaxTurns: cfg.MaxTurns}
+	if r.MaxTurns <= 0 {
+		r.MaxTurns = DefaultMaxTurns
+	}
+	v, err := r.Play(ctx, g)
+	if err != nil {
+		return nil, "", err
+	}
+	return g, v.Termination(), nil
+}
+
+func tags(round int, white, black Player, opening Opening, termination string, cfg Config) []record.Tag {
+	var t []record.Tag
+	if cfg.Event != "" {
+		t = append(t, record.Tag{Name: record.TagEvent, Value: cfg.Event})
+	}
+	t = append(t,
+		record.Tag{Name: record.TagDate, Value: time.Now().Format("2006.01.02")},
+		record.Tag{Name: TagRound, Value: strconv.Itoa(round)},
+		record.Tag{Name: record.TagWhite, Value: white.Name()},
+		record.Tag{Name: record.TagBlack, Value: black.Name()},
+	)
+	if cfg.Control.Base > 0 {
+		t = append(t, record.Tag{Name: record.TagTimeControl, Value: cfg.Control.String()})
+	}
+	if opening.Name != "" {
+		t = append(t, record.Tag{Name: record.TagOpening, Value: opening.Name})
+	}
+	if termination != "" {
+		t = append(t, record.Tag{Name: TagTermination, Value: termination})
+	}
+	return t
+}
+
+// ReadOpenings reads an opening suite, one opening per line with the moves separated by semicolons. An opening may
+// be named by starting the line with the name and a colon, otherwise it's named by its line number. Blank lines and
+// lines starting with # are skipped.
+//
+//	# a spider to the left, then the queens
+//	spiders: wS1;bS1 -wS1;wQ wS1/;bQ /bS1
+func ReadOpenings(r io.Reader) ([]Opening, error) {
+	var openings []Opening
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		o := Opening{Name: strconv.Itoa(line)}
+		if colon := strings.IndexByte(text, ':'); colon >= 0 {
+			o.Name, text = strings.TrimSpace(text[:colon]), text[colon+1:]
+		}
+		for _, m := range strings.Split(text, ";") {
+			if m = strings.TrimSpace(m); m != "" {
+				o.Moves = append(o.Moves, m)
+			}
+		}
+		if len(o.Moves) == 0 {
+			return nil, fmt.Errorf("line %d: %w", line, ErrEmptyOpening)
+		}
+		openings = append(openings, o)
+	}
+	return openings, scanner.Err()
+}
+
+const (
+	// DefaultMaxTurns is the turn at which a game is drawn when Config doesn't say otherwise.
+	DefaultMaxTurns = 200
+
+	// TagRound and TagTermination are added to the records of a match.
+	TagRound       = "Round"
+	TagTermination = "Termination"
+)
+
+var ErrEmptyOpening = fmt.Errorf("the opening has no moves")
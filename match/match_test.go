@@ -0,0 +1,179 @@
+package match
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/agent"
+	"github.com/theshadow/hive/ai"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/record"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("When the players alternate colors each opening is played from both sides", func(t *testing.T) {
+		var games []Game
+		cfg := Config{
+			Games:    4,
+			MaxTurns: 6,
+			Openings: []Opening{{Name: "spiders", Moves: []string{"wS1", "bS1 -wS1"}}, {Name: "queens", Moves: []string{"wQ", "bQ wQ/"}}},
+			OnGame:   func(g Game) { games = append(games, g) },
+		}
+		res, err := Run(context.Background(), &firstLegal{"first"}, &firstLegal{"second"}, cfg)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while running the match", err)
+		}
+		if res.Games != 4 || res.Draws != 4 || len(games) != 4 {
+			t.Fatalf("Expected 4 drawn games instead received %s", res.Summary)
+		}
+
+		expected := []struct{ white, opening, move string }{
+			{"first", "spiders", "wS1"}, {"second", "spiders", "wS1"}, {"first", "queens", "wQ"}, {"second", "queens", "wQ"},
+		}
+		for i, e := range expected {
+			r := games[i].Record
+			if white, _ := r.Get(record.TagWhite); white != e.white {
+				t.Errorf("Expected %s to play white in round %d instead received %s", e.white, i+1, white)
+			}
+			if opening, _ := r.Get(record.TagOpening); opening != e.opening || r.Moves[0] != e.move {
+				t.Errorf("Expected round %d to open with %s instead received %s %v", i+1, e.opening, opening, r.Moves)
+			}
+			if reason, _ := r.Get(record.TagReason); reason != game.Adjudication.String() {
+				t.Errorf("Expected round %d to be adjudicated at the turn limit instead received %s", i+1, reason)
+			}
+		}
+	})
+
+	t.Run("When a player fails to return a legal action they forfeit", func(t *testing.T) {
+		for _, p := range []Player{&failing{err: ErrForTest}, &failing{action: hive.NewAction(hive.Moved, hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), hive.Origin, hive.NewCoordinate(9, 9, -18, 0))}} {
+			var last Game
+			cfg := Config{Games: 2, OnGame: func(g Game) { last = g }}
+			res, err := Run(context.Background(), &firstLegal{"first"}, p, cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error %#v while running the match", err)
+			}
+			if res.Wins != 2 {
+				t.Errorf("Expected the first player to win both games instead received %s", res.Summary)
+			}
+			if termination, _ := last.Record.Get(TagTermination); !strings.HasPrefix(termination, "White forfeited") {
+				t.Errorf("Expected the record to explain the forfeit instead received %q", termination)
+			}
+		}
+	})
+
+	t.Run("When a player runs out of time they lose on time", func(t *testing.T) {
+		var last Game
+		cfg := Config{Games: 1, Control: game.SuddenDeath(20 * time.Millisecond), OnGame: func(g Game) { last = g }}
+		res, err := Run(context.Background(), &stalling{}, &firstLegal{"second"}, cfg)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while running the match", err)
+		}
+		if r, _ := last.Game.Result(); res.Losses != 1 || r.Reason != game.Timeout {
+			t.Errorf("Expected the first player to lose on time instead received %s", r)
+		}
+		if tc, _ := last.Record.Get(record.TagTimeControl); tc != cfg.Control.String() {
+			t.Errorf("Expected the time control to be recorded instead received %q", tc)
+		}
+	})
+
+	t.Run("When the results are convincing the SPRT stops the match", func(t *testing.T) {
+		cfg := Config{Games: 100, SPRT: &SPRT{Elo0: 0, Elo1: 5, Alpha: 0.05, Beta: 0.05}}
+		res, err := Run(context.Background(), &firstLegal{"first"}, &failing{err: ErrForTest}, cfg)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while running the match", err)
+		}
+		if res.Decision != AcceptH1 || res.Games >= 100 {
+			t.Errorf("Expected H1 to be accepted early instead received %s after %d games", res.Decision, res.Games)
+		}
+	})
+
+	t.Run("When the context is done the match stops", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := Run(ctx, &stalling{}, &stalling{}, Config{Games: 2}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled instead received %#v", err)
+		}
+	})
+
+	t.Run("When a search player plays it returns legal moves", func(t *testing.T) {
+		p := Named("search", &agent.Search{Options: ai.Options{Depth: 1, Workers: 1}})
+		res, err := Run(context.Background(), p, &firstLegal{"second"}, Config{Games: 2, MaxTurns: 8})
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while running the match", err)
+		}
+		if res.Games != 2 {
+			t.Errorf("Expected 2 games instead received %s", res.Summary)
+		}
+	})
+}
+
+func TestReadOpenings(t *testing.T) {
+	t.Run("When the suite has names and comments they are read", func(t *testing.T) {
+		suite := "# the suite\nspiders: wS1; bS1 -wS1\n\nwQ;bQ wQ/\n"
+		openings, err := ReadOpenings(strings.NewReader(suite))
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while reading the suite", err)
+		}
+		if len(openings) != 2 {
+			t.Fatalf("Expected 2 openings instead received %d", len(openings))
+		}
+		if o := openings[0]; o.Name != "spiders" || len(o.Moves) != 2 || o.Moves[1] != "bS1 -wS1" {
+			t.Errorf("Expected the named spiders opening instead received %#v", o)
+		}
+		if o := openings[1]; o.Name != "4" || len(o.Moves) != 2 {
+			t.Errorf("Expected the opening to be named by its line instead received %#v", o)
+		}
+	})
+
+	t.Run("When an opening has no moves it is refused", func(t *testing.T) {
+		if _, err := ReadOpenings(strings.NewReader("empty:\n")); !errors.Is(err, ErrEmptyOpening) {
+			t.Errorf("Expected ErrEmptyOpening instead received %#v", err)
+		}
+	})
+}
+
+// firstLegal plays the first legal action.
+type firstLegal struct {
+	name string
+}
+
+func (p *firstLegal) Name() string {
+	return p.name
+}
+
+func (p *firstLegal) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	return g.LegalActions()[0], nil
+}
+
+// failing returns the error or, when there's none, the action.
+type failing struct {
+	err    error
+	action hive.Action
+}
+
+func (p *failing) Name() string {
+	return "failing"
+}
+
+func (p *failing) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	return p.action, p.err
+}
+
+// stalling waits until the context is done.
+type stalling struct{}
+
+func (p *stalling) Name() string {
+	return "stalling"
+}
+
+func (p *stalling) Next(ctx context.Context, g *game.Game) (hive.Action, error) {
+	<-ctx.Done()
+	return hive.Action{}, ctx.Err()
+}
+
+var ErrForTest = fmt.Errorf("the player failed")
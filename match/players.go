@@ -0,0 +1,25 @@
+package match
+
+import (
+	"github.com/theshadow/hive/agent"
+)
+
+// Player is an agent with a name to identify it in the game records.
+type Player interface {
+	agent.Agent
+	Name() string
+}
+
+// Named returns the agent as a player with the name.
+func Named(name string, a agent.Agent) Player {
+	return &named{Agent: a, name: name}
+}
+
+type named struct {
+	agent.Agent
+	name string
+}
+
+func (n *named) Name() string {
+	return n.name
+}
@@ -0,0 +1,153 @@
+package match
+
+import (
+	"fmt"
+	"math"
+)
+
+// Summary counts the results of a match from the perspective of the first player.
+type Summary struct {
+	Games, Wins, Losses, Draws int
+}
+
+// add counts a game with the score of the first player, 1 for a win, 0.5 for a draw, and 0 for a loss.
+func (s *Summary) add(score float64) {
+	s.Games++
+	switch score {
+	case 1:
+		s.Wins++
+	case 0:
+		s.Losses++
+	default:
+		s.Draws++
+	}
+}
+
+// Score returns the average score of the first player.
+func (s Summary) Score() float64 {
+	if s.Games == 0 {
+		return 0.5
+	}
+	return (float64(s.Wins) + float64(s.Draws)/2) / float64(s.Games)
+}
+
+// variance returns the variance of the score of a single game.
+func (s Summary) variance() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	mean, n := s.Score(), float64(s.Games)
+	return (float64(s.Wins)*(1-mean)*(1-mean) + float64(s.Draws)*(0.5-mean)*(0.5-mean) +
+		float64(s.Losses)*mean*mean) / n
+}
+
+// Elo returns the Elo difference between the first and the second player along with the margin of its 95% confidence
+// interval. A player that won or lost every game is infinitely stronger or weaker as far as the numbers are
+// concerned.
+func (s Summary) Elo() (diff, margin float64) {
+	score := s.Score()
+	diff = eloFromScore(score)
+	if s.Games == 0 {
+		return diff, math.Inf(1)
+	}
+
+	deviation := math.Sqrt(s.variance() / float64(s.Games))
+	low, high := eloFromScore(score-z95*deviation), eloFromScore(score+z95*deviation)
+	return diff, (high - low) / 2
+}
+
+func (s Summary) String() string {
+	diff, margin := s.Elo()
+	return fmt.Sprintf("Games: %d W: %d L: %d D: %d Elo: %.1f +/- %.1f", s.Games, s.Wins, s.Losses, s.Draws, diff, margin)
+}
+
+// SPRT is a sequential probability ratio test, it stops a match as soon as the results are convincing enough. The
+// hypotheses are that the first player is Elo0 stronger than the second, H0, or Elo1 stronger, H1. Alpha is the
+// chance of accepting H1 when H0 holds and Beta the chance of accepting H0 when H1 holds.
+//
+// A typical test of a change is Elo0 0 and Elo1 5 with Alpha and Beta 0.05, when H1 is accepted the change made the
+// engine stronger.
+type SPRT struct {
+	Elo0, Elo1  float64
+	Alpha, Beta float64
+}
+
+// Decision is the outcome of an SPRT.
+type Decision int
+
+const (
+	// Continue means the results aren't convincing either way yet.
+	Continue Decision = iota
+	AcceptH0
+	AcceptH1
+)
+
+func (d Decision) String() string {
+	switch d {
+	case AcceptH0:
+		return "H0 accepted"
+	case AcceptH1:
+		return "H1 accepted"
+	}
+	return "Continue"
+}
+
+// LLR returns the log-likelihood ratio of the results, it uses the normal approximation of the score. The variance is
+// kept above minVariance so that a player winning every game doesn't leave the ratio undefined.
+func (t SPRT) LLR(s Summary) float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	v := s.variance()
+	if v < minVariance {
+		v = minVariance
+	}
+	s0, s1 := scoreFromElo(t.Elo0), scoreFromElo(t.Elo1)
+	return float64(s.Games) * (s1 - s0) * (2*s.Score() - s0 - s1) / (2 * v)
+}
+
+// Bounds returns the log-likelihood ratios at which H0 and H1 are accepted.
+func (t SPRT) Bounds() (lower, upper float64) {
+	return math.Log(t.Beta / (1 - t.Alpha)), math.Log((1 - t.Beta) / t.Alpha)
+}
+
+// Test decides whether the results are convincing enough to stop the match.
+func (t SPRT) Test(s Summary) Decision {
+	llr := t.LLR(s)
+	lower, upper := t.Bounds()
+	switch {
+	case llr >= upper:
+		return AcceptH1
+	case llr <= lower:
+		return AcceptH0
+	}
+	return Continue
+}
+
+func (t SPRT) validate() error {
+	if t.Elo1 <= t.Elo0 || t.Alpha <= 0 || t.Alpha >= 1 || t.Beta <= 0 || t.Beta >= 1 {
+		return ErrInvalidSPRT
+	}
+	return nil
+}
+
+func eloFromScore(score float64) float64 {
+	if score <= 0 {
+		return math.Inf(-1)
+	} else if score >= 1 {
+		return math.Inf(1)
+	}
+	return 400 * math.Log10(score/(1-score))
+}
+
+func scoreFromElo(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+// z95 is the number of standard deviations either side of the mean a 95% confidence interval spans.
+const z95 = 1.959964
+
+// minVariance is the smallest variance of the score of a single game LLR assumes.
+const minVariance = 0.01
+
+var ErrInvalidSPRT = fmt.Errorf("an SPRT needs Elo1 above Elo0 and Alpha and Beta between 0 and 1")
@@ -0,0 +1,68 @@
+package match
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	t.Run("When the first player scores 60% they are about 70 Elo stronger", func(t *testing.T) {
+		s := Summary{Games: 100, Wins: 50, Losses: 30, Draws: 20}
+		diff, margin := s.Elo()
+		if math.Abs(diff-70.44) > 0.01 || math.Abs(margin-62.59) > 0.01 {
+			t.Errorf("Expected 70.44 +/- 62.59 instead received %.2f +/- %.2f", diff, margin)
+		}
+	})
+
+	t.Run("When every game is drawn the players are even", func(t *testing.T) {
+		s := Summary{Games: 10, Draws: 10}
+		if diff, margin := s.Elo(); diff != 0 || margin != 0 {
+			t.Errorf("Expected 0 +/- 0 instead received %f +/- %f", diff, margin)
+		}
+	})
+
+	t.Run("When the first player wins every game the difference is unbounded", func(t *testing.T) {
+		s := Summary{Games: 4, Wins: 4}
+		if diff, _ := s.Elo(); !math.IsInf(diff, 1) {
+			t.Errorf("Expected an infinite difference instead received %f", diff)
+		}
+	})
+}
+
+func TestSPRT(t *testing.T) {
+	sprt := SPRT{Elo0: 0, Elo1: 5, Alpha: 0.05, Beta: 0.05}
+
+	t.Run("When there are few games the test continues", func(t *testing.T) {
+		s := Summary{Games: 100, Wins: 50, Losses: 30, Draws: 20}
+		if llr := sprt.LLR(s); math.Abs(llr-0.365) > 0.001 {
+			t.Errorf("Expected an LLR of 0.365 instead received %f", llr)
+		}
+		if d := sprt.Test(s); d != Continue {
+			t.Errorf("Expected the test to continue instead received %s", d)
+		}
+	})
+
+	t.Run("When the first player keeps winning H1 is accepted", func(t *testing.T) {
+		if d := sprt.Test(Summary{Games: 1000, Wins: 500, Losses: 300, Draws: 200}); d != AcceptH1 {
+			t.Errorf("Expected H1 to be accepted instead received %s", d)
+		}
+		if d := sprt.Test(Summary{Games: 20, Wins: 20}); d != AcceptH1 {
+			t.Errorf("Expected H1 to be accepted after a clean sweep instead received %s", d)
+		}
+	})
+
+	t.Run("When the first player keeps losing H0 is accepted", func(t *testing.T) {
+		if d := sprt.Test(Summary{Games: 1000, Wins: 300, Losses: 500, Draws: 200}); d != AcceptH0 {
+			t.Errorf("Expected H0 to be accepted instead received %s", d)
+		}
+	})
+
+	t.Run("When the hypotheses are reversed the test is invalid", func(t *testing.T) {
+		bad := SPRT{Elo0: 5, Elo1: 0, Alpha: 0.05, Beta: 0.05}
+		if _, err := Run(context.Background(), nil, nil, Config{SPRT: &bad}); !errors.Is(err, ErrInvalidSPRT) {
+			t.Errorf("Expected ErrInvalidSPRT instead received %#v", err)
+		}
+	})
+}
@@ -64,7 +64,7 @@ func (p Piece) Bug() uint8 {
 	return uint8(uint32(p) & BugMask >> 16)
 }
 func (p Piece) BugS() string {
-	return bugLabels[p.Bug()]
+	return bugLabel(p.Bug())
 }
 func (p Piece) IsPieceA() bool {
 	return p.Piece() == PieceA
@@ -127,14 +127,5 @@ var colorLabels = []string{
 	"White",
 }
 
-var bugLabels = []string{
-	"No Bug",
-	"Queen",
-	"Beetle",
-	"Grasshopper",
-	"Spider",
-	"Ant",
-	"Mosquito",
-	"Ladybug",
-	"PillBug",
-}
+// noBugLabel is the label of a bug that isn't registered, see BugRegistry.
+const noBugLabel = "No Bug"
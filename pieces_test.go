@@ -70,8 +70,8 @@ func TestPiece_Bug(t *testing.T) {
 		if p.Bug() != i {
 			t.Errorf("Bug didn't return %d", i)
 		}
-		if p.BugS() != bugLabels[i] {
-			t.Errorf("BugS didn't return %s", bugLabels[i])
+		if def, _ := DefaultBugs.Lookup(i); p.BugS() != def.Label {
+			t.Errorf("BugS didn't return %s", def.Label)
 		}
 	}
 }
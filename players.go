@@ -193,6 +193,71 @@ func (p *Player) TakePillBug() error {
 	return nil
 }
 
+// Remaining returns the number of pieces of the bug left in the players inventory, see Stock.
+func (p *Player) Remaining(bug uint8) int {
+	switch bug {
+	case Queen:
+		return count(p.HasQueen())
+	case Ant:
+		return p.Ants()
+	case Grasshopper:
+		return p.Grasshoppers()
+	case Beetle:
+		return p.Beetles()
+	case Spider:
+		return p.Spiders()
+	case Mosquito:
+		return count(p.HasMosquito())
+	case Ladybug:
+		return count(p.HasLadybug())
+	case PillBug:
+		return count(p.HasPillBug())
+	}
+	return 0
+}
+
+// Total returns the number of pieces of the bug a player starts with, which is always the standard set.
+func (p *Player) Total(bug uint8) int {
+	return StandardInventory.Count(bug)
+}
+
+// Take will attempt to take a piece of the bug from the players inventory and will return an ErrNoPieceAvailable if
+// there aren't any pieces available.
+func (p *Player) Take(bug uint8) error {
+	switch bug {
+	case Queen:
+		return p.TakeQueen()
+	case Ant:
+		return p.TakeAnAnt()
+	case Grasshopper:
+		return p.TakeAGrasshopper()
+	case Beetle:
+		return p.TakeABeetle()
+	case Spider:
+		return p.TakeASpider()
+	case Mosquito:
+		return p.TakeMosquito()
+	case Ladybug:
+		return p.TakeLadybug()
+	case PillBug:
+		return p.TakePillBug()
+	}
+	return ErrNoPieceAvailable
+}
+
+// Copy returns a copy of the player, see Stock.
+func (p *Player) Copy() Stock {
+	c := *p
+	return &c
+}
+
+func count(has bool) int {
+	if has {
+		return 1
+	}
+	return 0
+}
+
 // String
 func (p *Player) String() string {
 	color := "White"
@@ -0,0 +1,32 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package rating rates players from the results of their finished games, for ladders and leaderboards.
+
+Matches and Periods
+
+A Match is a finished game between two named players along with its Winner, a Tie included, and the rating period it
+was played in. Ratings are updated one period at a time, every match of a period is rated against the ratings the
+players had at the start of the period, so the order of the matches within a period doesn't change the outcome. A
+period may be a day, a week, or a round of a tournament, whatever suits the ladder. Use one period per match to rate
+every game as it's played.
+
+Systems
+
+Elo is the classic system, it adjusts a rating by a K factor times the difference between the score and the expected
+score. Glicko2 implements Mark Glickman's Glicko-2 system, which tracks how certain it is about each rating with a
+rating deviation and a volatility, and grows less certain about players who don't play.
+
+A player is provisional until they've played a number of games set by the system. Elo rates provisional players with
+a larger K factor so that they reach their level quickly.
+
+Ladders and Logs
+
+A Ladder holds the ratings of every player and applies periods to them. Compute rebuilds a ladder from a log of
+matches, the result only depends on the log so a ladder may always be recomputed from scratch, after correcting a
+result for example. ReadLog and WriteLog keep a log as JSON, one match per line:
+
+	{"period":1,"white":"alice","black":"bob","winner":"White"}
+*/
+package rating
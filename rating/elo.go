@@ -0,0 +1,60 @@
+package rating
+
+import (
+	"math"
+)
+
+// Elo is the Elo rating system. The rating of a player changes by K times the difference between their score and
+// their expected score, summed over the matches of the period.
+type Elo struct {
+	// Start is the rating of a new player.
+	Start float64
+
+	// K is the K factor of established players.
+	K float64
+
+	// ProvisionalK is the K factor of provisional players.
+	ProvisionalK float64
+
+	// ProvisionalGames is the number of games a player remains provisional for.
+	ProvisionalGames int
+}
+
+// NewElo returns an Elo system with the values commonly used by game communities, players start at 1500 and are
+// provisional for their first 20 games during which K is 40 instead of 20.
+func NewElo() *Elo {
+	return &Elo{Start: 1500, K: 20, ProvisionalK: 40, ProvisionalGames: 20}
+}
+
+func (e *Elo) Initial() Rating {
+	return Rating{Rating: e.Start, Provisional: e.ProvisionalGames > 0}
+}
+
+func (e *Elo) Rate(ratings map[string]Rating, matches []Match) {
+	delta := make(map[string]float64)
+	played := make(map[string]int)
+	for _, m := range matches {
+		for _, player := range []string{m.White, m.Black} {
+			r, opponent := ratings[player], ratings[m.opponent(player)]
+			k := e.K
+			if r.Provisional {
+				k = e.ProvisionalK
+			}
+			delta[player] += k * (m.score(player) - expectedElo(r.Rating, opponent.Rating))
+			played[player]++
+		}
+	}
+
+	for player, d := range delta {
+		r := ratings[player]
+		r.Rating += d
+		r.Games += played[player]
+		r.Provisional = r.Games < e.ProvisionalGames
+		ratings[player] = r
+	}
+}
+
+// expectedElo returns the expected score of a player rated a against a player rated b.
+func expectedElo(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
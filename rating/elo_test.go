@@ -0,0 +1,49 @@
+package rating
+
+import (
+	"math"
+	"testing"
+
+	"github.com/theshadow/hive/game"
+)
+
+func TestElo(t *testing.T) {
+	t.Run("When equally rated players tie their ratings do not change", func(t *testing.T) {
+		l := NewLadder(NewElo())
+		if err := l.Rate([]Match{{White: "alice", Black: "bob", Winner: game.Tie}}); err != nil {
+			t.Fatalf("Unexpected error %#v while rating the period", err)
+		}
+		for _, player := range []string{"alice", "bob"} {
+			if r, _ := l.Rating(player); r.Rating != 1500 || r.Games != 1 {
+				t.Errorf("Expected %s to stay at 1500 after 1 game instead received %#v", player, r)
+			}
+		}
+	})
+
+	t.Run("When a provisional player wins they gain the provisional K factor", func(t *testing.T) {
+		l := NewLadder(NewElo())
+		if err := l.Rate([]Match{{White: "alice", Black: "bob", Winner: game.BlackPlayer}}); err != nil {
+			t.Fatalf("Unexpected error %#v while rating the period", err)
+		}
+		if r, _ := l.Rating("bob"); r.Rating != 1520 || !r.Provisional {
+			t.Errorf("Expected bob to be provisional at 1520 instead received %#v", r)
+		}
+	})
+
+	t.Run("When a player is established the K factor is used", func(t *testing.T) {
+		e := NewElo()
+		ratings := map[string]Rating{
+			"alice": {Rating: 1600, Games: 30},
+			"bob":   {Rating: 1400, Games: 30},
+		}
+		e.Rate(ratings, []Match{{White: "alice", Black: "bob", Winner: game.WhitePlayer}})
+
+		// alice was expected to score 0.76 against bob
+		if r := ratings["alice"]; math.Abs(r.Rating-1604.81) > 0.01 || r.Provisional {
+			t.Errorf("Expected alice to be established at 1604.81 instead received %#v", r)
+		}
+		if a, b := ratings["alice"].Rating-1600, 1400-ratings["bob"].Rating; math.Abs(a-b) > 1e-9 {
+			t.Errorf("Expected the points won by alice to be the points lost by bob instead received %f and %f", a, b)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package rating
+
+import (
+	"math"
+)
+
+// Glicko2 is Mark Glickman's Glicko-2 rating system, see http://www.glicko.net/glicko/glicko2.pdf. Besides the rating
+// it tracks a rating deviation, how uncertain the rating is, and a volatility, how erratic the player's results are.
+// The deviation of a player shrinks as they play and grows during the periods they don't, up to the deviation of a new
+// player, a player that hasn't played in a long time is as uncertain as one that never has.
+type Glicko2 struct {
+	// Start, StartDeviation, and StartVolatility make up the rating of a new player.
+	Start           float64
+	StartDeviation  float64
+	StartVolatility float64
+
+	// Tau constrains how much the volatility may change in a period, smaller values suit games with fewer upsets.
+	// Glickman suggests a value between 0.3 and 1.2.
+	Tau float64
+
+	// ProvisionalGames is the number of games a player remains provisional for.
+	ProvisionalGames int
+}
+
+// NewGlicko2 returns a Glicko-2 system with the values suggested by Glickman, players start at 1500 with a deviation
+// of 350 and a volatility of 0.06, and tau is 0.5. Players are provisional for their first 20 games.
+func NewGlicko2() *Glicko2 {
+	return &Glicko2{Start: 1500, StartDeviation: 350, StartVolatility: 0.06, Tau: 0.5, ProvisionalGames: 20}
+}
+
+func (gl *Glicko2) Initial() Rating {
+	return Rating{
+		Rating:      gl.Start,
+		Deviation:   gl.StartDeviation,
+		Volatility:  gl.StartVolatility,
+		Provisional: gl.ProvisionalGames > 0,
+	}
+}
+
+func (gl *Glicko2) Rate(ratings map[string]Rating, matches []Match) {
+	byPlayer := make(map[string][]Match)
+	for _, m := range matches {
+		byPlayer[m.White] = append(byPlayer[m.White], m)
+		byPlayer[m.Black] = append(byPlayer[m.Black], m)
+	}
+
+	// every player is rated against the ratings at the start of the period
+	next := make(map[string]Rating, len(ratings))
+	for player, r := range ratings {
+		next[player] = gl.rate(player, r, ratings, byPlayer[player])
+	}
+	for player, r := range next {
+		ratings[player] = r
+	}
+}
+
+// rate returns the rating of the player after the matches they played in the period, step 2 through 8 of the paper.
+func (gl *Glicko2) rate(player string, r Rating, ratings map[string]Rating, matches []Match) Rating {
+	mu, phi := (r.Rating-glickoStart)/glickoScale, r.Deviation/glickoScale
+	if len(matches) == 0 {
+		r.Deviation = gl.capDeviation(math.Sqrt(phi*phi+r.Volatility*r.Volatility) * glickoScale)
+		return r
+	}
+
+	var v, improvement float64
+	for _, m := range matches {
+		opponent := ratings[m.opponent(player)]
+		muJ, phiJ := (opponent.Rating-glickoStart)/glickoScale, opponent.Deviation/glickoScale
+		g := 1 / math.Sqrt(1+3*phiJ*phiJ/(math.Pi*math.Pi))
+		e := 1 / (1 + math.Exp(-g*(mu-muJ)))
+		v += g * g * e * (1 - e)
+		improvement += g * (m.score(player) - e)
+	}
+	v = 1 / v
+	delta := v * improvement
+
+	sigma := gl.volatility(phi, r.Volatility, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * improvement
+
+	r.Rating = mu*glickoScale + glickoStart
+	r.Deviation = gl.capDeviation(phi * glickoScale)
+	r.Volatility = sigma
+	r.Games += len(matches)
+	r.Provisional = r.Games < gl.ProvisionalGames
+	return r
+}
+
+// capDeviation keeps the deviation from growing past StartDeviation, as the paper asks. A system without a starting
+// deviation leaves it alone.
+func (gl *Glicko2) capDeviation(deviation float64) float64 {
+	if gl.StartDeviation > 0 && deviation > gl.StartDeviation {
+		return gl.StartDeviation
+	}
+	return deviation
+}
+
+// volatility finds the new volatility with the Illinois algorithm, step 5 of the paper.
+func (gl *Glicko2) volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-d)/(2*d*d) - (x-a)/(gl.Tau*gl.Tau)
+	}
+
+	A, B := a, 0.0
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*gl.Tau) < 0 {
+			k++
+		}
+		B = a - k*gl.Tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+const (
+	// glickoScale converts between the Glicko scale and the Glicko-2 scale, 400 / ln(10).
+	glickoScale = 173.7178
+	glickoStart = 1500
+
+	// glickoEpsilon is the tolerance of the volatility.
+	glickoEpsilon = 0.000001
+)
@@ -0,0 +1,67 @@
+package rating
+
+import (
+	"math"
+	"testing"
+
+	"github.com/theshadow/hive/game"
+)
+
+func TestGlicko2(t *testing.T) {
+	t.Run("When rating the example from the paper the published rating is returned", func(t *testing.T) {
+		ratings := map[string]Rating{
+			"player": {Rating: 1500, Deviation: 200, Volatility: 0.06},
+			"a":      {Rating: 1400, Deviation: 30, Volatility: 0.06},
+			"b":      {Rating: 1550, Deviation: 100, Volatility: 0.06},
+			"c":      {Rating: 1700, Deviation: 300, Volatility: 0.06},
+		}
+		matches := []Match{
+			{White: "player", Black: "a", Winner: game.WhitePlayer},
+			{White: "b", Black: "player", Winner: game.WhitePlayer},
+			{White: "player", Black: "c", Winner: game.BlackPlayer},
+		}
+
+		NewGlicko2().Rate(ratings, matches)
+
+		r := ratings["player"]
+		if math.Abs(r.Rating-1464.06) > 0.01 || math.Abs(r.Deviation-151.52) > 0.01 || math.Abs(r.Volatility-0.05999) > 0.00001 {
+			t.Errorf("Expected a rating of 1464.06 with a deviation of 151.52 and a volatility of 0.05999 instead received %#v", r)
+		}
+	})
+
+	t.Run("When a player does not play in a period their deviation grows", func(t *testing.T) {
+		l := NewLadder(NewGlicko2())
+		if err := l.Rate([]Match{{White: "alice", Black: "bob", Winner: game.Tie}}); err != nil {
+			t.Fatalf("Unexpected error %#v while rating the period", err)
+		}
+		before, _ := l.Rating("alice")
+		if err := l.Rate([]Match{{White: "bob", Black: "carol", Winner: game.WhitePlayer}}); err != nil {
+			t.Fatalf("Unexpected error %#v while rating the period", err)
+		}
+
+		after, _ := l.Rating("alice")
+		if after.Rating != before.Rating || after.Deviation <= before.Deviation {
+			t.Errorf("Expected the deviation to grow and the rating to stay the same instead received %#v from %#v", after, before)
+		}
+	})
+
+	t.Run("When a player does not play for many periods their deviation stops at the starting deviation", func(t *testing.T) {
+		gl := NewGlicko2()
+		l := NewLadder(gl)
+		if err := l.Rate([]Match{{White: "alice", Black: "bob", Winner: game.Tie}}); err != nil {
+			t.Fatalf("Unexpected error %#v while rating the period", err)
+		}
+		for i := 0; i < 500; i++ {
+			if err := l.Rate([]Match{{White: "bob", Black: "carol", Winner: game.WhitePlayer}}); err != nil {
+				t.Fatalf("Unexpected error %#v while rating the period", err)
+			}
+		}
+
+		if r, _ := l.Rating("alice"); r.Deviation != gl.StartDeviation {
+			t.Errorf("Expected a deviation of %v instead received %v", gl.StartDeviation, r.Deviation)
+		}
+		if r, _ := l.Rating("carol"); r.Deviation > gl.StartDeviation {
+			t.Errorf("Expected a deviation of at most %v instead received %v", gl.StartDeviation, r.Deviation)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package rating
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/theshadow/hive/game"
+)
+
+// Rating is the rating of a player. Deviation and Volatility are only used by systems that track the uncertainty of a
+// rating, such as Glicko2, and are zero otherwise.
+type Rating struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation,omitempty"`
+	Volatility float64 `json:"volatility,omitempty"`
+
+	// Games is the number of rated games the player has played.
+	Games int `json:"games"`
+
+	// Provisional is true while the player hasn't played enough games for the rating to be trusted.
+	Provisional bool `json:"provisional"`
+}
+
+// Match is a finished game between two players.
+type Match struct {
+	// Period is the rating period the match was played in, see the package documentation.
+	Period int
+
+	// White and Black name the players.
+	White, Black string
+
+	Winner game.Winner
+}
+
+// FromGame returns the match for a finished game, it returns game.ErrGameNotOver when the game hasn't ended.
+func FromGame(period int, white, black string, g *game.Game) (Match, error) {
+	w, err := g.Winner()
+	if err != nil {
+		return Match{}, err
+	}
+	return Match{Period: period, White: white, Black: black, Winner: w}, nil
+}
+
+// score returns the score of the player in the match, 1 for a win, 0.5 for a tie, and 0 for a loss.
+func (m Match) score(player string) float64 {
+	switch {
+	case m.Winner == game.Tie:
+		return 0.5
+	case (m.Winner == game.WhitePlayer) == (player == m.White):
+		return 1
+	}
+	return 0
+}
+
+// opponent returns the other player of the match.
+func (m Match) opponent(player string) string {
+	if player == m.White {
+		return m.Black
+	}
+	return m.White
+}
+
+func (m Match) validate() error {
+	if m.White == "" || m.Black == "" {
+		return fmt.Errorf("%w: both players must be named", ErrInvalidMatch)
+	}
+	if m.White == m.Black {
+		return fmt.Errorf("%w: %s may not play themselves", ErrInvalidMatch, m.White)
+	}
+	if m.Winner != game.WhitePlayer && m.Winner != game.BlackPlayer && m.Winner != game.Tie {
+		return fmt.Errorf("%w: %s", ErrInvalidMatch, game.ErrUnknownWinner)
+	}
+	return nil
+}
+
+func (m Match) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMatch{Period: m.Period, White: m.White, Black: m.Black, Winner: m.Winner.String()})
+}
+func (m *Match) UnmarshalJSON(data []byte) error {
+	var v jsonMatch
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	w, err := game.ParseWinner(v.Winner)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMatch, err)
+	}
+	*m = Match{Period: v.Period, White: v.White, Black: v.Black, Winner: w}
+	return nil
+}
+
+type jsonMatch struct {
+	Period int    `json:"period"`
+	White  string `json:"white"`
+	Black  string `json:"black"`
+	Winner string `json:"winner"`
+}
+
+// System is a rating system.
+type System interface {
+	// Initial is the rating of a player before their first game.
+	Initial() Rating
+
+	// Rate applies one rating period. The ratings hold every player known to the ladder, the players of the matches
+	// included, and are replaced with the ratings at the end of the period. The matches have been validated.
+	Rate(ratings map[string]Rating, matches []Match)
+}
+
+// Ladder holds the ratings of the players of a community and updates them one rating period at a time.
+type Ladder struct {
+	system  System
+	ratings map[string]Rating
+}
+
+func NewLadder(system System) *Ladder {
+	return &Ladder{system: system, ratings: make(map[string]Rating)}
+}
+
+// Rate applies the matches of a rating period to the ladder. Players that are new to the ladder start with the initial
+// rating of the system. When a match is invalid the ladder is left untouched.
+func (l *Ladder) Rate(matches []Match) error {
+	for _, m := range matches {
+		if err := m.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range matches {
+		for _, player := range []string{m.White, m.Black} {
+			if _, ok := l.ratings[player]; !ok {
+				l.ratings[player] = l.system.Initial()
+			}
+		}
+	}
+	l.system.Rate(l.ratings, matches)
+	return nil
+}
+
+// Rating returns the rating of the player, it returns false when the player isn't on the ladder.
+func (l *Ladder) Rating(player string) (Rating, bool) {
+	r, ok := l.ratings[player]
+	return r, ok
+}
+
+// Standing is the place of a player on the ladder.
+type Standing struct {
+	Player string `json:"player"`
+	Rating
+}
+
+// Standings returns the players from the highest rating to the lowest, players with the same rating are ordered by
+// name.
+func (l *Ladder) Standings() []Standing {
+	standings := make([]Standing, 0, len(l.ratings))
+	for player, r := range l.ratings {
+		standings = append(standings, Standing{Player: player, Rating: r})
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Rating.Rating != standings[j].Rating.Rating {
+			return standings[i].Rating.Rating > standings[j].Rating.Rating
+		}
+		return standings[i].Player < standings[j].Player
+	})
+	return standings
+}
+
+// Compute builds a ladder from a log of matches. The matches are grouped by period and the periods are applied from
+// the earliest to the latest, within a period the matches keep their order in the log. The same log always results in
+// the same ladder.
+func Compute(system System, matches []Match) (*Ladder, error) {
+	sorted := make([]Match, len(matches))
+	copy(sorted, matches)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Period < sorted[j].Period })
+
+	l := NewLadder(system)
+	for start := 0; start < len(sorted); {
+		end := start
+		for end < len(sorted) && sorted[end].Period == sorted[start].Period {
+			end++
+		}
+		if err := l.Rate(sorted[start:end]); err != nil {
+			return nil, fmt.Errorf("period %d: %w", sorted[start].Period, err)
+		}
+		start = end
+	}
+	return l, nil
+}
+
+// ReadLog reads a log of matches written by WriteLog, blank lines are skipped.
+func ReadLog(r io.Reader) ([]Match, error) {
+	var matches []Match
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var m Match
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, scanner.Err()
+}
+
+// WriteLog writes the matches as JSON, one match per line.
+func WriteLog(w io.Writer, matches []Match) error {
+	enc := json.NewEncoder(w)
+	for _, m := range matches {
+		if err := enc.Encode(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var ErrInvalidMatch = fmt.Errorf("the match is invalid")
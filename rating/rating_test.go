@@ -0,0 +1,110 @@
+package rating
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+func TestCompute(t *testing.T) {
+	matches := []Match{
+		{Period: 2, White: "carol", Black: "alice", Winner: game.WhitePlayer},
+		{Period: 1, White: "alice", Black: "bob", Winner: game.WhitePlayer},
+		{Period: 1, White: "bob", Black: "carol", Winner: game.Tie},
+		{Period: 2, White: "bob", Black: "alice", Winner: game.BlackPlayer},
+	}
+
+	t.Run("When a log is computed twice the ladders are the same", func(t *testing.T) {
+		for _, system := range []System{NewElo(), NewGlicko2()} {
+			a, err := Compute(system, matches)
+			if err != nil {
+				t.Fatalf("Unexpected error %#v while computing the ladder", err)
+			}
+			b, _ := Compute(system, matches)
+
+			sa, sb := a.Standings(), b.Standings()
+			if len(sa) != 3 || len(sa) != len(sb) {
+				t.Fatalf("Expected 3 players instead received %d and %d", len(sa), len(sb))
+			}
+			for i := range sa {
+				if sa[i] != sb[i] {
+					t.Errorf("Expected %#v instead received %#v", sa[i], sb[i])
+				}
+			}
+		}
+	})
+
+	t.Run("When the periods are applied in order the result matches rating each period", func(t *testing.T) {
+		computed, _ := Compute(NewElo(), matches)
+
+		l := NewLadder(NewElo())
+		l.Rate([]Match{matches[1], matches[2]})
+		l.Rate([]Match{matches[0], matches[3]})
+
+		for _, player := range []string{"alice", "bob", "carol"} {
+			expected, _ := l.Rating(player)
+			if r, _ := computed.Rating(player); r != expected {
+				t.Errorf("Expected %s to be rated %#v instead received %#v", player, expected, r)
+			}
+		}
+	})
+
+	t.Run("When a log is written and read the matches are the same", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteLog(&buf, matches); err != nil {
+			t.Fatalf("Unexpected error %#v while writing the log", err)
+		}
+		read, err := ReadLog(&buf)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while reading the log", err)
+		}
+		if len(read) != len(matches) {
+			t.Fatalf("Expected %d matches instead received %d", len(matches), len(read))
+		}
+		for i := range read {
+			if read[i] != matches[i] {
+				t.Errorf("Expected %#v instead received %#v", matches[i], read[i])
+			}
+		}
+	})
+
+	t.Run("When a player plays themselves an error is returned", func(t *testing.T) {
+		_, err := Compute(NewElo(), []Match{{Period: 1, White: "alice", Black: "alice", Winner: game.Tie}})
+		if !errors.Is(err, ErrInvalidMatch) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidMatch, err)
+		}
+	})
+
+	t.Run("When a log has an unknown winner an error is returned", func(t *testing.T) {
+		_, err := ReadLog(bytes.NewBufferString(`{"period":1,"white":"alice","black":"bob","winner":"Nobody"}`))
+		if !errors.Is(err, ErrInvalidMatch) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidMatch, err)
+		}
+	})
+}
+
+func TestFromGame(t *testing.T) {
+	t.Run("When the game is over the match has its winner", func(t *testing.T) {
+		g := game.New(nil)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := g.Resign(hive.BlackColor); err != nil {
+			t.Fatalf("Unexpected error %#v while black was resigning", err)
+		}
+
+		m, err := FromGame(1, "alice", "bob", g)
+		if err != nil || m.Winner != game.WhitePlayer {
+			t.Errorf("Expected white to have won instead received %s, %v", m.Winner, err)
+		}
+	})
+
+	t.Run("When the game is not over an error is returned", func(t *testing.T) {
+		if _, err := FromGame(1, "alice", "bob", game.New(nil)); !errors.Is(err, game.ErrGameNotOver) {
+			t.Errorf("Expected an error of type %#v instead received %#v", game.ErrGameNotOver, err)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package record reads and writes game records, the text files games are kept in once they're over.
+
+Format
+
+A record is a set of tags, one per line, followed by the moves of the game, one per line and numbered by turn. White
+moves are numbered "1." and black moves "1...". The moves use the notation of the uhp package. Records may be written
+one after the other in the same file, a tag following the moves of a record starts the next one.
+
+	[White "alice"]
+	[Black "bob"]
+	[GameType "Base+MLP"]
+	[Result "White"]
+	[Reason "Resignation"]
+
+	1. wS1
+	1... bG1 -wS1
+	2. wQ wS1/
+
+Players that don't start with the standard set of pieces are listed in an Inventory tag in the notation of
+uhp.InventoryString, the game is set up with the inventories before the moves are played.
+
+A handicap game has a Handicap tag in the notation of uhp.HandicapString, the handicap is applied before the moves are
+played. The extra placements of a handicap don't change how moves are numbered, they're still counted in pairs.
+
+The Result and Reason tags hold the Winner and the Reason of the game.Result. A game that ended on the board, a
+surrounded queen for example, follows from its moves. For any other ending the tags are what records it, when the
+record is replayed with Game the result is applied once the moves have been played.
+
+Annotations
+
+A record may also hold the variations of a game and annotations on its moves, for study and puzzles. They're kept in
+a tree of Nodes, see Annotations, and written the way chess games are. A symbol judging a move, a NAG such as ! or ?!,
+follows the move. A comment in braces follows the move too, and may carry arrows and marked cells as commands. A
+variation is written in parentheses after the move it's an alternative to, and variations may be nested.
+
+	{A short game. [%mark wS1]}
+	1. wS1
+	1... bG1 -wS1?! {Passive. [%arrow bG1 wS1/]}
+	(
+	  1... bA1 wS1-! {Develops the ant.}
+	  2. wQ /wS1
+	)
+	2. wQ wS1/
+
+Cells are written as the reference of a move and are read on the position of the node with uhp.ParsePosition. When
+annotating by hand the layout is free, moves may share a line and comments may span lines, but every move is numbered.
+*/
+package record
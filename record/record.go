@@ -0,0 +1,334 @@
+package record
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/uhp"
+)
+
+// Record is a game as it's kept in a text file, a set of tags describing the game followed by its moves in the
+// notation of the uhp package. See the package documentation for the format.
+type Record struct {
+	// Tags are kept in the order they were added or read.
+	Tags []Tag
+
+	// Moves are the moves of the game, in order.
+	Moves []string
+
+	// Tree holds the variations and the annotations of the game, nil when the record has neither. When it's set its
+	// main line is the moves of the game and Moves is ignored, see Annotations.
+	Tree *Node
+}
+
+// Tag is a named value describing a game.
+type Tag struct {
+	Name, Value string
+}
+
+// FromGame returns the record of the game. The game type, the inventories of players that don't have the standard set,
+// the handicap of a handicap game, the result, and the reason are tagged, along with any of the extra tags, which are
+// added first.
+func FromGame(g *game.Game, tags ...Tag) (*Record, error) {
+	moves, err := uhp.Moves(g)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Record{Moves: moves}
+	for _, t := range tags {
+		r.Set(t.Name, t.Value)
+	}
+	r.Set(TagGameType, uhp.GameTypeString(g.Features()))
+	if inv := uhp.InventoryString(g.Inventory(hive.WhiteColor), g.Inventory(hive.BlackColor)); inv != "" {
+		r.Set(TagInventory, inv)
+	}
+	if h := g.Handicap(); !h.IsZero() {
+		r.Set(TagHandicap, uhp.HandicapString(h))
+	}
+	if res, err := g.Result(); err == nil {
+		r.Set(TagResult, res.Winner.String())
+		r.Set(TagReason, res.Reason.String())
+	}
+	return r, nil
+}
+
+// Get returns the value of the tag, it returns false when the record doesn't have the tag.
+func (r *Record) Get(name string) (string, bool) {
+	for _, t := range r.Tags {
+		if t.Name == name {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set replaces the value of the tag, or adds the tag when the record doesn't have it yet.
+func (r *Record) Set(name, value string) {
+	for i, t := range r.Tags {
+		if t.Name == name {
+			r.Tags[i].Value = value
+			return
+		}
+	}
+	r.Tags = append(r.Tags, Tag{Name: name, Value: value})
+}
+
+// Game replays the record. The moves are played on a new game with the features of the game type and the handicap,
+// and a result that didn't come about on the board, a resignation for example, is applied at the end.
+func (r *Record) Game() (*game.Game, error) {
+	g, err := r.newGame()
+	if err != nil {
+		return nil, err
+	}
+	if err := uhp.PlayMoves(g, r.moves()); err != nil {
+		return nil, err
+	}
+	if err := r.conclude(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Annotations returns the tree of the moves for the variations and the annotations of the game to be read or added.
+// A record without a tree is given one with Moves as its main line.
+func (r *Record) Annotations() *Node {
+	if r.Tree == nil {
+		r.Tree = NewTree(r.Moves)
+	}
+	return r.Tree
+}
+
+// Position returns the game at the position of the node of the tree, the cells of its arrows and marks are read on
+// it. The result of the record isn't applied.
+func (r *Record) Position(n *Node) (*game.Game, error) {
+	g, err := r.newGame()
+	if err != nil {
+		return nil, err
+	}
+	if err := uhp.PlayMoves(g, n.Line()); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// newGame returns the game the moves of the record are played on, set up with its game type, inventories, and
+// handicap.
+func (r *Record) newGame() (*game.Game, error) {
+	gameType, ok := r.Get(TagGameType)
+	if !ok {
+		gameType = uhp.GameTypeString(nil)
+	}
+	features, err := uhp.ParseGameType(gameType)
+	if err != nil {
+		return nil, err
+	}
+
+	white, black := hive.StandardInventory, hive.StandardInventory
+	if value, ok := r.Get(TagInventory); ok {
+		if white, black, err = uhp.ParseInventory(value); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRecord, err)
+		}
+	}
+
+	g := game.New(features, white, black)
+	if value, ok := r.Get(TagHandicap); ok {
+		h, err := uhp.ParseHandicap(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRecord, err)
+		}
+		if err := g.ApplyHandicap(h); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRecord, err)
+		}
+	}
+	return g, nil
+}
+
+// moves returns the main line of the game.
+func (r *Record) moves() []string {
+	if r.Tree != nil {
+		return r.Tree.MainLine()
+	}
+	return r.Moves
+}
+
+// Replay replays the record for random access to its positions, see game.Replay.
+func (r *Record) Replay() (*game.Replay, error) {
+	g, err := r.Game()
+	if err != nil {
+		return nil, err
+	}
+	return g.Replay()
+}
+
+// conclude applies a result to the game that the moves don't explain.
+func (r *Record) conclude(g *game.Game) error {
+	result, hasResult := r.Get(TagResult)
+	reason, hasReason := r.Get(TagReason)
+	if !hasResult || !hasReason || g.Over() {
+		return nil
+	}
+
+	w, err := game.ParseWinner(result)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRecord, err)
+	}
+	var loser uint8 = hive.BlackColor
+	if w == game.BlackPlayer {
+		loser = hive.WhiteColor
+	}
+
+	switch reason {
+	case game.Resignation.String():
+		return g.Resign(loser)
+	case game.Agreement.String():
+		return g.AgreeDraw()
+	case game.Adjudication.String():
+		return g.Adjudicate(w)
+	case game.Timeout.String():
+		return g.Play(hive.NewAction(hive.TimedOut, hive.NewPiece(loser, hive.NoBug, hive.NoPiece), 0, 0))
+	}
+	return fmt.Errorf("%w: the moves don't end in %s by %s", ErrInvalidRecord, result, reason)
+}
+
+// WriteTo writes the record, it ends with a blank line so that records may be written one after the other. The tree
+// is written when the record has one, a comment with a brace in it can't be written.
+func (r *Record) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, t := range r.Tags {
+		fmt.Fprintf(&b, "[%s %s]\n", t.Name, strconv.Quote(t.Value))
+	}
+	b.WriteString("\n")
+
+	tree := r.Tree
+	if tree == nil {
+		tree = NewTree(r.Moves)
+	}
+	if err := writeTree(&b, tree); err != nil {
+		return 0, err
+	}
+	b.WriteString("\n")
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ReadAll reads every record written one after the other, see WriteTo.
+func ReadAll(rd io.Reader) ([]*Record, error) {
+	var records []*Record
+	var current *Record
+	// moves holds the text of the moves of the current record, a comment may span lines
+	var moves strings.Builder
+	inMoves, inComment := false, false
+
+	// finish reads the moves of the current record once all of its lines are known
+	finish := func() error {
+		if current == nil || !inMoves {
+			return nil
+		}
+		tree, err := parseTree(moves.String())
+		if err != nil {
+			return err
+		}
+		current.Moves = tree.MainLine()
+		if tree.annotated() {
+			current.Tree = tree
+		}
+		moves.Reset()
+		return nil
+	}
+
+	scanner := bufio.NewScanner(rd)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" && !inComment:
+			continue
+		case strings.HasPrefix(text, "[") && !inComment:
+			// a tag after the moves starts the next record
+			if current == nil || inMoves {
+				if err := finish(); err != nil {
+					return nil, fmt.Errorf("record %d: %w", len(records), err)
+				}
+				current = &Record{}
+				records = append(records, current)
+				inMoves = false
+			}
+			t, err := parseTag(text)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			current.Tags = append(current.Tags, t)
+		default:
+			if current == nil {
+				current = &Record{}
+				records = append(records, current)
+			}
+			moves.WriteString(text)
+			moves.WriteString("\n")
+			inMoves = true
+			if opened, closed := strings.LastIndexByte(text, '{'), strings.LastIndexByte(text, '}'); opened > closed {
+				inComment = true
+			} else if closed >= 0 {
+				inComment = false
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, fmt.Errorf("record %d: %w", len(records), err)
+	}
+	return records, nil
+}
+
+// Read reads a single record.
+func Read(rd io.Reader) (*Record, error) {
+	records, err := ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) != 1 {
+		return nil, fmt.Errorf("%w: expected one record instead found %d", ErrInvalidRecord, len(records))
+	}
+	return records[0], nil
+}
+
+func parseTag(text string) (Tag, error) {
+	if !strings.HasSuffix(text, "]") {
+		return Tag{}, fmt.Errorf("%w: %q is not a tag", ErrInvalidRecord, text)
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "["), "]")
+	space := strings.IndexByte(text, ' ')
+	if space <= 0 {
+		return Tag{}, fmt.Errorf("%w: the tag %q has no value", ErrInvalidRecord, text)
+	}
+	value, err := strconv.Unquote(strings.TrimSpace(text[space+1:]))
+	if err != nil {
+		return Tag{}, fmt.Errorf("%w: the value of the tag %q is not quoted", ErrInvalidRecord, text[:space])
+	}
+	return Tag{Name: text[:space], Value: value}, nil
+}
+
+// The well known tags, FromGame writes GameType, Inventory, Handicap, Result, and Reason. Applications are free to add their own.
+const (
+	TagEvent       = "Event"
+	TagDate        = "Date"
+	TagWhite       = "White"
+	TagBlack       = "Black"
+	TagGameType    = "GameType"
+	TagInventory   = "Inventory"
+	TagHandicap    = "Handicap"
+	TagTimeControl = "TimeControl"
+	TagOpening     = "Opening"
+	TagResult      = "Result"
+	TagReason      = "Reason"
+)
+
+var ErrInvalidRecord = fmt.Errorf("the game record is invalid")
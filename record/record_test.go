@@ -0,0 +1,155 @@
+package record
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/uhp"
+)
+
+func TestRecord(t *testing.T) {
+	resigned := func(t *testing.T) *game.Game {
+		t.Helper()
+		g := game.New([]game.Feature{game.PillBugPieceFeature})
+		if err := uhp.PlayMoves(g, []string{"wS1", "bG1 -wS1", "wQ wS1/"}); err != nil {
+			t.Fatalf("Unexpected error %#v while playing the moves", err)
+		}
+		if err := g.Resign(hive.BlackColor); err != nil {
+			t.Fatalf("Unexpected error %#v while black was resigning", err)
+		}
+		return g
+	}
+
+	t.Run("When a record is written and read the game is the same", func(t *testing.T) {
+		g := resigned(t)
+		r, err := FromGame(g, Tag{TagWhite, "alice"}, Tag{TagBlack, "bob"})
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while recording the game", err)
+		}
+
+		var buf bytes.Buffer
+		if _, err := r.WriteTo(&buf); err != nil {
+			t.Fatalf("Unexpected error %#v while writing the record", err)
+		}
+		read, err := Read(&buf)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while reading the record", err)
+		}
+		if white, _ := read.Get(TagWhite); white != "alice" {
+			t.Errorf("Expected the white player to be alice instead received %q", white)
+		}
+
+		replayed, err := read.Game()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while replaying the record", err)
+		}
+		if replayed.Hash() != g.Hash() {
+			t.Error("Expected the replayed game to reach the same position")
+		}
+		if res, _ := replayed.Result(); res != (game.Result{Winner: game.WhitePlayer, Reason: game.Resignation}) {
+			t.Errorf("Expected white to win by resignation instead received %s", res)
+		}
+	})
+
+	t.Run("When records are written one after the other they are all read", func(t *testing.T) {
+		var buf bytes.Buffer
+		for i := 0; i < 3; i++ {
+			r, _ := FromGame(resigned(t))
+			r.WriteTo(&buf)
+		}
+		records, err := ReadAll(&buf)
+		if err != nil || len(records) != 3 {
+			t.Errorf("Expected 3 records instead received %d, %v", len(records), err)
+		}
+	})
+
+	t.Run("When the moves are out of order an error is returned", func(t *testing.T) {
+		_, err := Read(strings.NewReader("[GameType \"Base\"]\n\n1. wS1\n1. bG1 -wS1\n"))
+		if !errors.Is(err, ErrInvalidRecord) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidRecord, err)
+		}
+	})
+
+	t.Run("When a handicap game is recorded the handicap is tagged and applied on replay", func(t *testing.T) {
+		g := game.New(nil)
+		if err := g.ApplyHandicap(game.Handicap{Black: game.Odds{Placements: 1}}); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the handicap", err)
+		}
+		if err := uhp.PlayMoves(g, []string{"wS1", "bG1 -wS1", "bA1 /bG1"}); err != nil {
+			t.Fatalf("Unexpected error %#v while playing the moves", err)
+		}
+		r, err := FromGame(g)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while recording the game", err)
+		}
+		if h, _ := r.Get(TagHandicap); h != "Black placements=1" {
+			t.Errorf("Expected the handicap to be tagged instead received %q", h)
+		}
+		replayed, err := r.Game()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while replaying the record", err)
+		}
+		if replayed.Hash() != g.Hash() || replayed.Turn() != hive.WhiteColor {
+			t.Error("Expected the replayed game to reach the same position")
+		}
+	})
+
+	t.Run("When a game with custom inventories is recorded the inventories are tagged and kept on replay", func(t *testing.T) {
+		white, _ := hive.NewInventory(map[uint8]int{hive.Queen: 1, hive.Spider: 1, hive.Ant: 2})
+		g := game.New(nil, white, hive.StandardInventory)
+		if err := uhp.PlayMoves(g, []string{"wS1", "bG1 -wS1"}); err != nil {
+			t.Fatalf("Unexpected error %#v while playing the moves", err)
+		}
+		r, err := FromGame(g)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while recording the game", err)
+		}
+
+		var buf bytes.Buffer
+		if _, err := r.WriteTo(&buf); err != nil {
+			t.Fatalf("Unexpected error %#v while writing the record", err)
+		}
+		read, err := Read(&buf)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while reading the record", err)
+		}
+		if inv, _ := read.Get(TagInventory); inv != "White QSAA" {
+			t.Errorf("Expected the inventory of white to be tagged instead received %q", inv)
+		}
+		replayed, err := read.Game()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while replaying the record", err)
+		}
+		if replayed.Inventory(hive.WhiteColor) != white || replayed.Inventory(hive.BlackColor) != hive.StandardInventory {
+			t.Errorf("Expected the inventories to be kept instead received %s and %s",
+				replayed.Inventory(hive.WhiteColor), replayed.Inventory(hive.BlackColor))
+		}
+		if replayed.Hash() != g.Hash() {
+			t.Error("Expected the replayed game to reach the same position")
+		}
+	})
+
+	t.Run("When a record is replayed each position may be revisited", func(t *testing.T) {
+		r, err := FromGame(resigned(t))
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while recording the game", err)
+		}
+		replay, err := r.Replay()
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while replaying the record", err)
+		}
+		if res, _ := replay.Result(); res.Reason != game.Resignation {
+			t.Errorf("Expected the replay to end in a resignation instead received %s", res)
+		}
+		if err := replay.Seek(1); err != nil {
+			t.Fatalf("Unexpected error %#v while seeking", err)
+		}
+		if n := len(replay.Pieces()); n != 1 {
+			t.Errorf("Expected %d piece on the board instead received %d", 1, n)
+		}
+	})
+}
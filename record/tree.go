@@ -0,0 +1,493 @@
+package record
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Node is a position of an annotated game, the one reached by playing its move from the position of its parent. The
+// root of a tree is the position before the first move and has no move. The variations of a node are the moves that
+// may be played next, the first of them continues the main line.
+type Node struct {
+	// Move is the move played to reach the position in the notation of the uhp package, empty for the root.
+	Move string
+
+	// NAGs are the symbols judging the move, see NAG.
+	NAGs []NAG
+
+	// Comment is the text the move is annotated with, it may not contain braces.
+	Comment string
+
+	// Arrows and Marks are drawn on the board of the position. Cells are written as the reference of a move, "bQ" or
+	// "-bQ" for example, and are read with uhp.ParsePosition.
+	Arrows []Arrow
+	Marks  []string
+
+	Parent     *Node
+	Variations []*Node
+}
+
+// Arrow points from a cell of the board to another.
+type Arrow struct {
+	From, To string
+}
+
+// NAG is a numeric annotation glyph, a symbol judging a move. The numbers are those of the glyphs of chess, which the
+// first six symbols are written as.
+type NAG uint8
+
+const (
+	NoNAG NAG = iota
+	GoodMove
+	Mistake
+	BrilliantMove
+	Blunder
+	InterestingMove
+	DubiousMove
+)
+
+// NewTree returns the root of a tree with the moves as its main line.
+func NewTree(moves []string) *Node {
+	root := &Node{}
+	n := root
+	for _, m := range moves {
+		n = n.Add(m)
+	}
+	return root
+}
+
+// Add returns the variation of the node that plays the move, it's added as the last variation when the node doesn't
+// have it yet.
+func (n *Node) Add(move string) *Node {
+	for _, v := range n.Variations {
+		if v.Move == move {
+			return v
+		}
+	}
+	v := &Node{Move: move, Parent: n}
+	n.Variations = append(n.Variations, v)
+	return v
+}
+
+// Ply returns the number of moves played to reach the node, 0 for the root.
+func (n *Node) Ply() int {
+	ply := 0
+	for ; n.Parent != nil; n = n.Parent {
+		ply++
+	}
+	return ply
+}
+
+// Root returns the root of the tree of the node.
+func (n *Node) Root() *Node {
+	for n.Parent != nil {
+		n = n.Parent
+	}
+	return n
+}
+
+// Line returns the moves played to reach the node from the root.
+func (n *Node) Line() []string {
+	line := make([]string, n.Ply())
+	for i := len(line) - 1; i >= 0; i-- {
+		line[i], n = n.Move, n.Parent
+	}
+	return line
+}
+
+// MainLine returns the moves that follow the node along the first variation of each position.
+func (n *Node) MainLine() []string {
+	var line []string
+	for len(n.Variations) > 0 {
+		n = n.Variations[0]
+		line = append(line, n.Move)
+	}
+	return line
+}
+
+// Promote makes the node the first variation of its parent, the main line.
+func (n *Node) Promote() {
+	if n.Parent == nil {
+		return
+	}
+	siblings := n.Parent.Variations
+	for i, v := range siblings {
+		if v == n {
+			copy(siblings[1:i+1], siblings[:i])
+			siblings[0] = n
+			return
+		}
+	}
+}
+
+// Remove takes the node and its variations out of the tree.
+func (n *Node) Remove() {
+	if n.Parent == nil {
+		return
+	}
+	siblings := n.Parent.Variations
+	for i, v := range siblings {
+		if v == n {
+			n.Parent.Variations = append(siblings[:i:i], siblings[i+1:]...)
+			break
+		}
+	}
+	n.Parent = nil
+}
+
+// annotated returns true when anything but the main line is recorded in the tree of the node.
+func (n *Node) annotated() bool {
+	if len(n.NAGs) > 0 || n.Comment != "" || len(n.Arrows) > 0 || len(n.Marks) > 0 || len(n.Variations) > 1 {
+		return true
+	}
+	return len(n.Variations) == 1 && n.Variations[0].annotated()
+}
+
+// String returns the symbol of the glyph, "!" for GoodMove for example, or its number as "$7" when it has no symbol.
+func (a NAG) String() string {
+	if a >= GoodMove && int(a) < len(nagSymbols) {
+		return nagSymbols[a]
+	}
+	return "$" + strconv.Itoa(int(a))
+}
+
+// ParseNAG returns the glyph of the symbol or of the number, see NAG.String.
+func ParseNAG(s string) (NAG, error) {
+	for i, symbol := range nagSymbols {
+		if i > 0 && s == symbol {
+			return NAG(i), nil
+		}
+	}
+	if strings.HasPrefix(s, "$") {
+		if n, err := strconv.ParseUint(s[1:], 10, 8); err == nil {
+			return NAG(n), nil
+		}
+	}
+	return NoNAG, fmt.Errorf("%w: %q is not an annotation glyph", ErrInvalidRecord, s)
+}
+
+// writeTree writes the moves of the tree, the main line one move per line with each other variation following the
+// move it's an alternative to in parentheses.
+func writeTree(b *strings.Builder, root *Node) error {
+	if root.Comment != "" || len(root.Arrows) > 0 || len(root.Marks) > 0 {
+		comment, err := commentString(root)
+		if err != nil {
+			return err
+		}
+		b.WriteString(comment[1:])
+		b.WriteString("\n")
+	}
+	return writeLine(b, root, 0)
+}
+
+func writeLine(b *strings.Builder, n *Node, depth int) error {
+	for len(n.Variations) > 0 {
+		if err := writeMove(b, n.Variations[0], depth); err != nil {
+			return err
+		}
+		for _, v := range n.Variations[1:] {
+			indent := strings.Repeat(variationIndent, depth)
+			b.WriteString(indent + "(\n")
+			if err := writeMove(b, v, depth+1); err != nil {
+				return err
+			}
+			if err := writeLine(b, v, depth+1); err != nil {
+				return err
+			}
+			b.WriteString(indent + ")\n")
+		}
+		n = n.Variations[0]
+	}
+	return nil
+}
+
+func writeMove(b *strings.Builder, n *Node, depth int) error {
+	b.WriteString(strings.Repeat(variationIndent, depth))
+	b.WriteString(moveNumber(n.Ply() - 1))
+	b.WriteString(" ")
+	b.WriteString(n.Move)
+
+	// symbols are written against the move as in chess, numbers apart from it
+	for _, nag := range n.NAGs {
+		if s := nag.String(); strings.HasPrefix(s, "$") {
+			b.WriteString(" " + s)
+		} else {
+			b.WriteString(s)
+		}
+	}
+	comment, err := commentString(n)
+	if err != nil {
+		return err
+	}
+	b.WriteString(comment)
+	b.WriteString("\n")
+	return nil
+}
+
+// commentString returns the comment of the node with its arrows and marks as commands, " {text [%arrow a b]}", or
+// nothing when the node has none of them.
+func commentString(n *Node) (string, error) {
+	if n.Comment == "" && len(n.Arrows) == 0 && len(n.Marks) == 0 {
+		return "", nil
+	}
+	if strings.ContainsAny(n.Comment, "{}") {
+		return "", fmt.Errorf("%w: the comment of %q has a brace", ErrInvalidRecord, n.Move)
+	}
+
+	parts := make([]string, 0, 2+len(n.Arrows))
+	if n.Comment != "" {
+		parts = append(parts, n.Comment)
+	}
+	for _, a := range n.Arrows {
+		parts = append(parts, fmt.Sprintf("[%%%s %s %s]", arrowCommand, a.From, a.To))
+	}
+	if len(n.Marks) > 0 {
+		parts = append(parts, fmt.Sprintf("[%%%s %s]", markCommand, strings.Join(n.Marks, " ")))
+	}
+	return " {" + strings.Join(parts, " ") + "}", nil
+}
+
+// moveNumber returns the number of the move at the index, counted from 0, "1." for white and "1..." for black.
+func moveNumber(index int) string {
+	if index%2 == 0 {
+		return fmt.Sprintf("%d.", index/2+1)
+	}
+	return fmt.Sprintf("%d...", index/2+1)
+}
+
+// parseTree reads the moves of a record, see writeTree.
+func parseTree(text string) (*Node, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &Node{}
+	current := root
+	// starts holds the node each open variation was started from, to return to once it's closed
+	var starts []*Node
+	var number string
+	var fields []string
+
+	// flush adds the move read so far as a variation of the current node
+	flush := func() error {
+		if number == "" {
+			return nil
+		}
+		if len(fields) == 0 || len(fields) > 2 {
+			return fmt.Errorf("%w: %q is not a move", ErrInvalidRecord, number+" "+strings.Join(fields, " "))
+		}
+		if want := moveNumber(current.Ply()); number != want {
+			return fmt.Errorf("%w: %q is out of order", ErrInvalidRecord, number+" "+strings.Join(fields, " "))
+		}
+		current = current.Add(strings.Join(fields, " "))
+		number, fields = "", nil
+		return nil
+	}
+
+	for _, tok := range tokens {
+		// anything but a field of a move ends the move
+		if tok.kind != fieldToken {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+
+		switch tok.kind {
+		case numberToken:
+			number = tok.text
+		case fieldToken:
+			if number == "" {
+				return nil, fmt.Errorf("%w: %q is not a numbered move", ErrInvalidRecord, tok.text)
+			}
+			field, nags := splitNAGs(tok.text)
+			fields = append(fields, field)
+			if len(nags) > 0 {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				current.NAGs = append(current.NAGs, nags...)
+			}
+		case nagToken:
+			nag, err := ParseNAG(tok.text)
+			if err != nil {
+				return nil, err
+			}
+			if current == root {
+				return nil, fmt.Errorf("%w: %q doesn't follow a move", ErrInvalidRecord, tok.text)
+			}
+			current.NAGs = append(current.NAGs, nag)
+		case commentToken:
+			// only the root may be commented on before its move, a variation is commented on after its first move
+			if current == root && len(root.Variations) > 0 || len(starts) > 0 && current == starts[len(starts)-1].Parent {
+				return nil, fmt.Errorf("%w: the comment {%s} doesn't follow a move", ErrInvalidRecord, tok.text)
+			}
+			if err := parseComment(current, tok.text); err != nil {
+				return nil, err
+			}
+		case openToken:
+			if current == root {
+				return nil, fmt.Errorf("%w: a variation doesn't follow a move", ErrInvalidRecord)
+			}
+			starts = append(starts, current)
+			current = current.Parent
+		case closeToken:
+			if len(starts) == 0 {
+				return nil, fmt.Errorf("%w: a variation is closed that wasn't opened", ErrInvalidRecord)
+			}
+			current, starts = starts[len(starts)-1], starts[:len(starts)-1]
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(starts) > 0 {
+		return nil, fmt.Errorf("%w: a variation isn't closed", ErrInvalidRecord)
+	}
+	return root, nil
+}
+
+// splitNAGs separates the symbols written against the end of a field of a move, "bG1!?" for example.
+func splitNAGs(field string) (string, []NAG) {
+	end := strings.TrimRight(field, "!?")
+	if end == field || end == "" {
+		return field, nil
+	}
+
+	var nags []NAG
+	symbols := field[len(end):]
+	for len(symbols) > 0 {
+		// the two character symbols are tried first
+		n := 2
+		if len(symbols) < n {
+			n = 1
+		}
+		nag, err := ParseNAG(symbols[:n])
+		if err != nil {
+			n = 1
+			nag, _ = ParseNAG(symbols[:n])
+		}
+		nags = append(nags, nag)
+		symbols = symbols[n:]
+	}
+	return end, nags
+}
+
+// parseComment reads the text of a comment into the node, taking its arrow and mark commands apart from the text.
+func parseComment(n *Node, text string) error {
+	var rest []string
+	for {
+		start := strings.Index(text, "[%")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], ']')
+		if end < 0 {
+			return fmt.Errorf("%w: the command %q isn't closed", ErrInvalidRecord, text[start:])
+		}
+		rest = append(rest, text[:start])
+		args := strings.Fields(text[start+2 : start+end])
+		text = text[start+end+1:]
+
+		switch {
+		case len(args) == 3 && args[0] == arrowCommand:
+			n.Arrows = append(n.Arrows, Arrow{From: args[1], To: args[2]})
+		case len(args) > 1 && args[0] == markCommand:
+			n.Marks = append(n.Marks, args[1:]...)
+		default:
+			return fmt.Errorf("%w: the command %q is unknown", ErrInvalidRecord, strings.Join(args, " "))
+		}
+	}
+	rest = append(rest, text)
+
+	comment := strings.Join(strings.Fields(strings.Join(rest, " ")), " ")
+	if n.Comment != "" && comment != "" {
+		comment = n.Comment + " " + comment
+	} else if comment == "" {
+		comment = n.Comment
+	}
+	n.Comment = comment
+	return nil
+}
+
+type token struct {
+	kind int
+	text string
+}
+
+const (
+	fieldToken = iota
+	numberToken
+	nagToken
+	commentToken
+	openToken
+	closeToken
+)
+
+// tokenize splits the moves of a record into move numbers, the fields of the moves, glyphs, comments, and the
+// parentheses around variations.
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: a comment isn't closed", ErrInvalidRecord)
+			}
+			tokens = append(tokens, token{commentToken, text[i+1 : i+end]})
+			i += end + 1
+		case c == '}':
+			return nil, fmt.Errorf("%w: a comment is closed that wasn't opened", ErrInvalidRecord)
+		case c == '(':
+			tokens = append(tokens, token{openToken, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{closeToken, ")"})
+			i++
+		default:
+			end := i
+			for end < len(text) && !unicode.IsSpace(rune(text[end])) && !strings.ContainsRune("{}()", rune(text[end])) {
+				end++
+			}
+			tokens = append(tokens, wordTokens(text[i:end])...)
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// wordTokens returns the tokens of a word, a move number may be written against its move as in "1.wS1".
+func wordTokens(word string) []token {
+	digits := strings.IndexFunc(word, func(r rune) bool { return r < '0' || r > '9' })
+	if digits > 0 && word[digits] == '.' {
+		dots := digits
+		for dots < len(word) && word[dots] == '.' {
+			dots++
+		}
+		tokens := []token{{numberToken, word[:dots]}}
+		if dots < len(word) {
+			tokens = append(tokens, wordTokens(word[dots:])...)
+		}
+		return tokens
+	}
+	if _, err := ParseNAG(word); err == nil {
+		return []token{{nagToken, word}}
+	}
+	return []token{{fieldToken, word}}
+}
+
+// nagSymbols is indexed by NAG.
+var nagSymbols = [...]string{"", "!", "?", "!!", "??", "!?", "?!"}
+
+const (
+	arrowCommand = "arrow"
+	markCommand  = "mark"
+
+	variationIndent = "  "
+)
@@ -0,0 +1,150 @@
+package record
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/theshadow/hive/uhp"
+)
+
+func TestTree(t *testing.T) {
+	const annotated = `[GameType "Base"]
+
+{A short game. [%mark wS1]}
+1. wS1
+1... bG1 -wS1?! {Passive. [%arrow bG1 wS1/]}
+(
+  1... bA1 wS1-! {Develops the ant.}
+  2. wQ /wS1
+  (
+    2. wG1 /wS1 $7
+  )
+)
+2. wQ wS1/
+
+`
+
+	t.Run("When an annotated record is read the variations and annotations are kept", func(t *testing.T) {
+		r, err := Read(strings.NewReader(annotated))
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while reading the record", err)
+		}
+		if want := []string{"wS1", "bG1 -wS1", "wQ wS1/"}; !reflect.DeepEqual(r.Moves, want) {
+			t.Errorf("Expected the main line %q instead received %q", want, r.Moves)
+		}
+		if r.Tree == nil {
+			t.Fatal("Expected the record to have a tree")
+		}
+		if r.Tree.Comment != "A short game." || !reflect.DeepEqual(r.Tree.Marks, []string{"wS1"}) {
+			t.Errorf("Expected the game to be commented on instead received %q %q", r.Tree.Comment, r.Tree.Marks)
+		}
+
+		first := r.Tree.Variations[0]
+		if n := len(first.Variations); n != 2 {
+			t.Fatalf("Expected %d replies to the first move instead received %d", 2, n)
+		}
+		main, side := first.Variations[0], first.Variations[1]
+		if !reflect.DeepEqual(main.NAGs, []NAG{DubiousMove}) || main.Comment != "Passive." {
+			t.Errorf("Expected a dubious move commented on instead received %s %q", main.NAGs, main.Comment)
+		}
+		if !reflect.DeepEqual(main.Arrows, []Arrow{{From: "bG1", To: "wS1/"}}) {
+			t.Errorf("Expected an arrow instead received %v", main.Arrows)
+		}
+		if side.Move != "bA1 wS1-" || !reflect.DeepEqual(side.NAGs, []NAG{GoodMove}) {
+			t.Errorf("Expected a good ant move instead received %q %s", side.Move, side.NAGs)
+		}
+		nested := side.Variations[0].Variations
+		if len(nested) != 0 || len(side.Variations) != 2 || side.Variations[1].NAGs[0] != NAG(7) {
+			t.Errorf("Expected the nested variation to follow the ant move")
+		}
+		if want := []string{"wS1", "bA1 wS1-", "wG1 /wS1"}; !reflect.DeepEqual(side.Variations[1].Line(), want) {
+			t.Errorf("Expected the line %q instead received %q", want, side.Variations[1].Line())
+		}
+	})
+
+	t.Run("When an annotated record is written it's read back the same", func(t *testing.T) {
+		r, _ := Read(strings.NewReader(annotated))
+		var b strings.Builder
+		if _, err := r.WriteTo(&b); err != nil {
+			t.Fatalf("Unexpected error %#v while writing the record", err)
+		}
+		if b.String() != annotated {
+			t.Errorf("Expected the record\n%s\ninstead received\n%s", annotated, b.String())
+		}
+	})
+
+	t.Run("When the moves are written freely they're read the same", func(t *testing.T) {
+		free := `[GameType "Base"]
+{A short game.
+[%mark wS1]} 1.wS1 1... bG1 -wS1 ?! {Passive. [%arrow bG1 wS1/]}
+(1... bA1 wS1- ! {Develops
+the ant.} 2. wQ /wS1 (2. wG1 /wS1 $7)) 2. wQ wS1/`
+		r, err := Read(strings.NewReader(free))
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while reading the record", err)
+		}
+		var b strings.Builder
+		if _, err := r.WriteTo(&b); err != nil {
+			t.Fatalf("Unexpected error %#v while writing the record", err)
+		}
+		if b.String() != annotated {
+			t.Errorf("Expected the record\n%s\ninstead received\n%s", annotated, b.String())
+		}
+	})
+
+	t.Run("When the marks of a node are read on its position they name cells", func(t *testing.T) {
+		r, _ := Read(strings.NewReader(annotated))
+		main := r.Tree.Variations[0].Variations[0]
+		g, err := r.Position(main)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while playing to the position", err)
+		}
+		for _, cell := range []string{main.Arrows[0].From, main.Arrows[0].To} {
+			if _, err := uhp.ParsePosition(g, cell); err != nil {
+				t.Errorf("Unexpected error %#v while reading the cell %s", err, cell)
+			}
+		}
+	})
+
+	t.Run("When a variation is promoted it becomes the main line", func(t *testing.T) {
+		r, _ := Read(strings.NewReader(annotated))
+		side := r.Tree.Variations[0].Variations[1]
+		side.Promote()
+		if want := []string{"wS1", "bA1 wS1-", "wQ /wS1"}; !reflect.DeepEqual(r.moves(), want) {
+			t.Errorf("Expected the main line %q instead received %q", want, r.moves())
+		}
+		side.Remove()
+		if want := []string{"wS1", "bG1 -wS1", "wQ wS1/"}; !reflect.DeepEqual(r.moves(), want) {
+			t.Errorf("Expected the main line %q instead received %q", want, r.moves())
+		}
+	})
+
+	t.Run("When a record without annotations is annotated its moves are the main line", func(t *testing.T) {
+		r := &Record{Moves: []string{"wS1", "bG1 -wS1"}}
+		r.Annotations().Variations[0].Variations[0].NAGs = []NAG{Blunder}
+
+		var b strings.Builder
+		if _, err := r.WriteTo(&b); err != nil {
+			t.Fatalf("Unexpected error %#v while writing the record", err)
+		}
+		if !strings.Contains(b.String(), "1... bG1 -wS1??\n") {
+			t.Errorf("Expected the blunder to be written instead received\n%s", b.String())
+		}
+	})
+
+	t.Run("When the variations are malformed an error is returned", func(t *testing.T) {
+		for _, text := range []string{
+			"1. wS1\n(\n1... bG1 -wS1\n",
+			"1. wS1\n1... bG1 -wS1\n(\n2. wQ wS1/\n)\n",
+			"1. wS1 {unclosed\n",
+			"(\n1. wS1\n)\n",
+			"1. wS1 {[%circle wS1]}\n",
+		} {
+			if _, err := Read(strings.NewReader(text)); !errors.Is(err, ErrInvalidRecord) {
+				t.Errorf("Expected an error of type %#v for %q instead received %#v", ErrInvalidRecord, text, err)
+			}
+		}
+	})
+}
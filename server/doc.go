@@ -0,0 +1,61 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package server is a reference HTTP server for hosting games. It exposes the game package as a small JSON API and is
+what cmd/hived runs, it may also be mounted into another server as it's a plain http.Handler.
+
+Endpoints
+
+	GET  /games                list the identifiers of the games
+	POST /games                create a game, the body may enable features, a clock, and a handicap:
+	                           {"features": ["PillBug"], "clock": {"base": "10m", "increment": "5s"}}
+	                           {"handicap": {"white": {"removed": {"Ant": 1}}, "black": {"placements": 1}}}
+	GET  /games/{id}           the state of the game
+	GET  /games/{id}/actions   the legal actions for the player whose turn it is
+	POST /games/{id}/actions   perform an action, the body is an action such as {"act": "Passed"}, the query
+	                           ?player=White or ?player=Black acts for the player
+	GET  /games/{id}/history   the actions performed so far
+	GET  /games/{id}/stream    a WebSocket for playing and spectating in real time, see Message
+
+Pieces, coordinates, and actions use the JSON encoding of the hive package and games use the encoding of the game
+package.
+
+Errors
+
+Errors are returned as {"error": "..."} with a status that describes the failure. An action that breaks a rule is
+answered with 422 Unprocessable Entity and the response includes the identifier of the rule, {"error": "...", "rule":
+"piece-pinned"}, so a client may explain it to the player.
+
+Real Time
+
+The stream endpoint pushes every action to the players and spectators of a game as it happens. Each client receives
+a snapshot followed by deltas numbered by sequence, and may reconnect and resume from the last sequence it saw. The
+WebSocket is implemented with the standard library, Client connects to it from Go.
+
+Results
+
+A game ends on the board or with one of the actions that conclude it, posted to the actions endpoint like any other
+action. {"act": "Resigned", "color": "Black"} resigns for a player and is only accepted with ?player=Black.
+{"act": "Drawn"} offers a draw for the player and is answered with 202 Accepted until their opponent posts it as
+well, which draws the game. {"act": "TimedOut", "color": "Black"} claims the loss on time of a player whose clock ran
+out.
+
+{"act": "Adjudicated", "color": "White"} declares a winner, without a color a draw, for a game that can't be finished.
+It's only accepted from an admin, a request carrying the AdminToken of the server in the X-Hive-Admin header, and an
+admin may also post a draw without an offer. Anything a client isn't allowed to do is answered with 403 Forbidden. The
+state of a finished game includes its result, {"winner": "White", "reason": "Resignation"}.
+
+Clocks
+
+A game created with a clock is played under Fischer timing when it has an increment, Bronstein timing when it has a
+delay, and sudden death otherwise. The remaining time of each player is part of the state of the game and of every
+delta, and a player that runs out of time loses. A game loaded from the store keeps its clocks when the store records
+them, as store.FileLog does, and the time that passed while it was stored counts against the player whose turn it is.
+
+Storage
+
+Games being played are kept in memory. Every action is also appended to a store.GameStore so that a game may be
+picked up again after a restart, a store.Memory is used when nothing else is configured.
+*/
+package server
@@ -0,0 +1,409 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/store"
+)
+
+// Server hosts games over HTTP, see the package documentation for the endpoints.
+type Server struct {
+	// Clock is used by timed games, it defaults to the wall clock and may be replaced before the server is used.
+	Clock game.Clock
+
+	// AdminToken lets a request to the actions endpoint act as an admin, who may adjudicate, when it carries the token
+	// in the X-Hive-Admin header. Nobody may adjudicate while it's empty.
+	AdminToken string
+
+	store store.GameStore
+
+	mu    sync.Mutex
+	games map[string]*entry
+}
+
+// entry is a game that is loaded in memory. The session serializes access to the game while the lock keeps the
+// actions and the saves in the same order.
+type entry struct {
+	id      string
+	mu      sync.Mutex
+	session *game.Session
+
+	// the player that offered a draw, NoColor when there is no offer, see perform
+	drawOffer uint8
+}
+
+// New creates a server that persists its games to the store, when the store is nil a store.Memory is used.
+func New(gs store.GameStore) *Server {
+	if gs == nil {
+		gs = store.NewMemory()
+	}
+	return &Server{
+		Clock: game.SystemClock{},
+		store: gs,
+		games: make(map[string]*entry),
+	}
+}
+
+// ServeHTTP routes the request to the handler for the endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "games" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, ErrUnknownEndpoint)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.list(w, r)
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		s.create(w, r)
+	case len(parts) == 1:
+		writeError(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+	default:
+		e, err := s.lookup(parts[1])
+		if errors.Is(err, store.ErrGameNotFound) || errors.Is(err, store.ErrInvalidID) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		} else if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		endpoint := ""
+		if len(parts) == 3 {
+			endpoint = parts[2]
+		}
+		s.route(w, r, e, endpoint)
+	}
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, e *entry, endpoint string) {
+	switch {
+	case endpoint == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, e.session.Snapshot())
+	case endpoint == "actions" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, actionsResponse{Actions: nonNil(e.session.LegalActions())})
+	case endpoint == "actions" && r.Method == http.MethodPost:
+		s.act(w, r, e)
+	case endpoint == "history" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, historyResponse{History: nonNil(e.session.History())})
+	case endpoint == "stream" && r.Method == http.MethodGet:
+		s.stream(w, r, e)
+	case endpoint == "" || endpoint == "actions" || endpoint == "history" || endpoint == "stream":
+		writeError(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+	default:
+		writeError(w, http.StatusNotFound, ErrUnknownEndpoint)
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, _ *http.Request) {
+	ids, err := s.store.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, listResponse{Games: ids})
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var features []game.Feature
+	for _, name := range req.Features {
+		f, err := game.ParseFeature(name)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		features = append(features, f)
+	}
+
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	g := game.New(features)
+	if req.Handicap != nil {
+		if err := g.ApplyHandicap(*req.Handicap); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if req.Clock != nil {
+		tc, err := req.Clock.control()
+		if err == nil {
+			err = g.StartClock(tc, s.Clock)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if err := s.store.Save(id, g); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.games[id] = &entry{id: id, session: game.NewSession(g.Clone()), drawOffer: hive.NoColor}
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/games/"+id)
+	writeJSON(w, http.StatusCreated, createResponse{ID: id, Game: g})
+}
+
+func (s *Server) act(w http.ResponseWriter, r *http.Request, e *entry) {
+	color, err := parsePlayer(r.URL.Query().Get("player"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var a hive.Action
+	if err := decode(r, &a); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	token := r.Header.Get("X-Hive-Admin")
+	admin := s.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.AdminToken)) == 1
+	g, err := s.perform(e, a, role{color: color, admin: admin})
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+
+	// a draw offer that wasn't agreed to yet leaves the game going
+	status := http.StatusOK
+	if a.WasDrawn() && !g.Over() {
+		status = http.StatusAccepted
+	}
+	writeJSON(w, status, g)
+}
+
+// role is who a request acts for, a player, or nobody in particular, and whether it's an admin.
+type role struct {
+	color uint8
+	admin bool
+}
+
+// perform plays the action and saves the game, it returns the game as it is after the action. Both the HTTP and the
+// WebSocket endpoints act through here so that the game and the store never disagree. The action is played on behalf
+// of the role, see permitted.
+//
+// A draw is agreed to by both players. The first player to ask for one only offers it, the game is drawn once their
+// opponent asks as well, and the offer lapses with the next action played on the board. An admin draws the game
+// outright.
+func (s *Server) perform(e *entry, a hive.Action, r role) (*game.Game, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := permitted(a, r, e.session.Turn()); err != nil {
+		return nil, err
+	}
+	if a.WasDrawn() && !r.admin {
+		if e.session.Over() {
+			return nil, ErrGameOver
+		}
+		if e.drawOffer == hive.NoColor || e.drawOffer == r.color {
+			e.drawOffer = r.color
+			return e.session.Snapshot(), nil
+		}
+	}
+
+	// an action refused because the player ran out of time still records the timeout, so everything the action added
+	// to the history is stored whether it was refused or not
+	before := len(e.session.History())
+	played := e.session.Play(a)
+	// the store plays each action at the time the session recorded so that the clocks of both agree
+	for i, recorded := range e.session.History()[before:] {
+		at, _ := e.session.PlayedAt(before + i)
+		if err := s.store.AppendAction(e.id, recorded, at); err != nil {
+			// the game in memory is now ahead of the store, forget it so the next request picks up what was stored
+			s.forget(e.id)
+			return nil, err
+		}
+	}
+	if played != nil {
+		return nil, played
+	}
+	e.drawOffer = hive.NoColor
+	return e.session.Snapshot(), nil
+}
+
+// permitted checks that the role may perform the action. A player acts on the board on their turn, may resign or offer
+// a draw for themselves, and may claim a timeout at any time, the engine checks that the clock agrees. A request that
+// doesn't name a player may act on the board for the player whose turn it is and claim a timeout, only an admin may
+// adjudicate, and an admin may do anything.
+func permitted(a hive.Action, r role, turn uint8) error {
+	switch {
+	case r.admin:
+		return nil
+	case a.WasAdjudicated():
+		return ErrNotPermitted
+	case (a.WasResigned() || a.WasDrawn()) && r.color == hive.NoColor:
+		return ErrNotPermitted
+	case a.WasResigned() && a.Piece().Color() != r.color:
+		return ErrNotPermitted
+	case !a.Concludes() && r.color != hive.NoColor && r.color != turn:
+		return ErrNotPlayersTurn
+	}
+	return nil
+}
+
+// lookup returns the game from memory, loading it from the store when it isn't there yet.
+func (s *Server) lookup(id string) (*entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.games[id]; ok {
+		return e, nil
+	}
+
+	g, err := s.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	// a timed game keeps running on the clock of the server
+	g.SetClock(s.Clock)
+	e := &entry{id: id, session: game.NewSession(g), drawOffer: hive.NoColor}
+	s.games[id] = e
+	return e, nil
+}
+
+func (s *Server) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.games, id)
+}
+
+type createRequest struct {
+	Features []string       `json:"features"`
+	Clock    *clockRequest  `json:"clock"`
+	Handicap *game.Handicap `json:"handicap"`
+}
+
+// clockRequest describes a time control with durations such as "5m" or "10s", Increment and Delay are optional.
+type clockRequest struct {
+	Base      string `json:"base"`
+	Increment string `json:"increment"`
+	Delay     string `json:"delay"`
+}
+
+func (r *clockRequest) control() (game.TimeControl, error) {
+	var tc game.TimeControl
+	for _, d := range []struct {
+		s string
+		d *time.Duration
+	}{{r.Base, &tc.Base}, {r.Increment, &tc.Increment}, {r.Delay, &tc.Delay}} {
+		if d.s == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.s)
+		if err != nil {
+			return tc, fmt.Errorf("%w: %s", ErrInvalidRequest, err)
+		}
+		*d.d = v
+	}
+	return tc, nil
+}
+
+type createResponse struct {
+	ID   string     `json:"id"`
+	Game *game.Game `json:"game"`
+}
+
+type listResponse struct {
+	Games []string `json:"games"`
+}
+
+type actionsResponse struct {
+	Actions []hive.Action `json:"actions"`
+}
+
+type historyResponse struct {
+	History []hive.Action `json:"history"`
+}
+
+type errorResponse struct {
+	Error string      `json:"error"`
+	Rule  game.RuleID `json:"rule,omitempty"`
+}
+
+// decode reads the JSON body of the request into v, an empty body leaves v untouched.
+func decode(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	if err := dec.Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("%w: %s", ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+// statusOf returns the HTTP status that describes the error of an action.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrGameOver) || errors.Is(err, ErrNotPlayersTurn) || errors.Is(err, game.ErrTimeRemaining):
+		return http.StatusConflict
+	case game.RuleOf(err) != game.NoRule:
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrNotPermitted):
+		return http.StatusForbidden
+	case errors.Is(err, hive.ErrInvalidCoordinate) || errors.Is(err, game.ErrUnknownAction) ||
+		errors.Is(err, game.ErrUnknownColor) || errors.Is(err, game.ErrUnknownWinner):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error(), Rule: game.RuleOf(err)})
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func nonNil(actions []hive.Action) []hive.Action {
+	if actions == nil {
+		return []hive.Action{}
+	}
+	return actions
+}
+
+const maxBodySize = 1 << 20
+
+var (
+	ErrUnknownEndpoint  = fmt.Errorf("the endpoint does not exist")
+	ErrMethodNotAllowed = fmt.Errorf("the method is not allowed for the endpoint")
+	ErrInvalidRequest   = fmt.Errorf("the request body is invalid")
+	ErrGameOver         = game.ErrGameOver
+	ErrNotPlayersTurn   = fmt.Errorf("only the player whose turn it is may act")
+	ErrNotPermitted     = fmt.Errorf("players may only resign or offer a draw for themselves, and only an admin may adjudicate")
+)
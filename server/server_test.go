@@ -0,0 +1,279 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+	"github.com/theshadow/hive/store"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("When a game is created it can be retrieved and listed", func(t *testing.T) {
+		srv := New(nil)
+		id := create(t, srv, `{"features":["PillBug"]}`)
+
+		var g struct {
+			Features []string `json:"features"`
+			Turn     string   `json:"turn"`
+		}
+		if status := do(t, srv, http.MethodGet, "/games/"+id, "", &g); status != http.StatusOK {
+			t.Fatalf("Expected the status %d instead received %d", http.StatusOK, status)
+		}
+		if len(g.Features) != 1 || g.Features[0] != "PillBug" || g.Turn != "White" {
+			t.Errorf("Unexpected game %#v", g)
+		}
+
+		var list listResponse
+		do(t, srv, http.MethodGet, "/games", "", &list)
+		if len(list.Games) != 1 || list.Games[0] != id {
+			t.Errorf("Expected the list to contain %s instead received %v", id, list.Games)
+		}
+	})
+
+	t.Run("When creating a game with an unknown feature a bad request is returned", func(t *testing.T) {
+		srv := New(nil)
+		if status := do(t, srv, http.MethodPost, "/games", `{"features":["Dragonfly"]}`, nil); status != http.StatusBadRequest {
+			t.Errorf("Expected the status %d instead received %d", http.StatusBadRequest, status)
+		}
+	})
+
+	t.Run("When a legal action is submitted it is added to the history", func(t *testing.T) {
+		srv := New(nil)
+		id := create(t, srv, "")
+
+		var legal actionsResponse
+		do(t, srv, http.MethodGet, "/games/"+id+"/actions", "", &legal)
+		if len(legal.Actions) != 5 {
+			t.Fatalf("Expected 5 legal actions instead received %d", len(legal.Actions))
+		}
+
+		body, _ := json.Marshal(legal.Actions[0])
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions", string(body), nil); status != http.StatusOK {
+			t.Fatalf("Expected the status %d instead received %d", http.StatusOK, status)
+		}
+
+		var history historyResponse
+		do(t, srv, http.MethodGet, "/games/"+id+"/history", "", &history)
+		if len(history.History) != 1 || history.History[0] != legal.Actions[0] {
+			t.Errorf("Expected the history to contain %s instead received %v", legal.Actions[0], history.History)
+		}
+	})
+
+	t.Run("When an action breaks a rule the rule is returned", func(t *testing.T) {
+		srv := New(nil)
+		id := create(t, srv, "")
+
+		a := hive.NewAction(hive.Placed, hive.NewPiece(hive.BlackColor, hive.Queen, hive.PieceA), 0, hive.Origin)
+		body, _ := json.Marshal(a)
+		var resp errorResponse
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions", string(body), &resp); status != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected the status %d instead received %d", http.StatusUnprocessableEntity, status)
+		}
+		if resp.Rule != game.RuleNotPlayersTurn {
+			t.Errorf("Expected the rule %s instead received %s", game.RuleNotPlayersTurn, resp.Rule)
+		}
+	})
+
+	t.Run("When a game is only in the store it is loaded", func(t *testing.T) {
+		gs := store.NewMemory()
+		g := game.New(nil)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := gs.Save("stored", g); err != nil {
+			t.Fatalf("Unexpected error %#v while saving the game", err)
+		}
+
+		var history historyResponse
+		if status := do(t, New(gs), http.MethodGet, "/games/stored/history", "", &history); status != http.StatusOK {
+			t.Fatalf("Expected the status %d instead received %d", http.StatusOK, status)
+		}
+		if len(history.History) != 1 {
+			t.Errorf("Expected a history of 1 action instead received %d", len(history.History))
+		}
+	})
+
+	t.Run("When a game is created with a clock the remaining time is part of the state", func(t *testing.T) {
+		srv := New(nil)
+		clock := game.NewManualClock(time.Unix(0, 0))
+		srv.Clock = clock
+		id := create(t, srv, `{"clock":{"base":"5m","increment":"2s"}}`)
+
+		clock.Advance(10 * time.Second)
+		body, _ := json.Marshal(hive.NewAction(hive.Placed, hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), 0, hive.Origin))
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions", string(body), nil); status != http.StatusOK {
+			t.Fatalf("Expected the status %d instead received %d", http.StatusOK, status)
+		}
+
+		var g struct {
+			Clock game.ClockState `json:"clock"`
+		}
+		do(t, srv, http.MethodGet, "/games/"+id, "", &g)
+		if g.Clock.White != 292*time.Second || g.Clock.Black != 5*time.Minute || g.Clock.Running != hive.BlackColor {
+			t.Errorf("Expected white 4m52s and black 5m with blacks clock running instead received %#v", g.Clock)
+		}
+	})
+
+	t.Run("When a game is created with a handicap the player keeps the turn for their extra placement", func(t *testing.T) {
+		srv := New(nil)
+		id := create(t, srv, `{"handicap":{"white":{"placements":1},"black":{"removed":{"Ant":1}}}}`)
+
+		body, _ := json.Marshal(hive.NewAction(hive.Placed, hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), 0, hive.Origin))
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions", string(body), nil); status != http.StatusOK {
+			t.Fatalf("Expected the status %d instead received %d", http.StatusOK, status)
+		}
+
+		var g struct {
+			Turn     string        `json:"turn"`
+			Handicap game.Handicap `json:"handicap"`
+		}
+		do(t, srv, http.MethodGet, "/games/"+id, "", &g)
+		if g.Turn != "White" || g.Handicap.Black.Removed.Count(hive.Ant) != 1 {
+			t.Errorf("Expected white to still be playing a handicap game instead received %#v", g)
+		}
+	})
+
+	t.Run("When creating a game with a handicap that can't be met a bad request is returned", func(t *testing.T) {
+		srv := New(nil)
+		status := do(t, srv, http.MethodPost, "/games", `{"handicap":{"black":{"queenTurn":2}}}`, nil)
+		if status != http.StatusBadRequest {
+			t.Errorf("Expected the status %d instead received %d", http.StatusBadRequest, status)
+		}
+	})
+
+	t.Run("When a player resigns the result is part of the state and the history is stored", func(t *testing.T) {
+		gs := store.NewMemory()
+		srv := New(gs)
+		id := create(t, srv, "")
+
+		body, _ := json.Marshal(hive.NewAction(hive.Resigned, hive.NewPiece(hive.BlackColor, hive.NoBug, hive.NoPiece), 0, 0))
+		var g struct {
+			Over   bool        `json:"over"`
+			Result game.Result `json:"result"`
+		}
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions?player=Black", string(body), &g); status != http.StatusOK {
+			t.Fatalf("Expected the status %d instead received %d", http.StatusOK, status)
+		}
+		if !g.Over || g.Result != (game.Result{Winner: game.WhitePlayer, Reason: game.Resignation}) {
+			t.Errorf("Expected white to win by resignation instead received %#v", g)
+		}
+
+		stored, err := gs.Load(id)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while loading the game", err)
+		}
+		if r, err := stored.Result(); err != nil || r.Reason != game.Resignation {
+			t.Errorf("Expected the stored game to be resigned instead received %s, %v", r, err)
+		}
+
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions?player=White", `{"act":"Drawn"}`, nil); status != http.StatusConflict {
+			t.Errorf("Expected the status %d instead received %d", http.StatusConflict, status)
+		}
+	})
+
+	t.Run("When a player resigns for their opponent it is not permitted", func(t *testing.T) {
+		srv := New(nil)
+		id := create(t, srv, "")
+		e, _ := srv.lookup(id)
+
+		a := hive.NewAction(hive.Resigned, hive.NewPiece(hive.BlackColor, hive.NoBug, hive.NoPiece), 0, 0)
+		if _, err := srv.perform(e, a, role{color: hive.WhiteColor}); !errors.Is(err, ErrNotPermitted) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrNotPermitted, err)
+		}
+		if _, err := srv.perform(e, a, role{color: hive.BlackColor}); err != nil {
+			t.Errorf("Unexpected error %#v while black was resigning out of turn", err)
+		}
+	})
+
+	t.Run("When a request doesn't name a player it may not resign, draw, or adjudicate", func(t *testing.T) {
+		srv := New(nil)
+		id := create(t, srv, "")
+
+		for _, body := range []string{`{"act":"Resigned","color":"Black"}`, `{"act":"Drawn"}`, `{"act":"Adjudicated","color":"White"}`} {
+			if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions", body, nil); status != http.StatusForbidden {
+				t.Errorf("Expected the status %d for %s instead received %d", http.StatusForbidden, body, status)
+			}
+		}
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions?player=White", `{"act":"Adjudicated","color":"White"}`, nil); status != http.StatusForbidden {
+			t.Errorf("Expected the status %d for a player adjudicating instead received %d", http.StatusForbidden, status)
+		}
+	})
+
+	t.Run("When both players ask for a draw the game is drawn", func(t *testing.T) {
+		srv := New(nil)
+		id := create(t, srv, "")
+
+		var g struct {
+			Over   bool        `json:"over"`
+			Result game.Result `json:"result"`
+		}
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions?player=White", `{"act":"Drawn"}`, &g); status != http.StatusAccepted || g.Over {
+			t.Fatalf("Expected the offer to be accepted without ending the game instead received %d", status)
+		}
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions?player=White", `{"act":"Drawn"}`, &g); status != http.StatusAccepted || g.Over {
+			t.Fatalf("Expected a player repeating their offer not to end the game instead received %d", status)
+		}
+		if status := do(t, srv, http.MethodPost, "/games/"+id+"/actions?player=Black", `{"act":"Drawn"}`, &g); status != http.StatusOK {
+			t.Fatalf("Expected the status %d instead received %d", http.StatusOK, status)
+		}
+		if !g.Over || g.Result.Reason != game.Agreement {
+			t.Errorf("Expected the game to be drawn by agreement instead received %#v", g)
+		}
+	})
+
+	t.Run("When an admin adjudicates the game the result is declared", func(t *testing.T) {
+		srv := New(nil)
+		srv.AdminToken = "secret"
+		id := create(t, srv, "")
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/games/"+id+"/actions", bytes.NewBufferString(`{"act":"Adjudicated","color":"White"}`))
+		req.Header.Set("X-Hive-Admin", "wrong")
+		if srv.ServeHTTP(rec, req); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected the status %d for the wrong token instead received %d", http.StatusForbidden, rec.Code)
+		}
+
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPost, "/games/"+id+"/actions", bytes.NewBufferString(`{"act":"Adjudicated","color":"White"}`))
+		req.Header.Set("X-Hive-Admin", "secret")
+		if srv.ServeHTTP(rec, req); rec.Code != http.StatusOK {
+			t.Errorf("Expected the status %d instead received %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("When requesting a game that does not exist not found is returned", func(t *testing.T) {
+		if status := do(t, New(nil), http.MethodGet, "/games/missing", "", nil); status != http.StatusNotFound {
+			t.Errorf("Expected the status %d instead received %d", http.StatusNotFound, status)
+		}
+	})
+}
+
+// create creates a game and returns its identifier.
+func create(t *testing.T, srv *Server, body string) string {
+	t.Helper()
+	var resp createResponse
+	if status := do(t, srv, http.MethodPost, "/games", body, &resp); status != http.StatusCreated {
+		t.Fatalf("Expected the status %d instead received %d", http.StatusCreated, status)
+	}
+	return resp.ID
+}
+
+// do performs the request and decodes the response into v when it isn't nil.
+func do(t *testing.T, srv *Server, method, path, body string, v interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+	if v != nil {
+		if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+			t.Fatalf("Unexpected error %#v while decoding the response to %s %s", err, method, path)
+		}
+	}
+	return rec.Code
+}
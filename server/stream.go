@@ -0,0 +1,327 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// Message is exchanged over the stream endpoint of a game, GET /games/{id}/stream, which is upgraded to a WebSocket.
+//
+// A client that connects receives a snapshot of the game and then a delta for every action performed in the game, in
+// order, no matter which client or endpoint performed it. Every message about the game carries the sequence number of
+// the last action it includes, the first action of a game is 1, so a client can tell when it missed something.
+//
+// Clients may send an action message to act, or a sync message to receive a new snapshot. Only players may act, a
+// client is a player when it connects with the query ?player=White or ?player=Black, everybody else is a spectator.
+// Besides acting on their turn a player may resign, offer or accept a draw, or claim a timeout when their opponent runs
+// out of time, at any time. An offer isn't announced to the other clients, and adjudications are left to an admin on
+// the HTTP endpoint. A client that reconnects with ?since=N receives the deltas after N instead of a snapshot.
+//
+// The roles are taken at the clients word, authenticating the players is left to whatever the server is mounted
+// behind.
+type Message struct {
+	// Type is one of the Message types.
+	Type string `json:"type"`
+
+	// Sequence is the number of actions included in a snapshot or the sequence of the action of a delta.
+	Sequence int `json:"sequence,omitempty"`
+
+	// Game is the state of the game, for a snapshot.
+	Game *game.Game `json:"game,omitempty"`
+
+	// Action is the action performed, for a delta, or the action to perform, for an action.
+	Action *hive.Action `json:"action,omitempty"`
+
+	// Turn is the color of the player whose turn it is after a delta.
+	Turn string `json:"turn,omitempty"`
+
+	// Over is true when the action of a delta ended the game, Result is then how it ended.
+	Over   bool         `json:"over,omitempty"`
+	Result *game.Result `json:"result,omitempty"`
+
+	// Clock is a reading of the clocks after the action of a delta, for a timed game. Deltas sent to catch a client up
+	// don't carry the clock, the state of the clock is part of a snapshot.
+	Clock *game.ClockState `json:"clock,omitempty"`
+
+	// Error and Rule describe why an action was refused.
+	Error string      `json:"error,omitempty"`
+	Rule  game.RuleID `json:"rule,omitempty"`
+}
+
+const (
+	// MessageSnapshot is sent by the server with the full state of the game.
+	MessageSnapshot = "snapshot"
+
+	// MessageDelta is sent by the server after each action.
+	MessageDelta = "delta"
+
+	// MessageError is sent by the server when an action from the client was refused.
+	MessageError = "error"
+
+	// MessageAction is sent by a player to perform an action.
+	MessageAction = "action"
+
+	// MessageSync is sent by a client to receive a new snapshot.
+	MessageSync = "sync"
+)
+
+// stream upgrades the request to a WebSocket and serves the messages of the game until the client goes away.
+func (s *Server) stream(w http.ResponseWriter, r *http.Request, e *entry) {
+	color, err := parsePlayer(r.URL.Query().Get("player"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	since := -1
+	if v := r.URL.Query().Get("since"); v != "" {
+		if since, err = strconv.Atoi(v); err != nil || since < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: since must be a sequence number", ErrInvalidRequest))
+			return
+		}
+	}
+
+	conn, err := upgrade(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer conn.Close()
+
+	replies := make(chan Message, streamBuffer)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		(&streamWriter{conn: conn, session: e.session}).run(since, replies, done)
+		// when the writer gives up the reader is unblocked by closing the connection under it
+		conn.conn.Close()
+	}()
+	reply := func(m Message) {
+		select {
+		case replies <- m:
+		case <-finished:
+		}
+	}
+
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var m Message
+		if err := json.Unmarshal(data, &m); err != nil {
+			reply(errorMessage(fmt.Errorf("%w: %s", ErrInvalidRequest, err)))
+			continue
+		}
+
+		switch m.Type {
+		case MessageSync:
+			reply(m)
+		case MessageAction:
+			if color == hive.NoColor {
+				reply(errorMessage(ErrSpectator))
+			} else if m.Action == nil {
+				reply(errorMessage(fmt.Errorf("%w: the action is missing", ErrInvalidRequest)))
+			} else if _, err := s.perform(e, *m.Action, role{color: color}); err != nil {
+				reply(errorMessage(err))
+			}
+		default:
+			reply(errorMessage(fmt.Errorf("%w: unknown message type %q", ErrInvalidRequest, m.Type)))
+		}
+	}
+
+	close(done)
+	<-finished
+}
+
+// streamWriter is the only goroutine that writes to a stream, which is what keeps the messages in order.
+type streamWriter struct {
+	conn    *wsConn
+	session *game.Session
+
+	// sequence of the last action the client knows about
+	sequence int
+}
+
+func (sw *streamWriter) run(since int, replies <-chan Message, done <-chan struct{}) {
+	// subscribing before catching the client up means no action can fall between the two, anything that is delivered
+	// twice is skipped by its sequence.
+	updates, cancel := sw.session.Subscribe(streamBuffer)
+	defer func() { cancel() }()
+
+	if err := sw.catchUp(since); err != nil {
+		return
+	}
+
+	for {
+		var err error
+		select {
+		case <-done:
+			return
+		case m := <-replies:
+			if m.Type == MessageSync {
+				err = sw.snapshot()
+			} else {
+				err = sw.send(m)
+			}
+		case u, ok := <-updates:
+			if !ok {
+				// the client fell too far behind and the session dropped it, start over from a snapshot
+				updates, cancel = sw.session.Subscribe(streamBuffer)
+				err = sw.snapshot()
+			} else if u.Sequence > sw.sequence {
+				sw.sequence = u.Sequence
+				m := delta(u.Sequence, u.Action, u.Turn, u.Over)
+				m.Result = u.Result
+				m.Clock = u.Clock
+				err = sw.send(m)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// catchUp sends the deltas after since when the client already knows the game, otherwise a snapshot.
+func (sw *streamWriter) catchUp(since int) error {
+	g := sw.session.Snapshot()
+	history := g.History()
+	if since < 0 || since > len(history) {
+		return sw.send(snapshot(g))
+	}
+
+	// players don't always alternate, the extra placements of a handicap keep the turn, so the history is replayed
+	// to learn whose turn it was after each action
+	replay := g.Initial()
+	for i, a := range history {
+		if err := replay.Play(a); err != nil {
+			return sw.send(snapshot(g))
+		}
+		if i < since {
+			continue
+		}
+		m := delta(i+1, a, replay.Turn(), false)
+		if i+1 == len(history) {
+			if r, err := g.Result(); err == nil {
+				m.Over, m.Result = true, &r
+			}
+		}
+		if err := sw.send(m); err != nil {
+			return err
+		}
+	}
+	sw.sequence = len(history)
+	return nil
+}
+
+func (sw *streamWriter) snapshot() error {
+	g := sw.session.Snapshot()
+	sw.sequence = len(g.History())
+	return sw.send(snapshot(g))
+}
+
+func (sw *streamWriter) send(m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return sw.conn.WriteMessage(data)
+}
+
+func snapshot(g *game.Game) Message {
+	return Message{Type: MessageSnapshot, Sequence: len(g.History()), Game: g}
+}
+
+func delta(sequence int, a hive.Action, turn uint8, over bool) Message {
+	return Message{
+		Type:     MessageDelta,
+		Sequence: sequence,
+		Action:   &a,
+		Turn:     hive.NewPiece(turn, hive.NoBug, hive.NoPiece).ColorS(),
+		Over:     over,
+	}
+}
+
+func errorMessage(err error) Message {
+	return Message{Type: MessageError, Error: err.Error(), Rule: game.RuleOf(err)}
+}
+
+// parsePlayer returns the color of the player, or NoColor for a spectator.
+func parsePlayer(s string) (uint8, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return hive.NoColor, nil
+	case "white":
+		return hive.WhiteColor, nil
+	case "black":
+		return hive.BlackColor, nil
+	}
+	return hive.NoColor, fmt.Errorf("%w: unknown player %q", ErrInvalidRequest, s)
+}
+
+// Client is a connection to the stream endpoint of a game, it's used by Go programs that want to play or watch a game
+// hosted by a Server, and by the tests.
+type Client struct {
+	conn *wsConn
+}
+
+// Dial connects to the stream endpoint, the url is the ws:// form of the endpoint including any query.
+func Dial(url string) (*Client, error) {
+	conn, err := dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Receive blocks until the next message from the server arrives.
+func (c *Client) Receive() (Message, error) {
+	var m Message
+	data, err := c.conn.ReadMessage()
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// Act asks the server to perform the action, a refusal arrives as an error message.
+func (c *Client) Act(a hive.Action) error {
+	return c.send(Message{Type: MessageAction, Action: &a})
+}
+
+// Resign concedes the game for the player the client is connected as.
+func (c *Client) Resign(color uint8) error {
+	return c.Act(hive.NewAction(hive.Resigned, hive.NewPiece(color, hive.NoBug, hive.NoPiece), 0, 0))
+}
+
+// Sync asks the server for a new snapshot.
+func (c *Client) Sync() error {
+	return c.send(Message{Type: MessageSync})
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) send(m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(data)
+}
+
+// streamBuffer is the number of messages that may be waiting for a client before it's considered too slow.
+const streamBuffer = 64
+
+var ErrSpectator = fmt.Errorf("spectators may not act, connect as a player to play")
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+func TestServer_Stream(t *testing.T) {
+	first := hive.NewAction(hive.Placed, hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), 0, hive.Origin)
+	second := hive.NewAction(hive.Placed, hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA), 0,
+		hive.NewCoordinate(0, 1, -1, 0))
+
+	t.Run("When a player acts every client receives the delta", func(t *testing.T) {
+		srv, url := streamServer(t)
+		white := connect(t, url+"?player=white")
+		spectator := connect(t, url)
+		for _, c := range []*Client{white, spectator} {
+			if m := receive(t, c); m.Type != MessageSnapshot || m.Sequence != 0 {
+				t.Fatalf("Expected a snapshot of sequence 0 instead received %#v", m)
+			}
+		}
+
+		if err := white.Act(first); err != nil {
+			t.Fatalf("Unexpected error %#v while acting", err)
+		}
+		for _, c := range []*Client{white, spectator} {
+			m := receive(t, c)
+			if m.Type != MessageDelta || m.Sequence != 1 || m.Action == nil || *m.Action != first || m.Turn != "Black" {
+				t.Errorf("Expected the delta of the first action instead received %#v", m)
+			}
+		}
+
+		var history historyResponse
+		do(t, srv, "GET", "/games/"+gameID(url)+"/history", "", &history)
+		if len(history.History) != 1 {
+			t.Errorf("Expected the action to be visible over HTTP instead the history is %v", history.History)
+		}
+	})
+
+	t.Run("When a spectator acts an error is returned", func(t *testing.T) {
+		_, url := streamServer(t)
+		spectator := connect(t, url)
+		receive(t, spectator)
+
+		if err := spectator.Act(first); err != nil {
+			t.Fatalf("Unexpected error %#v while acting", err)
+		}
+		if m := receive(t, spectator); m.Type != MessageError || m.Error != ErrSpectator.Error() {
+			t.Errorf("Expected an error message instead received %#v", m)
+		}
+	})
+
+	t.Run("When a player acts out of turn an error is returned", func(t *testing.T) {
+		_, url := streamServer(t)
+		black := connect(t, url+"?player=black")
+		receive(t, black)
+
+		if err := black.Act(second); err != nil {
+			t.Fatalf("Unexpected error %#v while acting", err)
+		}
+		if m := receive(t, black); m.Type != MessageError || m.Error != ErrNotPlayersTurn.Error() {
+			t.Errorf("Expected an error message instead received %#v", m)
+		}
+	})
+
+	t.Run("When an action breaks a rule the rule is returned", func(t *testing.T) {
+		_, url := streamServer(t)
+		white := connect(t, url+"?player=white")
+		receive(t, white)
+
+		if err := white.Act(hive.NewAction(hive.Placed, first.Piece(), 0, hive.NewCoordinate(0, 1, -1, 0))); err != nil {
+			t.Fatalf("Unexpected error %#v while acting", err)
+		}
+		if m := receive(t, white); m.Type != MessageError || m.Rule != game.RuleFirstPieceMustBeAtOrigin {
+			t.Errorf("Expected an error message for the rule %s instead received %#v", game.RuleFirstPieceMustBeAtOrigin, m)
+		}
+	})
+
+	t.Run("When a client reconnects it receives the deltas it missed", func(t *testing.T) {
+		_, url := streamServer(t)
+		white := connect(t, url+"?player=white")
+		black := connect(t, url+"?player=black")
+		receive(t, white)
+		receive(t, black)
+
+		if err := white.Act(first); err != nil {
+			t.Fatalf("Unexpected error %#v while acting", err)
+		}
+		receive(t, white)
+		receive(t, black)
+		black.Close()
+
+		black = connect(t, url+"?player=black&since=1")
+		if err := black.Act(second); err != nil {
+			t.Fatalf("Unexpected error %#v while acting", err)
+		}
+		if m := receive(t, black); m.Type != MessageDelta || m.Sequence != 2 {
+			t.Errorf("Expected the delta of the second action instead received %#v", m)
+		}
+	})
+
+	t.Run("When a client reconnects to a handicap game the deltas it missed have the turn of the game", func(t *testing.T) {
+		srv := New(nil)
+		id := create(t, srv, `{"handicap": {"white": {"placements": 1}}}`)
+		ts := httptest.NewServer(srv)
+		t.Cleanup(ts.Close)
+		url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/games/" + id + "/stream"
+
+		white := connect(t, url+"?player=white")
+		receive(t, white)
+		extra := hive.NewAction(hive.Placed, hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceB), 0, hive.NewCoordinate(0, 1, -1, 0))
+		for _, a := range []hive.Action{first, extra} {
+			if err := white.Act(a); err != nil {
+				t.Fatalf("Unexpected error %#v while acting", err)
+			}
+			receive(t, white)
+		}
+
+		spectator := connect(t, url+"?since=0")
+		for i, turn := range []string{"White", "Black"} {
+			if m := receive(t, spectator); m.Type != MessageDelta || m.Sequence != i+1 || m.Turn != turn {
+				t.Errorf("Expected the delta of action %d with the turn of %s instead received %#v", i+1, turn, m)
+			}
+		}
+	})
+
+	t.Run("When a client asks to sync a snapshot is returned", func(t *testing.T) {
+		_, url := streamServer(t)
+		white := connect(t, url+"?player=white")
+		receive(t, white)
+		if err := white.Act(first); err != nil {
+			t.Fatalf("Unexpected error %#v while acting", err)
+		}
+		receive(t, white)
+
+		if err := white.Sync(); err != nil {
+			t.Fatalf("Unexpected error %#v while syncing", err)
+		}
+		m := receive(t, white)
+		if m.Type != MessageSnapshot || m.Sequence != 1 || m.Game == nil || len(m.Game.History()) != 1 {
+			t.Errorf("Expected a snapshot of sequence 1 instead received %#v", m)
+		}
+	})
+}
+
+// streamServer starts a server with a single game and returns the ws:// url of its stream.
+func streamServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	srv := New(nil)
+	id := create(t, srv, "")
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return srv, "ws" + strings.TrimPrefix(ts.URL, "http") + "/games/" + id + "/stream"
+}
+
+func gameID(url string) string {
+	parts := strings.Split(url, "/")
+	return parts[len(parts)-2]
+}
+
+func connect(t *testing.T, url string) *Client {
+	t.Helper()
+	c, err := Dial(url)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v while connecting to %s", err, url)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func receive(t *testing.T, c *Client) Message {
+	t.Helper()
+	m, err := c.Receive()
+	if err != nil {
+		t.Fatalf("Unexpected error %#v while receiving a message", err)
+	}
+	return m
+}
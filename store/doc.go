@@ -0,0 +1,32 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package store persists games so that they survive a restart of whatever is hosting them.
+
+A game is fully described by its features and its history, so the stores are event sourced. Instead of writing the
+whole game after every change an action is appended to the game with AppendAction, and loading a game replays its
+actions through the rules engine. A history that breaks the rules can't be loaded. The action of a timed game is
+appended with the time it was performed, the stored game plays it at that time so that its clocks never disagree with
+those of the game the action came from.
+
+Implementations
+
+Memory keeps the games in memory and is meant for tests and for servers that don't need to survive a restart.
+
+FileLog keeps an append-only log per game in a directory. The first line of a log holds the features of the game and
+every following line holds one action, both as JSON:
+
+	{"features":["PillBug"]}
+	{"act":"Placed","piece":{"color":"White","bug":"Ant","piece":"Piece A"},"dst":{"x":0,"y":0,"z":0,"h":0}}
+
+The header of a timed game also holds its time control and when its clock was started, and every action holds the
+time it was performed. Loading the game replays each action at its time, so the clocks are rebuilt as they were and
+the time that passed while the game was stored counts against the player whose turn it is:
+
+	{"features":[],"clock":{"base_ms":600000,"started":"2020-06-01T12:00:00Z"}}
+	{"act":"Placed","piece":{...},"dst":{...},"at":"2020-06-01T12:00:04Z"}
+
+An action that was only partially written when the process died is ignored when the log is loaded.
+*/
+package store
@@ -0,0 +1,353 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// FileLog is a GameStore that keeps an append-only log of actions per game in a directory, see the package
+// documentation for the format.
+//
+// The store remembers the games it has loaded so that an action can be checked against the rules before it's written,
+// the logs never contain an action the engine would refuse. Only one FileLog should use a directory at a time.
+//
+// The actions of a timed game are logged with the time they were performed, and loading the game replays them at
+// those times so that the clocks come back as they were, including the time that passed since the last action.
+type FileLog struct {
+	// Clock is handed to the timed games that are replayed from their logs, it defaults to the wall clock and may be
+	// replaced before the store is used.
+	Clock game.Clock
+
+	dir string
+
+	mu    sync.Mutex
+	games map[string]*game.Game
+}
+
+// NewFileLog creates a store in the directory, creating the directory when it doesn't exist.
+func NewFileLog(dir string) (*FileLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileLog{Clock: game.SystemClock{}, dir: dir, games: make(map[string]*game.Game)}, nil
+}
+
+// Save replaces the log of the game with one holding its features and history.
+func (s *FileLog) Save(id string, g *game.Game) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := writeHeader(&buf, g); err != nil {
+		return err
+	}
+	for i, a := range g.History() {
+		at, _ := g.PlayedAt(i)
+		if err := writeAction(&buf, a, at); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// write to the side and rename so that a crash never leaves a half written log in place of a good one
+	tmp, err := os.CreateTemp(s.dir, id+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path(id)); err != nil {
+		return err
+	}
+
+	s.games[id] = g.Clone()
+	return nil
+}
+
+// Load replays the log of the game.
+func (s *FileLog) Load(id string) (*game.Game, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	return g.Clone(), nil
+}
+
+func (s *FileLog) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, e := range entries {
+		if id := strings.TrimSuffix(e.Name(), logExtension); !e.IsDir() && id != e.Name() && validateID(id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// AppendAction checks the action against the game, played at the time, and appends it to the log. An action that
+// breaks the rules is refused with its rule error and isn't written.
+func (s *FileLog) AppendAction(id string, a hive.Action, at time.Time) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, err := s.load(id)
+	if err != nil {
+		return err
+	}
+	next := g.Clone()
+	if err := next.PlayAt(a, at); err != nil {
+		return err
+	}
+
+	// the time the action is logged with is the one the clock recorded, none for an action of an untimed game
+	at, _ = next.PlayedAt(len(next.History()) - 1)
+	var buf bytes.Buffer
+	if err := writeAction(&buf, a, at); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path(id), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		// the log may now end in part of the action, loading it again will drop it
+		delete(s.games, id)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	s.games[id] = next
+	return nil
+}
+
+// load returns the remembered game, replaying the log when there isn't one. It expects the lock to be held.
+func (s *FileLog) load(id string) (*game.Game, error) {
+	if g, ok := s.games[id]; ok {
+		return g, nil
+	}
+
+	data, err := os.ReadFile(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrGameNotFound, id)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Anything after the last newline is an action that was being written when the process died. It's cut off so
+	// that the next action starts on a line of its own.
+	if end := bytes.LastIndexByte(data, '\n') + 1; end != len(data) {
+		if err := os.Truncate(s.path(id), int64(end)); err != nil {
+			return nil, err
+		}
+		data = data[:end]
+	}
+
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	var h header
+	if err := json.Unmarshal(lines[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: %s: the header is invalid: %s", ErrCorruptLog, id, err)
+	}
+
+	features := make([]game.Feature, 0, len(h.Features))
+	for _, name := range h.Features {
+		f, err := game.ParseFeature(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrCorruptLog, id, err)
+		}
+		features = append(features, f)
+	}
+
+	white, black := hive.StandardInventory, hive.StandardInventory
+	if h.White != nil {
+		white = *h.White
+	}
+	if h.Black != nil {
+		black = *h.Black
+	}
+	g := game.New(features, white, black)
+	if h.Handicap != nil {
+		if err := g.ApplyHandicap(*h.Handicap); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrCorruptLog, id, err)
+		}
+	}
+
+	// A timed game is replayed on a clock that is set to the time of each action, the clock is started before the
+	// first action that has a time, or after the last action when none has.
+	var clock *game.ManualClock
+	startClock := func() error {
+		clock = game.NewManualClock(h.Clock.Started)
+		if err := g.StartClock(h.Clock.control(), clock); err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrCorruptLog, id, err)
+		}
+		return nil
+	}
+	for i, line := range lines[1:] {
+		var a hive.Action
+		var stamp struct {
+			At *time.Time `json:"at"`
+		}
+		if err := json.Unmarshal(line, &a); err != nil {
+			return nil, fmt.Errorf("%w: %s: action %d is invalid: %s", ErrCorruptLog, id, i+1, err)
+		}
+		if err := json.Unmarshal(line, &stamp); err != nil {
+			return nil, fmt.Errorf("%w: %s: action %d is invalid: %s", ErrCorruptLog, id, i+1, err)
+		}
+
+		if stamp.At != nil && h.Clock != nil && clock == nil {
+			if err := startClock(); err != nil {
+				return nil, err
+			}
+		}
+		if clock != nil {
+			if stamp.At == nil {
+				return nil, fmt.Errorf("%w: %s: action %d has no time", ErrCorruptLog, id, i+1)
+			}
+			clock.Advance(stamp.At.Sub(clock.Now()))
+		}
+
+		if err := g.Play(a); err != nil {
+			return nil, fmt.Errorf("%w: %s: action %d: %s", ErrCorruptLog, id, i+1, err)
+		}
+	}
+	if h.Clock != nil && clock == nil {
+		if err := startClock(); err != nil {
+			return nil, err
+		}
+	}
+	// from here on the clock runs on the time of the store, the time since the last action counts against the
+	// player whose turn it is
+	g.SetClock(s.Clock)
+
+	s.games[id] = g
+	return g, nil
+}
+
+func (s *FileLog) path(id string) string {
+	return filepath.Join(s.dir, id+logExtension)
+}
+
+// header is the first line of a log.
+type header struct {
+	Features []string `json:"features"`
+
+	// the inventories that aren't the standard set and the handicap, see game.New and game.ApplyHandicap
+	White    *hive.Inventory `json:"white,omitempty"`
+	Black    *hive.Inventory `json:"black,omitempty"`
+	Handicap *game.Handicap  `json:"handicap,omitempty"`
+
+	// the time control of a timed game, see game.StartClock
+	Clock *logClock `json:"clock,omitempty"`
+}
+
+// logClock is the time control of a timed game and when its clock was started, the durations are in milliseconds as
+// in the encoding of game.ClockState.
+type logClock struct {
+	Base      int64     `json:"base_ms"`
+	Increment int64     `json:"increment_ms,omitempty"`
+	Delay     int64     `json:"delay_ms,omitempty"`
+	Started   time.Time `json:"started"`
+}
+
+func (c *logClock) control() game.TimeControl {
+	return game.TimeControl{
+		Base:      time.Duration(c.Base) * time.Millisecond,
+		Increment: time.Duration(c.Increment) * time.Millisecond,
+		Delay:     time.Duration(c.Delay) * time.Millisecond,
+	}
+}
+
+func writeHeader(buf *bytes.Buffer, g *game.Game) error {
+	h := header{Features: []string{}}
+	for _, f := range g.Features() {
+		h.Features = append(h.Features, f.String())
+	}
+	if inv := g.Inventory(hive.WhiteColor); inv != hive.StandardInventory {
+		h.White = &inv
+	}
+	if inv := g.Inventory(hive.BlackColor); inv != hive.StandardInventory {
+		h.Black = &inv
+	}
+	if handicap := g.Handicap(); !handicap.IsZero() {
+		h.Handicap = &handicap
+	}
+	if cs, ok := g.Clock(); ok {
+		started, _, _ := g.Timestamps()
+		h.Clock = &logClock{
+			Base:      cs.Control.Base.Milliseconds(),
+			Increment: cs.Control.Increment.Milliseconds(),
+			Delay:     cs.Control.Delay.Milliseconds(),
+			Started:   started,
+		}
+	}
+	return json.NewEncoder(buf).Encode(h)
+}
+
+// writeAction writes the action as a line of the log. The time of an action of a timed game is added to the fields of
+// the action, the lines of an untimed game are plain actions.
+func writeAction(buf *bytes.Buffer, a hive.Action, at time.Time) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	if !at.IsZero() {
+		stamp, err := json.Marshal(at)
+		if err != nil {
+			return err
+		}
+		data = append(data[:len(data)-1], `,"at":`...)
+		data = append(data, stamp...)
+		data = append(data, '}')
+	}
+	buf.Write(data)
+	return buf.WriteByte('\n')
+}
+
+const logExtension = ".log"
+
+var ErrCorruptLog = fmt.Errorf("the log of the game is corrupt")
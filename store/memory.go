@@ -0,0 +1,71 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// Memory is a GameStore that keeps the games in memory, the games are gone once the process exits. The store keeps
+// its own copies of the games, changes made to a game after it was saved or loaded are never seen by the store.
+type Memory struct {
+	mu    sync.Mutex
+	games map[string]*game.Game
+}
+
+func NewMemory() *Memory {
+	return &Memory{games: make(map[string]*game.Game)}
+}
+
+func (s *Memory) Save(id string, g *game.Game) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.games[id] = g.Clone()
+	return nil
+}
+
+func (s *Memory) Load(id string) (*game.Game, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.games[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrGameNotFound, id)
+	}
+	return g.Clone(), nil
+}
+
+func (s *Memory) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.games))
+	for id := range s.games {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// AppendAction plays the action on the stored game at the time, an action that breaks the rules is refused with its
+// rule error.
+func (s *Memory) AppendAction(id string, a hive.Action, at time.Time) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.games[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrGameNotFound, id)
+	}
+	return g.PlayAt(a, at)
+}
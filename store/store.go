@@ -0,0 +1,42 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// GameStore persists games by an identifier chosen by the caller.
+type GameStore interface {
+	// Save stores the game under the identifier, replacing whatever was stored there.
+	Save(id string, g *game.Game) error
+
+	// Load returns the game stored under the identifier, or ErrGameNotFound.
+	Load(id string) (*game.Game, error)
+
+	// List returns the identifiers of the stored games in sorted order.
+	List() ([]string, error)
+
+	// AppendAction records an action performed in a stored game. The time is when the action was performed in a timed
+	// game, see game.Game.PlayedAt, the stored game plays the action at that time so that its clocks agree with those of
+	// the game the action came from. It's the zero time for an action without one.
+	AppendAction(id string, a hive.Action, at time.Time) error
+}
+
+// validateID keeps identifiers to a safe set of characters, they end up in file names.
+func validateID(id string) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	return nil
+}
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+var (
+	ErrGameNotFound = fmt.Errorf("the game does not exist")
+	ErrInvalidID    = fmt.Errorf("the game identifier may only contain letters, digits, dashes, and underscores")
+)
@@ -0,0 +1,332 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+func TestGameStore(t *testing.T) {
+	stores := map[string]func(t *testing.T) GameStore{
+		"Memory": func(*testing.T) GameStore { return NewMemory() },
+		"FileLog": func(t *testing.T) GameStore {
+			fl, err := NewFileLog(t.TempDir())
+			if err != nil {
+				t.Fatalf("Unexpected error %#v while creating the store", err)
+			}
+			return fl
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run("When a game is saved to the "+name+" store it can be loaded", func(t *testing.T) {
+			gs := newStore(t)
+			g := opening(t)
+			if err := gs.Save("game-1", g); err != nil {
+				t.Fatalf("Unexpected error %#v while saving the game", err)
+			}
+
+			loaded, err := gs.Load("game-1")
+			if err != nil {
+				t.Fatalf("Unexpected error %#v while loading the game", err)
+			}
+			if loaded.Hash() != g.Hash() || len(loaded.History()) != len(g.History()) {
+				t.Error("Expected the loaded game to match the saved game")
+			}
+			if features := loaded.Features(); len(features) != 1 || features[0] != game.PillBugPieceFeature {
+				t.Errorf("Expected the loaded game to have the pill bug feature instead it has %v", features)
+			}
+		})
+
+		t.Run("When an action is appended in the "+name+" store it is part of the loaded game", func(t *testing.T) {
+			gs := newStore(t)
+			g := opening(t)
+			if err := gs.Save("game-1", g); err != nil {
+				t.Fatalf("Unexpected error %#v while saving the game", err)
+			}
+
+			a := g.LegalActions()[0]
+			if err := gs.AppendAction("game-1", a, time.Time{}); err != nil {
+				t.Fatalf("Unexpected error %#v while appending %s", err, a)
+			}
+
+			loaded, err := gs.Load("game-1")
+			if err != nil {
+				t.Fatalf("Unexpected error %#v while loading the game", err)
+			}
+			if history := loaded.History(); len(history) != 3 || history[2] != a {
+				t.Errorf("Expected the action to be the last of the history instead received %v", history)
+			}
+		})
+
+		t.Run("When an illegal action is appended in the "+name+" store it is refused", func(t *testing.T) {
+			gs := newStore(t)
+			if err := gs.Save("game-1", opening(t)); err != nil {
+				t.Fatalf("Unexpected error %#v while saving the game", err)
+			}
+
+			a := hive.NewAction(hive.Placed, hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceB), 0, hive.NewCoordinate(0, -2, 2, 0))
+			if err := gs.AppendAction("game-1", a, time.Time{}); !errors.Is(err, game.ErrRuleNotPlayersTurn) {
+				t.Errorf("Expected an error of type %#v instead received %#v", game.ErrRuleNotPlayersTurn, err)
+			}
+			if loaded, _ := gs.Load("game-1"); len(loaded.History()) != 2 {
+				t.Error("Expected the refused action to be left out of the history")
+			}
+		})
+
+		t.Run("When the "+name+" store lists its games they are sorted", func(t *testing.T) {
+			gs := newStore(t)
+			for _, id := range []string{"b", "a", "c"} {
+				if err := gs.Save(id, game.New(nil)); err != nil {
+					t.Fatalf("Unexpected error %#v while saving the game", err)
+				}
+			}
+			ids, err := gs.List()
+			if err != nil {
+				t.Fatalf("Unexpected error %#v while listing the games", err)
+			}
+			if len(ids) != 3 || ids[0] != "a" || ids[1] != "b" || ids[2] != "c" {
+				t.Errorf("Expected the games [a b c] instead received %v", ids)
+			}
+		})
+
+		t.Run("When loading a game that is not in the "+name+" store an error is returned", func(t *testing.T) {
+			if _, err := newStore(t).Load("missing"); !errors.Is(err, ErrGameNotFound) {
+				t.Errorf("Expected an error of type %#v instead received %#v", ErrGameNotFound, err)
+			}
+		})
+
+		t.Run("When saving a game with an unsafe identifier to the "+name+" store an error is returned", func(t *testing.T) {
+			if err := newStore(t).Save("../escape", game.New(nil)); !errors.Is(err, ErrInvalidID) {
+				t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidID, err)
+			}
+		})
+
+		t.Run("When loading or appending to a game with an unsafe identifier in the "+name+" store an error is returned", func(t *testing.T) {
+			gs := newStore(t)
+			if _, err := gs.Load("../escape"); !errors.Is(err, ErrInvalidID) {
+				t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidID, err)
+			}
+			a := hive.NewAction(hive.Passed, hive.ZeroPiece, 0, 0)
+			if err := gs.AppendAction("../escape", a, time.Time{}); !errors.Is(err, ErrInvalidID) {
+				t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidID, err)
+			}
+		})
+	}
+}
+
+func TestFileLog(t *testing.T) {
+	t.Run("When the store is reopened the games are replayed from the logs", func(t *testing.T) {
+		dir := t.TempDir()
+		fl, _ := NewFileLog(dir)
+		g := opening(t)
+		if err := fl.Save("game-1", g); err != nil {
+			t.Fatalf("Unexpected error %#v while saving the game", err)
+		}
+		if err := fl.AppendAction("game-1", g.LegalActions()[0], time.Time{}); err != nil {
+			t.Fatalf("Unexpected error %#v while appending an action", err)
+		}
+
+		reopened, _ := NewFileLog(dir)
+		loaded, err := reopened.Load("game-1")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while loading the game", err)
+		}
+		if len(loaded.History()) != 3 {
+			t.Errorf("Expected a history of 3 actions instead received %d", len(loaded.History()))
+		}
+	})
+
+	t.Run("When a handicap game is reopened the inventories and the handicap are kept", func(t *testing.T) {
+		dir := t.TempDir()
+		fl, _ := NewFileLog(dir)
+		queenAndAnt, _ := hive.NewInventory(map[uint8]int{hive.Queen: 1, hive.Ant: 1})
+		g := game.New(nil, hive.StandardInventory, queenAndAnt)
+		h := game.Handicap{White: game.Odds{Placements: 1}}
+		if err := g.ApplyHandicap(h); err != nil {
+			t.Fatalf("Unexpected error %#v while applying the handicap", err)
+		}
+		if err := fl.Save("game-1", g); err != nil {
+			t.Fatalf("Unexpected error %#v while saving the game", err)
+		}
+
+		reopened, _ := NewFileLog(dir)
+		loaded, err := reopened.Load("game-1")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while loading the game", err)
+		}
+		if loaded.Handicap() != h || loaded.Inventory(hive.BlackColor) != queenAndAnt {
+			t.Errorf("Expected the handicap %v and the inventory %s instead received %v and %s", h, queenAndAnt,
+				loaded.Handicap(), loaded.Inventory(hive.BlackColor))
+		}
+	})
+
+	t.Run("When a timed game is reopened the clocks are rebuilt from the times of the actions", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := game.NewManualClock(time.Unix(0, 0))
+		fl, _ := NewFileLog(dir)
+		g := game.New(nil)
+		if err := g.StartClock(game.Fischer(time.Minute, 5*time.Second), clock); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		clock.Advance(10 * time.Second)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := fl.Save("game-1", g); err != nil {
+			t.Fatalf("Unexpected error %#v while saving the game", err)
+		}
+		clock.Advance(20 * time.Second)
+		if err := fl.AppendAction("game-1", g.LegalActions()[0], clock.Now()); err != nil {
+			t.Fatalf("Unexpected error %#v while appending an action", err)
+		}
+
+		// the store was closed for 15s, they count against white whose turn it is
+		clock.Advance(15 * time.Second)
+		reopened, _ := NewFileLog(dir)
+		reopened.Clock = clock
+		loaded, err := reopened.Load("game-1")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while loading the game", err)
+		}
+		cs, ok := loaded.Clock()
+		if !ok || cs.White != 40*time.Second || cs.Black != 45*time.Second || cs.Running != hive.WhiteColor {
+			t.Errorf("Expected white 40s and black 45s with whites clock running instead received %#v", cs)
+		}
+
+		clock.Advance(time.Minute)
+		if !loaded.Over() {
+			t.Error("Expected the clock of the loaded game to keep running")
+		}
+	})
+
+	t.Run("When an action of a timed game is appended it is played at the time it was performed", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := game.NewManualClock(time.Unix(0, 0))
+		fl, _ := NewFileLog(dir)
+		g := game.New(nil)
+		if err := g.StartClock(game.Fischer(time.Minute, 5*time.Second), clock); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		clock.Advance(10 * time.Second)
+		if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), hive.Origin); err != nil {
+			t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+		}
+		if err := fl.Save("game-1", g); err != nil {
+			t.Fatalf("Unexpected error %#v while saving the game", err)
+		}
+
+		// the clock of the store is two minutes ahead, by it black would have run out of time
+		reopened, _ := NewFileLog(dir)
+		reopened.Clock = game.NewManualClock(time.Unix(120, 0))
+		clock.Advance(20 * time.Second)
+		a := g.LegalActions()[0]
+		if err := g.Play(a); err != nil {
+			t.Fatalf("Unexpected error %#v while black was playing", err)
+		}
+		at, _ := g.PlayedAt(1)
+		if err := reopened.AppendAction("game-1", a, at); err != nil {
+			t.Fatalf("Unexpected error %#v while appending an action", err)
+		}
+
+		again, _ := NewFileLog(dir)
+		loaded, err := again.Load("game-1")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while loading the game", err)
+		}
+		if logged, ok := loaded.PlayedAt(1); !ok || !logged.Equal(at) {
+			t.Errorf("Expected the action to be logged at %s instead received %s", at, logged)
+		}
+		if cs, ok := loaded.Clock(); !ok || cs.Black != 45*time.Second {
+			t.Errorf("Expected black to have 45s left instead received %#v", cs)
+		}
+	})
+
+	t.Run("When a timed game that ran out of time is reopened the timeout is kept", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := game.NewManualClock(time.Unix(0, 0))
+		fl, _ := NewFileLog(dir)
+		g := game.New(nil)
+		if err := g.StartClock(game.SuddenDeath(time.Minute), clock); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		if err := fl.Save("game-1", g); err != nil {
+			t.Fatalf("Unexpected error %#v while saving the game", err)
+		}
+		clock.Advance(2 * time.Minute)
+		if err := fl.AppendAction("game-1", hive.NewAction(hive.TimedOut, hive.NewPiece(hive.WhiteColor, hive.NoBug, hive.NoPiece), 0, 0), clock.Now()); err != nil {
+			t.Fatalf("Unexpected error %#v while appending the timeout", err)
+		}
+
+		reopened, _ := NewFileLog(dir)
+		reopened.Clock = clock
+		loaded, err := reopened.Load("game-1")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while loading the game", err)
+		}
+		if winner, err := loaded.Winner(); err != nil || winner != game.BlackPlayer {
+			t.Errorf("Expected black to win on time instead received %s, %v", winner, err)
+		}
+	})
+
+	t.Run("When the log ends in a partially written action the action is dropped", func(t *testing.T) {
+		dir := t.TempDir()
+		fl, _ := NewFileLog(dir)
+		if err := fl.Save("game-1", opening(t)); err != nil {
+			t.Fatalf("Unexpected error %#v while saving the game", err)
+		}
+
+		f, err := os.OpenFile(filepath.Join(dir, "game-1.log"), os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while opening the log", err)
+		}
+		f.WriteString(`{"act":"Placed","piece":{"col`)
+		f.Close()
+
+		reopened, _ := NewFileLog(dir)
+		loaded, err := reopened.Load("game-1")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while loading the game", err)
+		}
+		if err := reopened.AppendAction("game-1", loaded.LegalActions()[0], time.Time{}); err != nil {
+			t.Fatalf("Unexpected error %#v while appending an action", err)
+		}
+
+		again, _ := NewFileLog(dir)
+		if loaded, err := again.Load("game-1"); err != nil || len(loaded.History()) != 3 {
+			t.Errorf("Expected the log to be repaired instead received %v", err)
+		}
+	})
+
+	t.Run("When the log holds an illegal action an error is returned", func(t *testing.T) {
+		dir := t.TempDir()
+		log := `{"features":[]}` + "\n" +
+			`{"act":"Placed","piece":{"color":"Black","bug":"Ant","piece":"Piece A"},"dst":{"x":0,"y":0,"z":0,"h":0}}` + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "game-1.log"), []byte(log), 0o644); err != nil {
+			t.Fatalf("Unexpected error %#v while writing the log", err)
+		}
+
+		fl, _ := NewFileLog(dir)
+		if _, err := fl.Load("game-1"); !errors.Is(err, ErrCorruptLog) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrCorruptLog, err)
+		}
+	})
+}
+
+// opening returns a game with the pill bug feature where both players have placed a piece.
+func opening(t *testing.T) *game.Game {
+	t.Helper()
+	g := game.New([]game.Feature{game.PillBugPieceFeature})
+	if err := g.Place(hive.NewPiece(hive.WhiteColor, hive.Ant, hive.PieceA), hive.Origin); err != nil {
+		t.Fatalf("Unexpected error %#v while white was placing a piece", err)
+	}
+	if err := g.Place(hive.NewPiece(hive.BlackColor, hive.Ant, hive.PieceA), hive.NewCoordinate(0, -1, 1, 0)); err != nil {
+		t.Fatalf("Unexpected error %#v while black was placing a piece", err)
+	}
+	return g
+}
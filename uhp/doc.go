@@ -0,0 +1,29 @@
+// Copyright 2020 Xander Guzman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+/*
+Package uhp speaks the Universal Hive Protocol, the text protocol Hive engines and user interfaces use to talk to
+each other. It's how the engines of other projects can be played against this one.
+
+Notation
+
+A move names the piece that moves and where it goes relative to a piece already on the board. Pieces are written
+as their color, bug, and number, wQ or bA2, and the position as an indicator on the side of the reference piece the
+move ends up on:
+
+	wS1          the first piece of the game
+	bG1 -wS1     to the left of wS1
+	wQ wS1/      to the top right of wS1
+	bB1 wS1      on top of wS1
+	pass
+
+The protocol draws the hexagons with a point at the top while the board of this engine has a flat edge at the top,
+the notation turns the board a sixth so that the two agree. MoveString and ParseMove convert between actions and
+moves against a game, GameString and ParseGame convert whole games. ClockString writes the time the players of a timed
+game have left in the time format of the protocol, hh:mm:ss, the same format bestmove is given its time in.
+
+Engines
+
+Engine runs an engine program, or talks to one over any reader and writer, and asks it for moves with BestMove.
+*/
+package uhp
@@ -0,0 +1,217 @@
+package uhp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// Engine is a connection to an engine that speaks the Universal Hive Protocol. Commands are written one per line and
+// the engine answers each of them with any number of lines followed by "ok".
+//
+// An Engine may only be used by one goroutine at a time.
+type Engine struct {
+	w   io.Writer
+	cmd *exec.Cmd
+
+	lines chan string
+	done  chan struct{}
+	quit  chan struct{}
+	err   error
+
+	// stale is the number of answers still owed for commands that were given up on, they're skipped before the answer
+	// of the next command is read.
+	stale int
+
+	id    string
+	close sync.Once
+}
+
+// Limit tells an engine how long to think about its move, by Depth when it's set and by Time otherwise.
+type Limit struct {
+	Depth int
+	Time  time.Duration
+}
+
+// StartEngine runs the engine program and waits for it to introduce itself.
+func StartEngine(ctx context.Context, path string, args ...string) (*Engine, error) {
+	cmd := exec.Command(path, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	e, err := NewEngine(ctx, stdout, stdin)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, err
+	}
+	e.cmd = cmd
+	return e, nil
+}
+
+// NewEngine speaks the protocol over the reader and the writer, which is how an engine running somewhere other than
+// a local process is reached. It waits for the engine to introduce itself.
+func NewEngine(ctx context.Context, r io.Reader, w io.Writer) (*Engine, error) {
+	e := &Engine{
+		w:     w,
+		lines: make(chan string),
+		done:  make(chan struct{}),
+		quit:  make(chan struct{}),
+	}
+	go e.read(r)
+
+	// the engine answers as if it was sent the info command when it starts
+	info, err := e.answer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range info {
+		if strings.HasPrefix(line, "id ") {
+			e.id = strings.TrimPrefix(line, "id ")
+		}
+	}
+	return e, nil
+}
+
+// ID returns how the engine identified itself, its name and version.
+func (e *Engine) ID() string {
+	return e.id
+}
+
+// Command sends the command and returns the lines of the answer. An answer that starts with "err" or "invalidmove" is
+// returned as an ErrEngine.
+//
+// When the context is done before the answer arrives the command is given up on, the engine keeps working on it and
+// its answer is skipped once it arrives.
+func (e *Engine) Command(ctx context.Context, command string) ([]string, error) {
+	for e.stale > 0 {
+		if _, err := e.answer(ctx); err != nil {
+			return nil, err
+		}
+		e.stale--
+	}
+
+	if _, err := io.WriteString(e.w, command+"\n"); err != nil {
+		return nil, err
+	}
+	lines, err := e.answer(ctx)
+	if err == nil && len(lines) > 0 {
+		if first := lines[0]; strings.HasPrefix(first, "err") || strings.HasPrefix(first, "invalidmove") {
+			return nil, fmt.Errorf("%w: %s", ErrEngine, strings.Join(lines, " "))
+		}
+	}
+	if ctx.Err() != nil && err == ctx.Err() {
+		e.stale++
+	}
+	return lines, err
+}
+
+// BestMove loads the game into the engine and asks it for the best move of the player whose turn it is.
+func (e *Engine) BestMove(ctx context.Context, g *game.Game, limit Limit) (hive.Action, error) {
+	gs, err := GameString(g)
+	if err != nil {
+		return hive.Action{}, err
+	}
+	if _, err := e.Command(ctx, "newgame "+gs); err != nil {
+		return hive.Action{}, err
+	}
+
+	command := fmt.Sprintf("bestmove depth %d", limit.Depth)
+	if limit.Depth <= 0 {
+		t := limit.Time
+		if t < time.Second {
+			t = time.Second
+		}
+		command = "bestmove time " + timeString(t)
+	}
+	lines, err := e.Command(ctx, command)
+	if err != nil {
+		return hive.Action{}, err
+	}
+	if len(lines) == 0 {
+		return hive.Action{}, fmt.Errorf("%w: no move was returned", ErrEngine)
+	}
+	return ParseMove(g, lines[0])
+}
+
+// Close asks the engine to exit and waits for the program, when there is one, to end. A program that is still busy
+// after a grace period is killed.
+func (e *Engine) Close() error {
+	var err error
+	e.close.Do(func() {
+		close(e.quit)
+		_, _ = io.WriteString(e.w, "exit\n")
+		if c, ok := e.w.(io.Closer); ok {
+			_ = c.Close()
+		}
+		if e.cmd == nil {
+			return
+		}
+		exited := make(chan error, 1)
+		go func() { exited <- e.cmd.Wait() }()
+		select {
+		case err = <-exited:
+		case <-time.After(closeGrace):
+			_ = e.cmd.Process.Kill()
+			err = <-exited
+		}
+	})
+	return err
+}
+
+// answer collects the lines up to the next "ok".
+func (e *Engine) answer(ctx context.Context) ([]string, error) {
+	var lines []string
+	for {
+		select {
+		case line := <-e.lines:
+			if line == "ok" {
+				return lines, nil
+			}
+			lines = append(lines, line)
+		case <-e.done:
+			if e.err == nil {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// read hands the lines the engine writes to answer until the engine goes away.
+func (e *Engine) read(r io.Reader) {
+	defer close(e.done)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		select {
+		case e.lines <- strings.TrimSpace(scanner.Text()):
+		case <-e.quit:
+			return
+		}
+	}
+	e.err = scanner.Err()
+}
+
+// closeGrace is how long a program is given to exit before it's killed.
+const closeGrace = 5 * time.Second
+
+var ErrEngine = fmt.Errorf("the engine returned an error")
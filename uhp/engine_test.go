@@ -0,0 +1,102 @@
+package uhp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive/game"
+)
+
+func TestEngine(t *testing.T) {
+	t.Run("When asked for a move the engine loads the game and the move is parsed", func(t *testing.T) {
+		e, commands := fakeEngine(t, func(command string) string {
+			if strings.HasPrefix(command, "bestmove") {
+				return "bG1 -wS1"
+			}
+			return "Base;InProgress;Black[1];wS1"
+		})
+
+		g := game.New(nil)
+		a, _ := ParseMove(g, "wS1")
+		g.Play(a)
+
+		move, err := e.BestMove(context.Background(), g, Limit{Depth: 2})
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while asking for a move", err)
+		}
+		if m, _ := MoveString(g, move); m != "bG1 -wS1" {
+			t.Errorf("Expected the move %q instead received %q", "bG1 -wS1", m)
+		}
+		if c := <-commands; c != "newgame Base;InProgress;Black[1];wS1" {
+			t.Errorf("Expected the game to be loaded instead received %q", c)
+		}
+		if c := <-commands; c != "bestmove depth 2" {
+			t.Errorf("Expected a search to depth 2 instead received %q", c)
+		}
+		if e.ID() != "Fake 1.0" {
+			t.Errorf("Expected the engine to be identified instead received %q", e.ID())
+		}
+	})
+
+	t.Run("When the engine answers with an error it is returned", func(t *testing.T) {
+		e, _ := fakeEngine(t, func(string) string { return "err unknown command" })
+		if _, err := e.Command(context.Background(), "dance"); !errors.Is(err, ErrEngine) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrEngine, err)
+		}
+	})
+
+	t.Run("When the context is done before the answer the answer is skipped later", func(t *testing.T) {
+		release := make(chan struct{})
+		e, _ := fakeEngine(t, func(command string) string {
+			if command == "slow" {
+				<-release
+			}
+			return command
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := e.Command(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected an error of type %#v instead received %#v", context.DeadlineExceeded, err)
+		}
+		close(release)
+
+		lines, err := e.Command(context.Background(), "fast")
+		if err != nil || len(lines) != 1 || lines[0] != "fast" {
+			t.Errorf("Expected the answer to fast instead received %v, %v", lines, err)
+		}
+	})
+}
+
+// fakeEngine serves the protocol with the answer function and returns the commands it received.
+func fakeEngine(t *testing.T, answer func(command string) string) (*Engine, <-chan string) {
+	t.Helper()
+	toEngine, fromClient := io.Pipe()
+	fromEngine, toClient := io.Pipe()
+	commands := make(chan string, 16)
+
+	go func() {
+		io.WriteString(toClient, "id Fake 1.0\nMosquito;Ladybug;Pillbug\nok\n")
+		scanner := bufio.NewScanner(toEngine)
+		for scanner.Scan() {
+			if scanner.Text() == "exit" {
+				break
+			}
+			commands <- scanner.Text()
+			io.WriteString(toClient, answer(scanner.Text())+"\nok\n")
+		}
+		toClient.Close()
+	}()
+
+	e, err := NewEngine(context.Background(), fromEngine, fromClient)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v while connecting to the engine", err)
+	}
+	t.Cleanup(func() { e.Close() })
+	return e, commands
+}
@@ -0,0 +1,595 @@
+package uhp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+// PieceString returns the notation of the piece, the color, the bug, and for the bugs a player has more than one of
+// the number of the piece. For example wQ, bA2, or wM.
+func PieceString(p hive.Piece) string {
+	color := "b"
+	if p.Color() == hive.WhiteColor {
+		color = "w"
+	}
+	s := color + bugLetter(p.Bug())
+	if numbered(p.Bug()) {
+		s += strconv.Itoa(int(p.Piece()))
+	}
+	return s
+}
+
+// ParsePiece returns the piece of the notation, see PieceString.
+func ParsePiece(s string) (hive.Piece, error) {
+	if len(s) < 2 {
+		return hive.ZeroPiece, fmt.Errorf("%w: %q", ErrInvalidPiece, s)
+	}
+
+	var color uint8
+	switch s[0] {
+	case 'w':
+		color = hive.WhiteColor
+	case 'b':
+		color = hive.BlackColor
+	default:
+		return hive.ZeroPiece, fmt.Errorf("%w: %q", ErrInvalidPiece, s)
+	}
+
+	bug, ok := hive.DefaultBugs.ByLetter(s[1])
+	if !ok {
+		return hive.ZeroPiece, fmt.Errorf("%w: %q", ErrInvalidPiece, s)
+	}
+
+	number := hive.PieceA
+	if numbered(bug) {
+		n, err := strconv.Atoi(s[2:])
+		if err != nil || n < hive.PieceA || n > hive.PieceC {
+			return hive.ZeroPiece, fmt.Errorf("%w: %q", ErrInvalidPiece, s)
+		}
+		number = n
+	} else if len(s) != 2 {
+		return hive.ZeroPiece, fmt.Errorf("%w: %q", ErrInvalidPiece, s)
+	}
+	return hive.NewPiece(color, bug, uint8(number)), nil
+}
+
+// MoveString returns the notation of the action, which must be a placement, movement, throw, or pass that is about to
+// be played on the game. The destination is described relative to a piece already on the board, "wA1 -bQ" is the
+// white ant placed or moved to the left of the black queen, and a piece climbing on to a stack names the piece it's
+// climbing on to, "wB1 bQ". The very first placement of a game has no reference piece.
+//
+// A Pill Bug throw is written as a movement of the thrown piece, as the protocol doesn't distinguish the two.
+func MoveString(g *game.Game, a hive.Action) (string, error) {
+	switch {
+	case a.WasPassed():
+		return PassMove, nil
+	case a.Concludes():
+		return "", fmt.Errorf("%w: %s", ErrNotAMove, a.ActS())
+	}
+
+	piece := PieceString(a.Piece())
+	dst := a.Dst()
+	if dst.H() > 0 {
+		below, ok := g.Cell(hive.NewCoordinate(dst.X(), dst.Y(), dst.Z(), dst.H()-1))
+		if !ok {
+			return "", fmt.Errorf("%w: nothing to climb on to at %s", ErrInvalidMove, dst)
+		}
+		return piece + " " + PieceString(below), nil
+	}
+
+	for dir := hive.North; dir < hive.Above; dir++ {
+		n := dst.Add(hive.NeighborsMatrix[dir])
+		ref, ok := g.Cell(n)
+		if !ok || (a.WasMoved() || a.WasThrown()) && n == a.Src() {
+			continue
+		}
+		// the indicator describes where the destination is as seen from the reference
+		indicator := directionIndicators[(dir+3)%6]
+		if strings.HasPrefix(indicator, " ") {
+			return piece + " " + indicator[1:] + PieceString(ref), nil
+		}
+		return piece + " " + PieceString(ref) + indicator, nil
+	}
+
+	if len(g.History()) == 0 {
+		return piece, nil
+	}
+	return "", fmt.Errorf("%w: %s doesn't touch the hive", ErrInvalidMove, piece)
+}
+
+// ParseMove returns the legal action of the game the notation describes, see MoveString. A move that is well formed
+// but not legal is refused with ErrInvalidMove.
+func ParseMove(g *game.Game, s string) (hive.Action, error) {
+	s = strings.TrimSpace(s)
+	legal := g.LegalActions()
+	if strings.EqualFold(s, PassMove) {
+		for _, a := range legal {
+			if a.WasPassed() {
+				return a, nil
+			}
+		}
+		return hive.Action{}, fmt.Errorf("%w: the player may not pass", ErrInvalidMove)
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return hive.Action{}, fmt.Errorf("%w: %q", ErrInvalidMove, s)
+	}
+	piece, err := ParsePiece(fields[0])
+	if err != nil {
+		return hive.Action{}, err
+	}
+
+	dst, err := destination(g, piece, fields[1:])
+	if err != nil {
+		return hive.Action{}, err
+	}
+
+	// a movement is preferred over a throw when both would take the piece to the destination
+	var thrown *hive.Action
+	for i, a := range legal {
+		if a.Piece() != piece || a.Dst() != dst {
+			continue
+		}
+		if !a.WasThrown() {
+			return a, nil
+		}
+		thrown = &legal[i]
+	}
+	if thrown != nil {
+		return *thrown, nil
+	}
+	return hive.Action{}, fmt.Errorf("%w: %s is not a legal move", ErrInvalidMove, s)
+}
+
+// ParseAction returns the action the notation describes without checking that it's legal, so that playing it reports
+// the rule it breaks. A piece on the board is moved and any other piece is placed. Prefer ParseMove, which also
+// recognizes a Pill Bug throw, unless the rule error is wanted.
+func ParseAction(g *game.Game, s string) (hive.Action, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, PassMove) {
+		return hive.NewAction(hive.Passed, hive.ZeroPiece, 0, 0), nil
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return hive.Action{}, fmt.Errorf("%w: %q", ErrInvalidMove, s)
+	}
+	piece, err := ParsePiece(fields[0])
+	if err != nil {
+		return hive.Action{}, err
+	}
+	dst, err := destination(g, piece, fields[1:])
+	if err != nil {
+		return hive.Action{}, err
+	}
+
+	if src, ok := g.Locate(piece); ok {
+		return hive.NewAction(hive.Moved, piece, src, dst), nil
+	}
+	return hive.NewAction(hive.Placed, piece, 0, dst), nil
+}
+
+// destination works out the coordinate the reference of a move describes.
+func destination(g *game.Game, piece hive.Piece, reference []string) (hive.Coordinate, error) {
+	if len(reference) == 0 {
+		return hive.Origin, nil
+	}
+
+	column, err := ParsePosition(g, reference[0])
+	if err != nil {
+		return hive.Origin, err
+	}
+
+	// the destination is the first free cell of the column, ignoring the moving piece
+	src, _ := g.Locate(piece)
+	for c := column; ; c = c.Add(hive.NeighborsMatrix[hive.Above]) {
+		if p, ok := g.Cell(c); !ok || p == piece && c == src {
+			return c, nil
+		}
+	}
+}
+
+// ParsePosition returns the ground cell a reference to a piece on the board describes, written as the reference of a
+// move, see MoveString. "bQ" is the cell of the black queen, or of the bottom of its stack, and "-bQ" is the cell to
+// its left.
+func ParsePosition(g *game.Game, s string) (hive.Coordinate, error) {
+	ref := s
+	dir := -1
+	for i, indicator := range directionIndicators {
+		if strings.HasPrefix(indicator, " ") && strings.HasPrefix(ref, indicator[1:]) {
+			dir, ref = i, ref[1:]
+			break
+		} else if !strings.HasPrefix(indicator, " ") && strings.HasSuffix(ref, indicator) {
+			dir, ref = i, ref[:len(ref)-1]
+			break
+		}
+	}
+
+	refPiece, err := ParsePiece(ref)
+	if err != nil {
+		return hive.Origin, err
+	}
+	at, ok := g.Locate(refPiece)
+	if !ok {
+		return hive.Origin, fmt.Errorf("%w: %s is not on the board", ErrInvalidMove, ref)
+	}
+
+	column := hive.NewCoordinate(at.X(), at.Y(), at.Z(), 0)
+	if dir >= 0 {
+		column = column.Add(hive.NeighborsMatrix[dir])
+	}
+	return column, nil
+}
+
+// GameTypeString returns the notation of the features of a game, "Base" followed by the letters of the expansion bugs
+// that are enabled, "Base+MLP" for example. Features that aren't expansion bugs aren't part of the game type.
+func GameTypeString(features []game.Feature) string {
+	enabled := make(map[game.Feature]bool)
+	for _, f := range features {
+		enabled[f] = true
+	}
+
+	var expansions string
+	for _, e := range expansionFeatures {
+		if enabled[e.feature] {
+			expansions += string(e.letter)
+		}
+	}
+	if expansions == "" {
+		return baseGameType
+	}
+	return baseGameType + "+" + expansions
+}
+
+// ParseGameType returns the features of the game type, see GameTypeString.
+func ParseGameType(s string) ([]game.Feature, error) {
+	if s == baseGameType {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, baseGameType+"+") || len(s) == len(baseGameType)+1 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGameType, s)
+	}
+
+	var features []game.Feature
+	for _, letter := range s[len(baseGameType)+1:] {
+		found := false
+		for _, e := range expansionFeatures {
+			if e.letter == letter {
+				features = append(features, e.feature)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidGameType, s)
+		}
+	}
+	return features, nil
+}
+
+// HandicapString returns the notation of a handicap, the terms of each player that has any, separated by a semicolon.
+// The terms are the letters of the removed pieces, the number of extra placements, and the turn the queen is due by:
+//
+//	White removed=AAG; Black placements=2 queen=6
+//
+// A game without a handicap is written as an empty string.
+func HandicapString(h game.Handicap) string {
+	var sides []string
+	for _, color := range []uint8{hive.WhiteColor, hive.BlackColor} {
+		odds := h.Of(color)
+		if odds == (game.Odds{}) {
+			continue
+		}
+
+		terms := []string{colorNames[color]}
+		var removed string
+		for _, bug := range hive.DefaultBugs.Bugs() {
+			removed += strings.Repeat(bugLetter(bug), odds.Removed.Count(bug))
+		}
+		if removed != "" {
+			terms = append(terms, handicapRemoved+"="+removed)
+		}
+		if odds.Placements != 0 {
+			terms = append(terms, fmt.Sprintf("%s=%d", handicapPlacements, odds.Placements))
+		}
+		if odds.QueenTurn != 0 {
+			terms = append(terms, fmt.Sprintf("%s=%d", handicapQueen, odds.QueenTurn))
+		}
+		sides = append(sides, strings.Join(terms, " "))
+	}
+	return strings.Join(sides, "; ")
+}
+
+// ParseHandicap returns the handicap of the notation, see HandicapString. Whether the terms can be met is only known
+// once the handicap is applied to a game, see game.ApplyHandicap.
+func ParseHandicap(s string) (game.Handicap, error) {
+	var h game.Handicap
+	if strings.TrimSpace(s) == "" {
+		return h, nil
+	}
+
+	for _, side := range strings.Split(s, ";") {
+		fields := strings.Fields(side)
+		if len(fields) == 0 {
+			return h, fmt.Errorf("%w: %q", ErrInvalidHandicap, s)
+		}
+		var odds *game.Odds
+		switch fields[0] {
+		case colorNames[hive.WhiteColor]:
+			odds = &h.White
+		case colorNames[hive.BlackColor]:
+			odds = &h.Black
+		default:
+			return h, fmt.Errorf("%w: %q is not a player", ErrInvalidHandicap, fields[0])
+		}
+
+		for _, term := range fields[1:] {
+			eq := strings.IndexByte(term, '=')
+			if eq <= 0 {
+				return h, fmt.Errorf("%w: %q is not a term", ErrInvalidHandicap, term)
+			}
+			name, value := term[:eq], term[eq+1:]
+			var err error
+			switch name {
+			case handicapRemoved:
+				odds.Removed, err = parseRemoved(value)
+			case handicapPlacements:
+				odds.Placements, err = strconv.Atoi(value)
+			case handicapQueen:
+				var turn uint64
+				turn, err = strconv.ParseUint(value, 10, 32)
+				odds.QueenTurn = uint(turn)
+			default:
+				err = fmt.Errorf("%w: %q is not a term", ErrInvalidHandicap, name)
+			}
+			if err != nil {
+				return h, fmt.Errorf("%w: %s", ErrInvalidHandicap, err)
+			}
+		}
+	}
+	return h, nil
+}
+
+// InventoryString returns the notation of the inventories the players start with, see game.New. Each player that
+// doesn't have the standard set is written as their color and the letters of their pieces, separated by a semicolon:
+//
+//	White QAAG; Black QSS
+//
+// A game where both players have the standard set is written as an empty string.
+func InventoryString(white, black hive.Inventory) string {
+	inventories := map[uint8]hive.Inventory{hive.WhiteColor: white, hive.BlackColor: black}
+	var sides []string
+	for _, color := range []uint8{hive.WhiteColor, hive.BlackColor} {
+		inv := inventories[color]
+		if inv == hive.StandardInventory {
+			continue
+		}
+		terms := []string{colorNames[color]}
+		var letters string
+		for _, bug := range hive.DefaultBugs.Bugs() {
+			letters += strings.Repeat(bugLetter(bug), inv.Count(bug))
+		}
+		if letters != "" {
+			terms = append(terms, letters)
+		}
+		sides = append(sides, strings.Join(terms, " "))
+	}
+	return strings.Join(sides, "; ")
+}
+
+// ParseInventory returns the inventories of the notation, see InventoryString. A player that isn't in the notation
+// has the standard set.
+func ParseInventory(s string) (white, black hive.Inventory, err error) {
+	white, black = hive.StandardInventory, hive.StandardInventory
+	if strings.TrimSpace(s) == "" {
+		return white, black, nil
+	}
+
+	for _, side := range strings.Split(s, ";") {
+		fields := strings.Fields(side)
+		if len(fields) == 0 || len(fields) > 2 {
+			return white, black, fmt.Errorf("%w: %q", hive.ErrInvalidInventory, s)
+		}
+		var letters string
+		if len(fields) == 2 {
+			letters = fields[1]
+		}
+		inv, err := parseRemoved(letters)
+		if err != nil {
+			return white, black, fmt.Errorf("%w: %s", hive.ErrInvalidInventory, err)
+		}
+		switch fields[0] {
+		case colorNames[hive.WhiteColor]:
+			white = inv
+		case colorNames[hive.BlackColor]:
+			black = inv
+		default:
+			return white, black, fmt.Errorf("%w: %q is not a player", hive.ErrInvalidInventory, fields[0])
+		}
+	}
+	return white, black, nil
+}
+
+// parseRemoved returns the inventory of the letters of the pieces, the removed pieces of a handicap or the pieces of
+// an inventory.
+func parseRemoved(letters string) (hive.Inventory, error) {
+	var inv hive.Inventory
+	for i := 0; i < len(letters); i++ {
+		bug, ok := hive.DefaultBugs.ByLetter(letters[i])
+		if !ok {
+			return inv, fmt.Errorf("%q is not a bug", letters[i])
+		}
+		var err error
+		if inv, err = inv.With(bug, inv.Count(bug)+1); err != nil {
+			return inv, err
+		}
+	}
+	return inv, nil
+}
+
+// GameString returns the notation of the game, the game type, the state, the turn, and the moves played so far, all
+// separated by semicolons:
+//
+//	Base+M;InProgress;Black[2];wS1;bG1 -wS1;wQ wS1/
+//
+// It's what the newgame command of an engine accepts to load a game. Actions that end a game without touching the
+// board, a resignation for example, aren't moves and are left out, the state of the game still reflects them.
+func GameString(g *game.Game) (string, error) {
+	moves, err := Moves(g)
+	if err != nil {
+		return "", err
+	}
+
+	turn := "White"
+	if g.Turn() == hive.BlackColor {
+		turn = "Black"
+	}
+	parts := []string{GameTypeString(g.Features()), gameState(g), fmt.Sprintf("%s[%d]", turn, g.Turns())}
+	return strings.Join(append(parts, moves...), ";"), nil
+}
+
+// ParseGame builds the game a game string describes by playing its moves on a new game, see GameString. The game
+// type and the moves are all that's read, the state and the turn follow from the moves.
+func ParseGame(s string) (*game.Game, error) {
+	parts := strings.Split(s, ";")
+	features, err := ParseGameType(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) > 1 && len(parts) < 3 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGameString, s)
+	}
+
+	g := game.New(features)
+	if len(parts) > 3 {
+		if err := PlayMoves(g, parts[3:]); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Moves returns the notation of every move of the game, in order.
+func Moves(g *game.Game) ([]string, error) {
+	replay := g.Initial()
+	var moves []string
+	for _, a := range g.History() {
+		if a.Concludes() {
+			continue
+		}
+		m, err := MoveString(replay, a)
+		if err != nil {
+			return nil, err
+		}
+		if err := replay.Play(a); err != nil {
+			return nil, err
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+// PlayMoves parses and plays the moves on the game one after the other.
+func PlayMoves(g *game.Game, moves []string) error {
+	for i, m := range moves {
+		a, err := ParseMove(g, m)
+		if err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+		if err := g.Play(a); err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ClockString returns the time each player has left on the clocks, written in the time format of the protocol like the
+// turn of a game string:
+//
+//	White[00:04:52];Black[00:05:00]
+//
+// The protocol counts time in whole seconds, what's left of a second isn't written.
+func ClockString(cs game.ClockState) string {
+	return fmt.Sprintf("White[%s];Black[%s]", timeString(cs.White), timeString(cs.Black))
+}
+
+// timeString returns the duration in the time format of the protocol, hh:mm:ss.
+func timeString(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+func gameState(g *game.Game) string {
+	if len(g.History()) == 0 {
+		return "NotStarted"
+	}
+	switch w, err := g.Winner(); {
+	case err != nil:
+		return "InProgress"
+	case w == game.WhitePlayer:
+		return "WhiteWins"
+	case w == game.BlackPlayer:
+		return "BlackWins"
+	}
+	return "Draw"
+}
+
+// numbered returns true for the bugs a player may have more than one of.
+func numbered(bug uint8) bool {
+	return hive.MaxPieces(bug) > 1
+}
+
+// bugLetter returns the letter of the bug, see hive.BugDefinition.
+func bugLetter(bug uint8) string {
+	if def, ok := hive.DefaultBugs.Lookup(bug); ok {
+		return string(def.Letter)
+	}
+	return "?"
+}
+
+// directionIndicators is indexed by the direction, see hive.NeighborsMatrix, and describes the neighbor in that
+// direction. The protocol draws the hexagons with a point at the top, so the directions of the board are turned a
+// sixth to the right. An indicator starting with a space is written in front of the reference piece.
+var directionIndicators = [6]string{
+	"/",   // North, to the top right
+	"-",   // Northeast, to the right
+	"\\",  // Southeast, to the bottom right
+	" /",  // South, to the bottom left
+	" -",  // Southwest, to the left
+	" \\", // Northwest, to the top left
+}
+
+var expansionFeatures = []struct {
+	letter  rune
+	feature game.Feature
+}{
+	{'M', game.MosquitoPieceFeature},
+	{'L', game.LadybugPieceFeature},
+	{'P', game.PillBugPieceFeature},
+}
+
+const (
+	// PassMove is the notation of a pass.
+	PassMove = "pass"
+
+	baseGameType = "Base"
+
+	handicapRemoved    = "removed"
+	handicapPlacements = "placements"
+	handicapQueen      = "queen"
+)
+
+// colorNames is indexed by color.
+var colorNames = [...]string{"", "Black", "White"}
+
+var ErrInvalidPiece = fmt.Errorf("the piece notation is invalid")
+var ErrInvalidMove = fmt.Errorf("the move is invalid")
+var ErrNotAMove = fmt.Errorf("the action is not a move")
+var ErrInvalidGameType = fmt.Errorf("the game type is invalid")
+var ErrInvalidGameString = fmt.Errorf("the game string is invalid")
+var ErrInvalidHandicap = fmt.Errorf("the handicap is invalid")
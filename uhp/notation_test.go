@@ -0,0 +1,220 @@
+package uhp
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/theshadow/hive"
+	"github.com/theshadow/hive/game"
+)
+
+func TestMoveString(t *testing.T) {
+	t.Run("When moves are written and parsed the same actions are returned", func(t *testing.T) {
+		features := []game.Feature{game.MosquitoPieceFeature, game.LadybugPieceFeature, game.PillBugPieceFeature}
+		for seed := int64(1); seed <= 5; seed++ {
+			rng := rand.New(rand.NewSource(seed))
+			g := game.New(features)
+			for ply := 0; ply < 60 && !g.Over(); ply++ {
+				legal := g.LegalActions()
+				a := legal[rng.Intn(len(legal))]
+
+				m, err := MoveString(g, a)
+				if err != nil {
+					t.Fatalf("Unexpected error %#v while writing %s", err, a)
+				}
+				parsed, err := ParseMove(g, m)
+				if err != nil {
+					t.Fatalf("Unexpected error %#v while parsing %q", err, m)
+				}
+				if parsed.Piece() != a.Piece() || parsed.Dst() != a.Dst() {
+					t.Fatalf("Expected %q to be parsed as %s instead received %s", m, a, parsed)
+				}
+				if err := g.Play(a); err != nil {
+					t.Fatalf("Unexpected error %#v while playing %s", err, a)
+				}
+			}
+		}
+	})
+
+	t.Run("When a move is written it uses the notation of the protocol", func(t *testing.T) {
+		g, err := ParseGame("Base;InProgress;White[2];wS1;bG1 -wS1")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while parsing the game", err)
+		}
+
+		a, err := ParseMove(g, "wQ wS1/")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while parsing the move", err)
+		}
+		if m, _ := MoveString(g, a); m != "wQ wS1/" {
+			t.Errorf("Expected the move %q instead received %q", "wQ wS1/", m)
+		}
+		if a.Dst() != hive.NewCoordinate(0, 1, -1, 0) {
+			t.Errorf("Expected the queen to be placed north of the spider instead received %s", a.Dst())
+		}
+	})
+
+	t.Run("When a move is not legal an error is returned", func(t *testing.T) {
+		g, _ := ParseGame("Base;InProgress;White[2];wS1;bG1 -wS1")
+		if _, err := ParseMove(g, "wQ -bG1"); !errors.Is(err, ErrInvalidMove) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidMove, err)
+		}
+	})
+}
+
+func TestParseAction(t *testing.T) {
+	t.Run("When an illegal move is played the rule it breaks is reported", func(t *testing.T) {
+		g, _ := ParseGame("Base;InProgress;White[2];wS1;bG1 -wS1")
+		a, err := ParseAction(g, "wQ -bG1")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while parsing the action", err)
+		}
+		if !a.WasPlaced() {
+			t.Errorf("Expected a placement instead received %s", a)
+		}
+		if err := g.Play(a); !errors.Is(err, game.ErrRuleMayNotPlaceTouchingOpponentsPiece) {
+			t.Errorf("Expected an error of type %#v instead received %#v", game.ErrRuleMayNotPlaceTouchingOpponentsPiece, err)
+		}
+	})
+
+	t.Run("When a piece on the board is named it is moved", func(t *testing.T) {
+		g, _ := ParseGame("Base;InProgress;White[3];wS1;bG1 -wS1;wQ wS1-;bQ -bG1")
+		a, err := ParseAction(g, "wQ bG1\\")
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while parsing the action", err)
+		}
+		if src, _ := g.Locate(a.Piece()); !a.WasMoved() || a.Src() != src {
+			t.Errorf("Expected the queen to be moved instead received %s", a)
+		}
+	})
+}
+
+func TestParsePosition(t *testing.T) {
+	t.Run("When a position is relative to a piece it names the neighboring cell", func(t *testing.T) {
+		g, _ := ParseGame("Base;InProgress;White[2];wS1;bG1 -wS1")
+		grasshopper, _ := g.Locate(hive.NewPiece(hive.BlackColor, hive.Grasshopper, hive.PieceA))
+		for _, s := range []string{"-wS1", "bG1"} {
+			if c, err := ParsePosition(g, s); err != nil || c != grasshopper {
+				t.Errorf("Expected %s to name %s instead received %s, %#v", s, grasshopper, c, err)
+			}
+		}
+	})
+
+	t.Run("When the piece isn't on the board an error is returned", func(t *testing.T) {
+		g, _ := ParseGame("Base;InProgress;White[2];wS1;bG1 -wS1")
+		if _, err := ParsePosition(g, "wA1/"); !errors.Is(err, ErrInvalidMove) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidMove, err)
+		}
+	})
+}
+
+func TestHandicapString(t *testing.T) {
+	t.Run("When a handicap is written and parsed the same handicap is returned", func(t *testing.T) {
+		removed, _ := hive.NewInventory(map[uint8]int{hive.Ant: 2, hive.Grasshopper: 1})
+		h := game.Handicap{White: game.Odds{Removed: removed}, Black: game.Odds{Placements: 2, QueenTurn: 6}}
+		s := HandicapString(h)
+		if want := "White removed=GAA; Black placements=2 queen=6"; s != want {
+			t.Errorf("Expected %q instead received %q", want, s)
+		}
+		if parsed, err := ParseHandicap(s); err != nil || parsed != h {
+			t.Errorf("Expected %v instead received %v, %#v", h, parsed, err)
+		}
+		if HandicapString(game.Handicap{}) != "" {
+			t.Error("Expected a game without a handicap to be written as an empty string")
+		}
+	})
+
+	t.Run("When a handicap is malformed an error is returned", func(t *testing.T) {
+		for _, s := range []string{"Red placements=1", "White removed=X", "White queen", "Black placements=x"} {
+			if _, err := ParseHandicap(s); !errors.Is(err, ErrInvalidHandicap) {
+				t.Errorf("Expected an error of type %#v for %q instead received %#v", ErrInvalidHandicap, s, err)
+			}
+		}
+	})
+}
+
+func TestInventoryString(t *testing.T) {
+	t.Run("When the inventories are written and parsed the same inventories are returned", func(t *testing.T) {
+		white, _ := hive.NewInventory(map[uint8]int{hive.Queen: 1, hive.Ant: 2, hive.Grasshopper: 1})
+		s := InventoryString(white, hive.StandardInventory)
+		if want := "White QGAA"; s != want {
+			t.Errorf("Expected %q instead received %q", want, s)
+		}
+		parsedWhite, parsedBlack, err := ParseInventory(s)
+		if err != nil || parsedWhite != white || parsedBlack != hive.StandardInventory {
+			t.Errorf("Expected %v and the standard set instead received %v, %v, %#v", white, parsedWhite, parsedBlack,
+				err)
+		}
+		if InventoryString(hive.StandardInventory, hive.StandardInventory) != "" {
+			t.Error("Expected the standard sets to be written as an empty string")
+		}
+	})
+
+	t.Run("When the inventories are malformed an error is returned", func(t *testing.T) {
+		for _, s := range []string{"Red Q", "White QX", "White QQ", "Black Q A"} {
+			if _, _, err := ParseInventory(s); !errors.Is(err, hive.ErrInvalidInventory) {
+				t.Errorf("Expected an error of type %#v for %q instead received %#v", hive.ErrInvalidInventory, s, err)
+			}
+		}
+	})
+}
+
+func TestClockString(t *testing.T) {
+	t.Run("When the clocks of a timed game are written they use the time format of the protocol", func(t *testing.T) {
+		clock := game.NewManualClock(time.Unix(0, 0))
+		g := game.New(nil)
+		if err := g.StartClock(game.Fischer(5*time.Minute, 2*time.Second), clock); err != nil {
+			t.Fatalf("Unexpected error %#v while starting the clock", err)
+		}
+		clock.Advance(10 * time.Second)
+		if err := PlayMoves(g, []string{"wS1"}); err != nil {
+			t.Fatalf("Unexpected error %#v while playing the moves", err)
+		}
+		clock.Advance(1500 * time.Millisecond)
+
+		cs, _ := g.Clock()
+		if s, want := ClockString(cs), "White[00:04:52];Black[00:04:58]"; s != want {
+			t.Errorf("Expected %q instead received %q", want, s)
+		}
+	})
+
+	t.Run("When a player has over an hour left the hours are written", func(t *testing.T) {
+		cs := game.ClockState{White: 90 * time.Minute, Black: time.Hour + 59*time.Second}
+		if s, want := ClockString(cs), "White[01:30:00];Black[01:00:59]"; s != want {
+			t.Errorf("Expected %q instead received %q", want, s)
+		}
+	})
+}
+
+func TestGameString(t *testing.T) {
+	t.Run("When a game is written and parsed the same game is returned", func(t *testing.T) {
+		g := game.New([]game.Feature{game.MosquitoPieceFeature})
+		rng := rand.New(rand.NewSource(3))
+		for i := 0; i < 12; i++ {
+			legal := g.LegalActions()
+			if err := g.Play(legal[rng.Intn(len(legal))]); err != nil {
+				t.Fatalf("Unexpected error %#v while playing", err)
+			}
+		}
+
+		gs, err := GameString(g)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while writing the game", err)
+		}
+		parsed, err := ParseGame(gs)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v while parsing %q", err, gs)
+		}
+		if parsed.Hash() != g.Hash() || GameTypeString(parsed.Features()) != "Base+M" {
+			t.Errorf("Expected %q to describe the same game", gs)
+		}
+	})
+
+	t.Run("When a game type is unknown an error is returned", func(t *testing.T) {
+		if _, err := ParseGameType("Base+X"); !errors.Is(err, ErrInvalidGameType) {
+			t.Errorf("Expected an error of type %#v instead received %#v", ErrInvalidGameType, err)
+		}
+	})
+}